@@ -50,8 +50,9 @@ type ListAccessKeysInput struct{}
 func (c *AccessKeysClient) ListAccessKeys(ctx context.Context, _ *ListAccessKeysInput) ([]*AccessKey, error) {
 	fullPath := path.Join("/", c.client.AccountName, "accesskeys")
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "account.access_keys.list_access_keys",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -81,8 +82,9 @@ type GetAccessKeyInput struct {
 func (c *AccessKeysClient) GetAccessKey(ctx context.Context, input *GetAccessKeyInput) (*AccessKey, error) {
 	fullPath := path.Join("/", c.client.AccountName, "accesskeys", input.AccessKeyID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "account.access_keys.get_access_key",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -111,8 +113,9 @@ type DeleteAccessKeyInput struct {
 func (c *AccessKeysClient) DeleteAccessKey(ctx context.Context, input *DeleteAccessKeyInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "accesskeys", input.AccessKeyID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "account.access_keys.delete_access_key",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -132,9 +135,10 @@ type CreateAccessKeyInput struct{}
 func (c *AccessKeysClient) CreateAccessKey(ctx context.Context, input *CreateAccessKeyInput) (*AccessKey, error) {
 	fullPath := path.Join("/", c.client.AccountName, "accesskeys")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "account.access_keys.create_access_key",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
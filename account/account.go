@@ -42,8 +42,9 @@ type GetInput struct{}
 func (c AccountClient) Get(ctx context.Context, input *GetInput) (*Account, error) {
 	fullPath := path.Join("/", c.Client.AccountName)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "account.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.Client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -81,9 +82,10 @@ type UpdateInput struct {
 func (c AccountClient) Update(ctx context.Context, input *UpdateInput) (*Account, error) {
 	fullPath := path.Join("/", c.Client.AccountName)
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "account.update",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.Client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
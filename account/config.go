@@ -34,8 +34,9 @@ type GetConfigInput struct{}
 func (c *ConfigClient) Get(ctx context.Context, input *GetConfigInput) (*Config, error) {
 	fullPath := path.Join("/", c.client.AccountName, "config")
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "account.config.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -63,9 +64,10 @@ type UpdateConfigInput struct {
 func (c *ConfigClient) Update(ctx context.Context, input *UpdateConfigInput) (*Config, error) {
 	fullPath := path.Join("/", c.client.AccountName, "config")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "account.config.update",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -41,8 +41,9 @@ type ListKeysInput struct{}
 func (c *KeysClient) List(ctx context.Context, _ *ListKeysInput) ([]*Key, error) {
 	fullPath := path.Join("/", c.client.AccountName, "keys")
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "account.keys.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -68,8 +69,9 @@ type GetKeyInput struct {
 func (c *KeysClient) Get(ctx context.Context, input *GetKeyInput) (*Key, error) {
 	fullPath := path.Join("/", c.client.AccountName, "keys", input.KeyName)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "account.keys.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -95,8 +97,9 @@ type DeleteKeyInput struct {
 func (c *KeysClient) Delete(ctx context.Context, input *DeleteKeyInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "keys", input.KeyName)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "account.keys.delete",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -123,9 +126,10 @@ type CreateKeyInput struct {
 func (c *KeysClient) Create(ctx context.Context, input *CreateKeyInput) (*Key, error) {
 	fullPath := path.Join("/", c.client.AccountName, "keys")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "account.keys.create",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -11,13 +11,13 @@ package authentication
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/asn1"
 	"encoding/base64"
 	"fmt"
 	"math/big"
-	"strings"
 
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/ssh"
@@ -46,25 +46,39 @@ type PrivateKeySignerInput struct {
 	PrivateKeyMaterial []byte
 	AccountName        string
 	Username           string
+
+	// Passphrase decrypts PrivateKeyMaterial when it is encrypted, either as
+	// a legacy "Proc-Type: 4,ENCRYPTED" PEM block or as a new format OpenSSH
+	// key.
+	Passphrase []byte
+
+	// PassphraseFunc is called to obtain the passphrase when
+	// PrivateKeyMaterial turns out to be encrypted and Passphrase is empty,
+	// e.g. to prompt the user for it.
+	PassphraseFunc func() ([]byte, error)
 }
 
 func NewPrivateKeySigner(input PrivateKeySignerInput) (*PrivateKeySigner, error) {
-	keyFingerprintMD5 := strings.Replace(input.KeyID, ":", "", -1)
-
-	key, err := ssh.ParseRawPrivateKey(input.PrivateKeyMaterial)
+	key, err := parsePrivateKey(input)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to parse private key")
 	}
 
-	matchKeyFingerprint, err := formatPublicKeyFingerprint(key, false)
+	// OpenSSH formatted Ed25519 keys are parsed into a pointer, unlike PKCS#8
+	// ones.
+	if edKey, ok := key.(*ed25519.PrivateKey); ok {
+		key = *edKey
+	}
+
+	publicKey, err := sshPublicKey(key)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to format match public key")
+		return nil, errors.Wrap(err, "unable to read public key")
 	}
 	displayKeyFingerprint, err := formatPublicKeyFingerprint(key, true)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to format display public key")
 	}
-	if matchKeyFingerprint != keyFingerprintMD5 {
+	if !matchesKeyFingerprint(publicKey, input.KeyID) {
 		return nil, errors.New("Private key file does not match public key fingerprint")
 	}
 
@@ -83,39 +97,47 @@ func NewPrivateKeySigner(input PrivateKeySignerInput) (*PrivateKeySigner, error)
 
 	_, algorithm, err := signer.SignRaw("HelloWorld")
 	if err != nil {
-		return nil, fmt.Errorf("Cannot sign using ssh agent: %s", err)
+		return nil, fmt.Errorf("Cannot sign using private key: %s", err)
 	}
 	signer.algorithm = algorithm
 
 	return signer, nil
 }
 
+// parsePrivateKey parses the private key material of input, decrypting it with
+// the passphrase given or obtained from input when required.
+func parsePrivateKey(input PrivateKeySignerInput) (interface{}, error) {
+	if len(input.Passphrase) > 0 {
+		return ssh.ParseRawPrivateKeyWithPassphrase(input.PrivateKeyMaterial, input.Passphrase)
+	}
+
+	key, err := ssh.ParseRawPrivateKey(input.PrivateKeyMaterial)
+	if _, ok := err.(*ssh.PassphraseMissingError); !ok || input.PassphraseFunc == nil {
+		return key, err
+	}
+
+	passphrase, err := input.PassphraseFunc()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read passphrase")
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("private key is passphrase protected but no passphrase was given")
+	}
+
+	return ssh.ParseRawPrivateKeyWithPassphrase(input.PrivateKeyMaterial, passphrase)
+}
+
 func (s *PrivateKeySigner) Sign(dateHeader string, isManta bool) (string, error) {
-	const headerName = "date"
+	return s.SignHeaders([]SignedHeader{{Name: "date", Value: dateHeader}}, isManta)
+}
 
-	hash := s.hashFunc.New()
-	hash.Write([]byte(fmt.Sprintf("%s: %s", headerName, dateHeader)))
-	digest := hash.Sum(nil)
+// SignHeaders implements interface HeadersSigner on the PrivateKeySigner type.
+func (s *PrivateKeySigner) SignHeaders(headers []SignedHeader, isManta bool) (string, error) {
+	toSign, headerNames := signingString(headers)
 
-	var algoName string
-	var signedBase64 string
-	switch s.privateKey.(type) {
-	case *rsa.PrivateKey:
-		algoName = RSA_SHA512
-		signed, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey.(*rsa.PrivateKey), s.hashFunc, digest)
-		if err != nil {
-			return "", errors.Wrap(err, "unable to sign date header")
-		}
-		signedBase64 = base64.StdEncoding.EncodeToString(signed)
-	case *ecdsa.PrivateKey:
-		algoName = ECDSA_SHA512
-		r, s, err := ecdsa.Sign(rand.Reader, s.privateKey.(*ecdsa.PrivateKey), digest)
-		if err != nil {
-			return "", errors.Wrap(err, "unable to sign date header")
-		}
-		signature := ECDSASignature{R: r, S: s}
-		signed, err := asn1.Marshal(signature)
-		signedBase64 = base64.StdEncoding.EncodeToString(signed)
+	signedBase64, algoName, err := s.sign([]byte(toSign))
+	if err != nil {
+		return "", errors.Wrap(err, "unable to sign headers")
 	}
 
 	key := &KeyID{
@@ -125,37 +147,49 @@ func (s *PrivateKeySigner) Sign(dateHeader string, isManta bool) (string, error)
 		IsManta:     isManta,
 	}
 
-	return fmt.Sprintf(authorizationHeaderFormat, key.generate(), algoName, headerName, signedBase64), nil
+	return fmt.Sprintf(authorizationHeaderFormat, key.generate(), algoName, headerNames, signedBase64), nil
 }
 
 func (s *PrivateKeySigner) SignRaw(toSign string) (string, string, error) {
-	hash := s.hashFunc.New()
-	hash.Write([]byte(toSign))
-	digest := hash.Sum(nil)
+	signedBase64, algoName, err := s.sign([]byte(toSign))
+	if err != nil {
+		return "", "", errors.Wrap(err, "unable to sign string")
+	}
 
-	var algoName string
-	var signedBase64 string
-	switch s.privateKey.(type) {
+	return signedBase64, algoName, nil
+}
+
+// sign signs data with the private key of the signer, returning the base64
+// encoded signature along with the name of the HTTP signature algorithm used.
+func (s *PrivateKeySigner) sign(data []byte) (string, string, error) {
+	switch key := s.privateKey.(type) {
 	case *rsa.PrivateKey:
-		algoName = RSA_SHA512
-		signed, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey.(*rsa.PrivateKey), s.hashFunc, digest)
+		hash := s.hashFunc.New()
+		hash.Write(data)
+		signed, err := rsa.SignPKCS1v15(rand.Reader, key, s.hashFunc, hash.Sum(nil))
 		if err != nil {
-			return "", "", errors.Wrap(err, "unable to sign date header")
+			return "", "", err
 		}
-		signedBase64 = base64.StdEncoding.EncodeToString(signed)
+		return base64.StdEncoding.EncodeToString(signed), RSA_SHA512, nil
 	case *ecdsa.PrivateKey:
-		algoName = ECDSA_SHA512
-		r, s, err := ecdsa.Sign(rand.Reader, s.privateKey.(*ecdsa.PrivateKey), digest)
+		hash := s.hashFunc.New()
+		hash.Write(data)
+		r, s, err := ecdsa.Sign(rand.Reader, key, hash.Sum(nil))
 		if err != nil {
-			return "", "", errors.Wrap(err, "unable to sign date header")
+			return "", "", err
 		}
-		signature := ECDSASignature{R: r, S: s}
-		signed, err := asn1.Marshal(signature)
-		signedBase64 = base64.StdEncoding.EncodeToString(signed)
-
+		signed, err := asn1.Marshal(ECDSASignature{R: r, S: s})
+		if err != nil {
+			return "", "", err
+		}
+		return base64.StdEncoding.EncodeToString(signed), ECDSA_SHA512, nil
+	case ed25519.PrivateKey:
+		// Ed25519 hashes the message with SHA-512 itself.
+		signed := ed25519.Sign(key, data)
+		return base64.StdEncoding.EncodeToString(signed), ED25519_SHA512, nil
+	default:
+		return "", "", fmt.Errorf("unsupported private key type %T", s.privateKey)
 	}
-
-	return signedBase64, algoName, nil
 }
 
 type ECDSASignature struct {
@@ -0,0 +1,255 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package authentication
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	testAccountName = "test.user"
+	testDateHeader  = "Mon, 06 Jul 2020 12:00:00 UTC"
+
+	// Fingerprints as printed by ssh-keygen -l -E md5.
+	ed25519OpenSSHFingerprint = "90:c9:66:69:6b:47:58:12:21:ac:38:ca:e7:c5:7f:4c"
+	ed25519PKCS8Fingerprint   = "56:40:fa:fb:ce:a7:77:3a:69:9f:5d:b1:91:43:5b:96"
+
+	// Fingerprint as printed by ssh-keygen -l -E sha256.
+	ed25519OpenSSHFingerprintSHA256 = "SHA256:heItPKTcrMFm73hKbihkPe83wrKPA7cRZsN/F4hqfWo"
+)
+
+var authorizationRegexp = regexp.MustCompile(`^Signature keyId="([^"]*)",algorithm="([^"]*)",headers="([^"]*)",signature="([^"]*)"$`)
+
+func readTestKey(t *testing.T, name string) []byte {
+	key, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// parseAuthorization returns the keyId, algorithm, headers and signature of
+// an Authorization header.
+func parseAuthorization(t *testing.T, header string) (string, string, string, []byte) {
+	matches := authorizationRegexp.FindStringSubmatch(header)
+	if matches == nil {
+		t.Fatalf("unexpected Authorization header %q", header)
+	}
+	signature, err := base64.StdEncoding.DecodeString(matches[4])
+	if err != nil {
+		t.Fatalf("unable to decode signature: %v", err)
+	}
+	return matches[1], matches[2], matches[3], signature
+}
+
+func TestEd25519PrivateKeySigner(t *testing.T) {
+	keys := []struct {
+		name        string
+		file        string
+		fingerprint string
+	}{
+		{"openssh", "ed25519_openssh", ed25519OpenSSHFingerprint},
+		{"pkcs8", "ed25519_pkcs8.pem", ed25519PKCS8Fingerprint},
+	}
+
+	for _, key := range keys {
+		t.Run(key.name, func(t *testing.T) {
+			signer, err := NewPrivateKeySigner(PrivateKeySignerInput{
+				KeyID:              key.fingerprint,
+				PrivateKeyMaterial: readTestKey(t, key.file),
+				AccountName:        testAccountName,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if signer.KeyFingerprint() != key.fingerprint {
+				t.Errorf("expected fingerprint %s: got %s", key.fingerprint, signer.KeyFingerprint())
+			}
+			if signer.DefaultAlgorithm() != ED25519_SHA512 {
+				t.Errorf("expected algorithm %s: got %s", ED25519_SHA512, signer.DefaultAlgorithm())
+			}
+
+			header, err := signer.Sign(testDateHeader, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			keyID, algorithm, headers, signature := parseAuthorization(t, header)
+			if keyID != "/"+testAccountName+"/keys/"+key.fingerprint {
+				t.Errorf("unexpected keyId %q", keyID)
+			}
+			if algorithm != ED25519_SHA512 || headers != "date" {
+				t.Errorf("unexpected algorithm %q or headers %q", algorithm, headers)
+			}
+
+			public := signer.privateKey.(ed25519.PrivateKey).Public().(ed25519.PublicKey)
+			if !ed25519.Verify(public, []byte("date: "+testDateHeader), signature) {
+				t.Error("expected signature to verify")
+			}
+		})
+	}
+
+	t.Run("sha256 fingerprint", func(t *testing.T) {
+		signer, err := NewPrivateKeySigner(PrivateKeySignerInput{
+			KeyID:              ed25519OpenSSHFingerprintSHA256,
+			PrivateKeyMaterial: readTestKey(t, "ed25519_openssh"),
+			AccountName:        testAccountName,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		header, err := signer.Sign(testDateHeader, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keyID, _, _, _ := parseAuthorization(t, header); keyID != "/"+testAccountName+"/keys/"+ed25519OpenSSHFingerprint {
+			t.Errorf("expected the MD5 fingerprint in keyId: got %q", keyID)
+		}
+	})
+
+	t.Run("mismatched fingerprint", func(t *testing.T) {
+		_, err := NewPrivateKeySigner(PrivateKeySignerInput{
+			KeyID:              ed25519PKCS8Fingerprint,
+			PrivateKeyMaterial: readTestKey(t, "ed25519_openssh"),
+			AccountName:        testAccountName,
+		})
+		if err == nil || !strings.Contains(err.Error(), "does not match public key fingerprint") {
+			t.Errorf("expected fingerprint mismatch: got %v", err)
+		}
+	})
+}
+
+func TestUnsupportedPrivateKey(t *testing.T) {
+	material := readTestKey(t, "dsa.pem")
+
+	_, err := NewPrivateKeySigner(PrivateKeySignerInput{
+		KeyID:              ed25519OpenSSHFingerprint,
+		PrivateKeyMaterial: material,
+		AccountName:        testAccountName,
+	})
+	if err == nil || !strings.Contains(err.Error(), "private key of type *dsa.PrivateKey") {
+		t.Errorf("expected unsupported key error: got %v", err)
+	}
+
+	key, err := parsePrivateKey(PrivateKeySignerInput{PrivateKeyMaterial: material})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := &PrivateKeySigner{privateKey: key}
+	if _, _, err := signer.SignRaw("HelloWorld"); err == nil || !strings.Contains(err.Error(), "unsupported private key type *dsa.PrivateKey") {
+		t.Errorf("expected unsupported key error: got %v", err)
+	}
+}
+
+func TestEncryptedPrivateKeySigner(t *testing.T) {
+	const passphrase = "triton-go"
+
+	keys := []struct {
+		name        string
+		file        string
+		fingerprint string
+	}{
+		// Fingerprints as printed by ssh-keygen -l -E md5.
+		{"pkcs1", "rsa_encrypted.pem", "85:1c:23:52:bd:37:c3:cc:a9:ed:e1:ff:0f:a2:b2:5c"},
+		{"openssh", "ed25519_openssh_encrypted", "b9:91:80:14:65:d8:34:41:9d:50:bc:40:dc:16:b6:d8"},
+	}
+
+	for _, key := range keys {
+		newSigner := func(t *testing.T, input PrivateKeySignerInput) (*PrivateKeySigner, error) {
+			input.KeyID = key.fingerprint
+			input.PrivateKeyMaterial = readTestKey(t, key.file)
+			input.AccountName = testAccountName
+			return NewPrivateKeySigner(input)
+		}
+
+		t.Run(key.name, func(t *testing.T) {
+			t.Run("passphrase", func(t *testing.T) {
+				signer, err := newSigner(t, PrivateKeySignerInput{
+					Passphrase: []byte(passphrase),
+					PassphraseFunc: func() ([]byte, error) {
+						t.Error("expected PassphraseFunc not to be called")
+						return nil, nil
+					},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if signer.KeyFingerprint() != key.fingerprint {
+					t.Errorf("expected fingerprint %s: got %s", key.fingerprint, signer.KeyFingerprint())
+				}
+			})
+
+			t.Run("passphrase func", func(t *testing.T) {
+				calls := 0
+				_, err := newSigner(t, PrivateKeySignerInput{
+					PassphraseFunc: func() ([]byte, error) {
+						calls++
+						return []byte(passphrase), nil
+					},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if calls != 1 {
+					t.Errorf("expected PassphraseFunc to be called once: got %d", calls)
+				}
+			})
+
+			t.Run("wrong passphrase", func(t *testing.T) {
+				_, err := newSigner(t, PrivateKeySignerInput{
+					PassphraseFunc: func() ([]byte, error) {
+						return []byte("wrong"), nil
+					},
+				})
+				if err == nil || !strings.Contains(err.Error(), "decryption password incorrect") {
+					t.Errorf("expected incorrect passphrase error: got %v", err)
+				}
+			})
+
+			t.Run("no passphrase", func(t *testing.T) {
+				_, err := newSigner(t, PrivateKeySignerInput{})
+				if _, ok := errors.Cause(err).(*ssh.PassphraseMissingError); !ok {
+					t.Errorf("expected passphrase missing error: got %v", err)
+				}
+			})
+
+			t.Run("empty passphrase func", func(t *testing.T) {
+				_, err := newSigner(t, PrivateKeySignerInput{
+					PassphraseFunc: func() ([]byte, error) {
+						return nil, nil
+					},
+				})
+				if err == nil || !strings.Contains(err.Error(), "no passphrase was given") {
+					t.Errorf("expected missing passphrase error: got %v", err)
+				}
+			})
+
+			t.Run("passphrase func error", func(t *testing.T) {
+				_, err := newSigner(t, PrivateKeySignerInput{
+					PassphraseFunc: func() ([]byte, error) {
+						return nil, errors.New("no terminal")
+					},
+				})
+				if err == nil || !strings.Contains(err.Error(), "unable to read passphrase: no terminal") {
+					t.Errorf("expected passphrase func error: got %v", err)
+				}
+			})
+		})
+	}
+}
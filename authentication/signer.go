@@ -8,11 +8,48 @@
 
 package authentication
 
+import (
+	"strings"
+)
+
 const authorizationHeaderFormat = `Signature keyId="%s",algorithm="%s",headers="%s",signature="%s"`
 
+// RequestTarget is the pseudo-header binding the method and path of a request
+// to its signature. Its value is the lower case method and the request URI,
+// e.g. "get /my/machines?limit=10".
+const RequestTarget = "(request-target)"
+
 type Signer interface {
 	DefaultAlgorithm() string
 	KeyFingerprint() string
 	Sign(dateHeader string, isManta bool) (string, error)
 	SignRaw(toSign string) (string, string, error)
 }
+
+// HeadersSigner is implemented by signers which can sign an arbitrary,
+// ordered list of headers rather than only the date header. Signing just the
+// date header with SignHeaders is equivalent to calling Sign.
+type HeadersSigner interface {
+	Signer
+	SignHeaders(headers []SignedHeader, isManta bool) (string, error)
+}
+
+// SignedHeader is a header covered by an HTTP signature.
+type SignedHeader struct {
+	Name  string
+	Value string
+}
+
+// signingString returns the string to sign for headers along with the value
+// of the headers parameter of the Authorization header.
+func signingString(headers []SignedHeader) (string, string) {
+	lines := make([]string, 0, len(headers))
+	names := make([]string, 0, len(headers))
+	for _, header := range headers {
+		name := strings.ToLower(header.Name)
+		lines = append(lines, name+": "+header.Value)
+		names = append(names, name)
+	}
+
+	return strings.Join(lines, "\n"), strings.Join(names, " ")
+}
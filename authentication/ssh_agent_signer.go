@@ -9,13 +9,9 @@
 package authentication
 
 import (
-	"crypto/md5"
-	"crypto/sha256"
-	"encoding/base64"
 	"fmt"
 	"net"
 	"os"
-	"strings"
 
 	"github.com/pkg/errors"
 	pkgerrors "github.com/pkg/errors"
@@ -56,8 +52,12 @@ func NewSSHAgentSigner(input SSHAgentSignerInput) (*SSHAgentSigner, error) {
 		return nil, pkgerrors.Wrap(err, "unable to dial SSH agent")
 	}
 
-	ag := agent.NewClient(conn)
+	return newSSHAgentSigner(agent.NewClient(conn), input)
+}
 
+// newSSHAgentSigner returns a signer using the key of ag matching the key ID
+// of input.
+func newSSHAgentSigner(ag agent.Agent, input SSHAgentSignerInput) (*SSHAgentSigner, error) {
 	signer := &SSHAgentSigner{
 		keyFingerprint: input.KeyID,
 		accountName:    input.AccountName,
@@ -93,21 +93,9 @@ func (s *SSHAgentSigner) MatchKey() (ssh.PublicKey, error) {
 		return nil, pkgerrors.Wrap(err, "unable to list keys in SSH Agent")
 	}
 
-	keyFingerprintStripped := strings.TrimPrefix(s.keyFingerprint, "MD5:")
-	keyFingerprintStripped = strings.TrimPrefix(keyFingerprintStripped, "SHA256:")
-	keyFingerprintStripped = strings.Replace(keyFingerprintStripped, ":", "", -1)
-
 	var matchingKey ssh.PublicKey
 	for _, key := range keys {
-		keyMD5 := md5.New()
-		keyMD5.Write(key.Marshal())
-		finalizedMD5 := fmt.Sprintf("%x", keyMD5.Sum(nil))
-
-		keySHA256 := sha256.New()
-		keySHA256.Write(key.Marshal())
-		finalizedSHA256 := base64.RawStdEncoding.EncodeToString(keySHA256.Sum(nil))
-
-		if keyFingerprintStripped == finalizedMD5 || keyFingerprintStripped == finalizedSHA256 {
+		if matchesKeyFingerprint(key, s.keyFingerprint) {
 			matchingKey = key
 		}
 	}
@@ -120,16 +108,16 @@ func (s *SSHAgentSigner) MatchKey() (ssh.PublicKey, error) {
 }
 
 func (s *SSHAgentSigner) Sign(dateHeader string, isManta bool) (string, error) {
-	const headerName = "date"
+	return s.SignHeaders([]SignedHeader{{Name: "date", Value: dateHeader}}, isManta)
+}
 
-	signature, err := s.agent.Sign(s.key, []byte(fmt.Sprintf("%s: %s", headerName, dateHeader)))
-	if err != nil {
-		return "", pkgerrors.Wrap(err, "unable to sign date header")
-	}
+// SignHeaders implements interface HeadersSigner on the SSHAgentSigner type.
+func (s *SSHAgentSigner) SignHeaders(headers []SignedHeader, isManta bool) (string, error) {
+	toSign, headerNames := signingString(headers)
 
-	keyFormat, err := keyFormatToKeyType(signature.Format)
+	authSignature, err := s.sign([]byte(toSign))
 	if err != nil {
-		return "", pkgerrors.Wrap(err, "unable to format signature")
+		return "", pkgerrors.Wrap(err, "unable to sign headers")
 	}
 
 	key := &KeyID{
@@ -139,54 +127,53 @@ func (s *SSHAgentSigner) Sign(dateHeader string, isManta bool) (string, error) {
 		IsManta:     isManta,
 	}
 
-	var authSignature httpAuthSignature
-	switch keyFormat {
-	case "rsa":
-		authSignature, err = newRSASignature(signature.Blob)
-		if err != nil {
-			return "", pkgerrors.Wrap(err, "unable to read RSA signature")
-		}
-	case "ecdsa":
-		authSignature, err = newECDSASignature(signature.Blob)
-		if err != nil {
-			return "", pkgerrors.Wrap(err, "unable to read ECDSA signature")
-		}
-	default:
-		return "", fmt.Errorf("Unsupported algorithm from SSH agent: %s", signature.Format)
-	}
-
 	return fmt.Sprintf(authorizationHeaderFormat, key.generate(),
-		authSignature.SignatureType(), headerName, authSignature.String()), nil
+		authSignature.SignatureType(), headerNames, authSignature.String()), nil
 }
 
 func (s *SSHAgentSigner) SignRaw(toSign string) (string, string, error) {
-	signature, err := s.agent.Sign(s.key, []byte(toSign))
+	authSignature, err := s.sign([]byte(toSign))
 	if err != nil {
 		return "", "", pkgerrors.Wrap(err, "unable to sign string")
 	}
 
+	return authSignature.String(), authSignature.SignatureType(), nil
+}
+
+// sign has the SSH agent sign data with the signer's key.
+func (s *SSHAgentSigner) sign(data []byte) (httpAuthSignature, error) {
+	signature, err := s.agent.Sign(s.key, data)
+	if err != nil {
+		return nil, err
+	}
+
 	keyFormat, err := keyFormatToKeyType(signature.Format)
 	if err != nil {
-		return "", "", pkgerrors.Wrap(err, "unable to format key")
+		return nil, pkgerrors.Wrap(err, "unable to format signature")
 	}
 
-	var authSignature httpAuthSignature
 	switch keyFormat {
 	case "rsa":
-		authSignature, err = newRSASignature(signature.Blob)
+		authSignature, err := newRSASignature(signature.Blob)
 		if err != nil {
-			return "", "", pkgerrors.Wrap(err, "unable to read RSA signature")
+			return nil, pkgerrors.Wrap(err, "unable to read RSA signature")
 		}
+		return authSignature, nil
 	case "ecdsa":
-		authSignature, err = newECDSASignature(signature.Blob)
+		authSignature, err := newECDSASignature(signature.Blob)
 		if err != nil {
-			return "", "", pkgerrors.Wrap(err, "unable to read ECDSA signature")
+			return nil, pkgerrors.Wrap(err, "unable to read ECDSA signature")
 		}
+		return authSignature, nil
+	case "ed25519":
+		authSignature, err := newED25519Signature(signature.Blob)
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "unable to read Ed25519 signature")
+		}
+		return authSignature, nil
 	default:
-		return "", "", fmt.Errorf("Unsupported algorithm from SSH agent: %s", signature.Format)
+		return nil, fmt.Errorf("Unsupported algorithm from SSH agent: %s", signature.Format)
 	}
-
-	return authSignature.String(), authSignature.SignatureType(), nil
 }
 
 func (s *SSHAgentSigner) KeyFingerprint() string {
@@ -0,0 +1,79 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package authentication
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestEd25519SSHAgentSigner(t *testing.T) {
+	key, err := ssh.ParseRawPrivateKey(readTestKey(t, "ed25519_openssh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := newSSHAgentSigner(keyring, SSHAgentSignerInput{
+		KeyID:       "MD5:" + ed25519OpenSSHFingerprint,
+		AccountName: testAccountName,
+		Username:    "operator",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if signer.KeyFingerprint() != ed25519OpenSSHFingerprint {
+		t.Errorf("expected fingerprint %s: got %s", ed25519OpenSSHFingerprint, signer.KeyFingerprint())
+	}
+	if signer.DefaultAlgorithm() != ED25519_SHA512 {
+		t.Errorf("expected algorithm %s: got %s", ED25519_SHA512, signer.DefaultAlgorithm())
+	}
+
+	header, err := signer.Sign(testDateHeader, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID, algorithm, headers, signature := parseAuthorization(t, header)
+	if keyID != "/"+testAccountName+"/operator/keys/"+ed25519OpenSSHFingerprint {
+		t.Errorf("unexpected keyId %q", keyID)
+	}
+	if algorithm != ED25519_SHA512 || headers != "date" {
+		t.Errorf("unexpected algorithm %q or headers %q", algorithm, headers)
+	}
+
+	public := (*key.(*ed25519.PrivateKey)).Public().(ed25519.PublicKey)
+	if !ed25519.Verify(public, []byte("date: "+testDateHeader), signature) {
+		t.Error("expected signature to verify")
+	}
+
+	t.Run("no matching key", func(t *testing.T) {
+		_, err := newSSHAgentSigner(keyring, SSHAgentSignerInput{
+			KeyID:       ed25519PKCS8Fingerprint,
+			AccountName: testAccountName,
+		})
+		if err == nil || !strings.Contains(err.Error(), "No key in the SSH Agent matches") {
+			t.Errorf("expected no matching key: got %v", err)
+		}
+	})
+
+	t.Run("unsupported signature", func(t *testing.T) {
+		if _, err := keyFormatToKeyType(ssh.KeyAlgoDSA); err == nil {
+			t.Error("expected DSA signatures to be rejected")
+		}
+	})
+}
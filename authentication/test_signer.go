@@ -30,6 +30,10 @@ func (s *TestSigner) Sign(dateHeader string, isManta bool) (string, error) {
 	return "", nil
 }
 
+func (s *TestSigner) SignHeaders(headers []SignedHeader, isManta bool) (string, error) {
+	return "", nil
+}
+
 func (s *TestSigner) SignRaw(toSign string) (string, string, error) {
 	return "", "", nil
 }
@@ -9,9 +9,14 @@
 package authentication
 
 import (
+	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/md5"
 	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"strings"
 
@@ -20,27 +25,15 @@ import (
 )
 
 // formatPublicKeyFingerprint produces the MD5 fingerprint of the given SSH
-// public key. If display is true, the fingerprint is formatted with colons
-// between each byte, as per the output of OpenSSL.
+// public key, or of the public half of the given RSA, ECDSA or Ed25519 private
+// key. If display is true, the fingerprint is formatted with colons between
+// each byte, as per the output of OpenSSL.
 func formatPublicKeyFingerprint(privateKey interface{}, display bool) (string, error) {
-	var key ssh.PublicKey
-	switch privateKey.(type) {
-	case *rsa.PrivateKey:
-		p, err := ssh.NewPublicKey(privateKey.(*rsa.PrivateKey).Public())
-		if err != nil {
-			return "", errors.Wrap(err, "unable to parse SSH key from private key")
-		}
-		key = p
-	case *ecdsa.PrivateKey:
-		p, err := ssh.NewPublicKey(privateKey.(*ecdsa.PrivateKey).Public())
-		if err != nil {
-			return "", errors.Wrap(err, "unable to parse SSH key from private key")
-		}
-		key = p
-	default:
-		return "", fmt.Errorf("unable to parse SSH key from private key")
-
+	key, err := sshPublicKey(privateKey)
+	if err != nil {
+		return "", err
 	}
+
 	publicKeyFingerprint := md5.New()
 	publicKeyFingerprint.Write(key.Marshal())
 	publicKeyFingerprintString := fmt.Sprintf("%x", publicKeyFingerprint.Sum(nil))
@@ -56,3 +49,35 @@ func formatPublicKeyFingerprint(privateKey interface{}, display bool) (string, e
 
 	return strings.TrimSuffix(formatted, ":"), nil
 }
+
+// sshPublicKey returns the given SSH public key, or the public half of the
+// given RSA, ECDSA or Ed25519 private key.
+func sshPublicKey(privateKey interface{}) (ssh.PublicKey, error) {
+	switch k := privateKey.(type) {
+	case ssh.PublicKey:
+		return k, nil
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+		p, err := ssh.NewPublicKey(k.(crypto.Signer).Public())
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to parse SSH key from private key")
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unable to parse SSH key from private key of type %T", privateKey)
+	}
+}
+
+// matchesKeyFingerprint reports whether keyID is the MD5 or the SHA256
+// fingerprint of key, as printed by ssh-keygen -l with or without the "MD5:"
+// or "SHA256:" prefix.
+func matchesKeyFingerprint(key ssh.PublicKey, keyID string) bool {
+	fingerprint := strings.TrimPrefix(keyID, "MD5:")
+	fingerprint = strings.TrimPrefix(fingerprint, "SHA256:")
+	fingerprint = strings.Replace(fingerprint, ":", "", -1)
+
+	keyMD5 := md5.Sum(key.Marshal())
+	keySHA256 := sha256.Sum256(key.Marshal())
+
+	return fingerprint == hex.EncodeToString(keyMD5[:]) ||
+		fingerprint == base64.RawStdEncoding.EncodeToString(keySHA256[:])
+}
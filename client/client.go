@@ -9,7 +9,6 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
@@ -21,6 +20,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	triton "github.com/joyent/triton-go/v2"
@@ -31,19 +31,20 @@ import (
 )
 
 var (
-	ErrDefaultAuth = pkgerrors.New("default SSH agent authentication requires SDC_KEY_ID / TRITON_KEY_ID and SSH_AUTH_SOCK")
-	ErrAccountName = pkgerrors.New("missing account name")
-	ErrMissingURL  = pkgerrors.New("missing API URL")
+	ErrDefaultAuth   = pkgerrors.New("default authentication requires SDC_KEY_ID / TRITON_KEY_ID and either SSH_AUTH_SOCK, TRITON_KEY_MATERIAL, TRITON_KEY_FILE or a matching key in ~/.ssh")
+	ErrAccountName   = pkgerrors.New("missing account name")
+	ErrMissingURL    = pkgerrors.New("missing API URL")
+	ErrMissingSigner = pkgerrors.New("missing signer to authenticate requests with")
 
 	InvalidTritonURL   = "invalid format of Triton URL"
 	InvalidMantaURL    = "invalid format of Manta URL"
 	InvalidServicesURL = "invalid format of Triton Service Groups URL"
 	InvalidDCInURL     = "invalid data center in URL"
 
-	knownDCFormats = []string{
-		`https?://(.*).api.joyent.com`,
-		`https?://(.*).api.joyentcloud.com`,
-		`https?://(.*).api.samsungcloud.io`,
+	knownDCFormats = []*regexp.Regexp{
+		regexp.MustCompile(`https?://(.*).api.joyent.com`),
+		regexp.MustCompile(`https?://(.*).api.joyentcloud.com`),
+		regexp.MustCompile(`https?://(.*).api.samsungcloud.io`),
 	}
 
 	jpcFormatURL = "https://tsg.%s.svc.joyent.zone"
@@ -54,20 +55,68 @@ var (
 )
 
 // Client represents a connection to the Triton Compute or Object Storage APIs.
+//
+// Requests are signed with one of Authorizers at a time, falling back to the
+// next one whenever the API rejects a key. See ActiveSigner.
 type Client struct {
-	HTTPClient    *http.Client
+	HTTPClient *http.Client
+
+	// RequestHeader is set on every request executed by the client while it
+	// is not nil. The client never modifies it.
+	//
+	// Deprecated: RequestHeader is not safe to assign while the client is
+	// used concurrently. Use SetHeader, WithHeader, ContextWithHeader or
+	// RequestInput.Headers instead.
 	RequestHeader *http.Header
-	Authorizers   []authentication.Signer
-	TritonURL     url.URL
-	MantaURL      url.URL
-	ServicesURL   url.URL
-	AccountName   string
-	Username      string
+
+	Authorizers []authentication.Signer
+	TritonURL   url.URL
+	MantaURL    url.URL
+	ServicesURL url.URL
+	AccountName string
+	Username    string
+
+	// RetryPolicy controls whether and how requests which were throttled or
+	// failed transiently are retried. A nil RetryPolicy disables retries.
+	RetryPolicy *RetryPolicy
+
+	// TritonLimiter, MantaLimiter and ServicesLimiter throttle the requests
+	// sent to TritonURL, MantaURL and ServicesURL respectively. A nil Limiter
+	// lets requests through unthrottled.
+	TritonLimiter   *Limiter
+	MantaLimiter    *Limiter
+	ServicesLimiter *Limiter
+
+	// Middleware is run for every request issued through the client. See
+	// Use.
+	Middleware []Middleware
+
+	// Logger receives a record of every API call made through the client,
+	// including its operation, status, latency and request ID, and of every
+	// retry. Credentials are never logged. A nil Logger disables logging.
+	Logger Logger
+
+	// Tracer starts a span for every API call made through the client. A nil
+	// Tracer disables tracing.
+	Tracer Tracer
+
+	// SignedHeaders lists the headers covered by request signatures, in
+	// order, e.g. "(request-target)", "host", "date" and "content-md5". It
+	// only applies to signers implementing authentication.HeadersSigner. By
+	// default only the date header is signed.
+	SignedHeaders []string
+
+	// signers tracks which of the Authorizers was last accepted by the API.
+	signers *signerChain
+
+	// header holds the http.Header set on every request executed by the
+	// client. SetHeader replaces it atomically, and every request copies
+	// the one current when it starts.
+	header atomic.Value
 }
 
 func isPrivateInstall(url string) bool {
-	for _, pattern := range knownDCFormats {
-		re := regexp.MustCompile(pattern)
+	for _, re := range knownDCFormats {
 		matches := re.FindStringSubmatch(url)
 		if len(matches) > 1 {
 			return false
@@ -101,8 +150,7 @@ func parseDC(url string) (string, bool, error) {
 		isSamsung = true
 	}
 
-	for _, pattern := range knownDCFormats {
-		re := regexp.MustCompile(pattern)
+	for _, re := range knownDCFormats {
 		matches := re.FindStringSubmatch(url)
 		if len(matches) > 1 {
 			return matches[1], isSamsung, nil
@@ -118,6 +166,19 @@ func parseDC(url string) (string, bool, error) {
 // At least one signer must be provided - example signers include
 // authentication.PrivateKeySigner and authentication.SSHAgentSigner.
 func New(tritonURL string, mantaURL string, accountName string, signers ...authentication.Signer) (*Client, error) {
+	return NewFromConfig(&triton.ClientConfig{
+		TritonURL:   tritonURL,
+		MantaURL:    mantaURL,
+		AccountName: accountName,
+		Signers:     signers,
+	})
+}
+
+// NewFromConfig constructs a Client from config, such as one returned by
+// triton.LoadProfile. Without signers, a signer for config.KeyID is looked up
+// as described by DefaultAuth.
+func NewFromConfig(config *triton.ClientConfig) (*Client, error) {
+	tritonURL, mantaURL, accountName := config.TritonURL, config.MantaURL, config.AccountName
 	if accountName == "" {
 		return nil, ErrAccountName
 	}
@@ -136,35 +197,19 @@ func New(tritonURL string, mantaURL string, accountName string, signers ...authe
 		return nil, pkgerrors.Wrapf(err, InvalidMantaURL)
 	}
 
-	// Generate the Services URL (TSG) based on the current datacenter used in
-	// the Triton URL (if TritonURL is available). If TRITON_TSG_URL environment
-	// variable is available than override using that value instead.
-	tsgURL := triton.GetEnv("TSG_URL")
-	if tsgURL == "" && tritonURL != "" && !isPrivateInstall(tritonURL) {
-		currentDC, isSamsung, err := parseDC(tritonURL)
-		if err != nil {
-			return nil, pkgerrors.Wrapf(err, InvalidDCInURL)
-		}
-
-		tsgURL = fmt.Sprintf(jpcFormatURL, currentDC)
-		if isSamsung {
-			tsgURL = fmt.Sprintf(spcFormatURL, currentDC)
-		}
-	}
-
-	servicesURL, err := url.Parse(tsgURL)
+	servicesURL, err := servicesURLFor(tritonURL)
 	if err != nil {
-		return nil, pkgerrors.Wrapf(err, InvalidServicesURL)
+		return nil, err
 	}
 
 	authorizers := make([]authentication.Signer, 0)
-	for _, key := range signers {
+	for _, key := range config.Signers {
 		if key != nil {
 			authorizers = append(authorizers, key)
 		}
 	}
 
-	skipTLSVerify := triton.GetEnv("SKIP_TLS_VERIFY") != ""
+	skipTLSVerify := config.InsecureSkipTLSVerify || triton.GetEnv("SKIP_TLS_VERIFY") != ""
 
 	newClient := &Client{
 		HTTPClient: &http.Client{
@@ -176,16 +221,21 @@ func New(tritonURL string, mantaURL string, accountName string, signers ...authe
 		MantaURL:    *storageURL,
 		ServicesURL: *servicesURL,
 		AccountName: accountName,
+		Username:    config.Username,
+		signers:     &signerChain{},
 	}
 
 	// Allow wrapping of the HTTP Transport.
 	newClient.HTTPClient.Transport = wrapTritonTransport(newClient.HTTPClient.Transport)
 
-	// Default to constructing an SSHAgentSigner if there are no other signers
-	// passed into NewClient and there's an TRITON_KEY_ID and SSH_AUTH_SOCK
-	// available in the user's environ(7).
+	// Default to looking up a signer for TRITON_KEY_ID if there are no other
+	// signers passed into NewClient. See DefaultAuth.
 	if len(newClient.Authorizers) == 0 {
-		if err := newClient.DefaultAuth(); err != nil {
+		keyID := config.KeyID
+		if keyID == "" {
+			keyID = triton.GetEnv("KEY_ID")
+		}
+		if err := newClient.defaultAuth(keyID); err != nil {
 			return nil, err
 		}
 	}
@@ -193,28 +243,6 @@ func New(tritonURL string, mantaURL string, accountName string, signers ...authe
 	return newClient, nil
 }
 
-// initDefaultAuth provides a default key signer for a client. This should only
-// be used internally if the client has no other key signer for authenticating
-// with Triton. We first look for both `SDC_KEY_ID` and `SSH_AUTH_SOCK` in the
-// user's environ(7). If so we default to the SSH agent key signer.
-func (c *Client) DefaultAuth() error {
-	tritonKeyId := triton.GetEnv("KEY_ID")
-	if tritonKeyId != "" {
-		input := authentication.SSHAgentSignerInput{
-			KeyID:       tritonKeyId,
-			AccountName: c.AccountName,
-			Username:    c.Username,
-		}
-		defaultSigner, err := authentication.NewSSHAgentSigner(input)
-		if err != nil {
-			return pkgerrors.Wrapf(err, "unable to initialize NewSSHAgentSigner")
-		}
-		c.Authorizers = append(c.Authorizers, defaultSigner)
-	}
-
-	return ErrDefaultAuth
-}
-
 // InsecureSkipTLSVerify turns off TLS verification for the client connection. This
 // allows connection to an endpoint with a certificate which was signed by a non-
 // trusted CA, such as self-signed certificates. This can be useful when connecting
@@ -251,263 +279,218 @@ func doNotFollowRedirects(*http.Request, []*http.Request) error {
 	return http.ErrUseLastResponse
 }
 
-// DecodeError decodes a backend Triton error into a more usable Go error type
-func (c *Client) DecodeError(resp *http.Response, requestMethod string, consumeBody bool) error {
-	err := &errors.APIError{
-		StatusCode: resp.StatusCode,
-	}
+// servicesURLFor generates the Services URL (TSG) based on the datacenter used
+// in tritonURL (if tritonURL is available). If TRITON_TSG_URL environment
+// variable is available than override using that value instead.
+func servicesURLFor(tritonURL string) (*url.URL, error) {
+	tsgURL := triton.GetEnv("TSG_URL")
+	if tsgURL == "" && tritonURL != "" && !isPrivateInstall(tritonURL) {
+		currentDC, isSamsung, err := parseDC(tritonURL)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, InvalidDCInURL)
+		}
 
-	if requestMethod != http.MethodHead && resp.Body != nil && consumeBody {
-		errorDecoder := json.NewDecoder(resp.Body)
-		if err := errorDecoder.Decode(err); err != nil {
-			return pkgerrors.Wrapf(err, "unable to decode error response")
+		tsgURL = fmt.Sprintf(jpcFormatURL, currentDC)
+		if isSamsung {
+			tsgURL = fmt.Sprintf(spcFormatURL, currentDC)
 		}
 	}
 
-	if err.Message == "" {
-		err.Message = fmt.Sprintf("HTTP response returned status code %d", err.StatusCode)
+	servicesURL, err := url.Parse(tsgURL)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, InvalidServicesURL)
 	}
 
-	return err
+	return servicesURL, nil
 }
 
-// overrideHeader overrides the header of the passed in HTTP request
-func (c *Client) overrideHeader(req *http.Request) {
-	if c.RequestHeader != nil {
-		for k := range *c.RequestHeader {
-			req.Header.Set(k, c.RequestHeader.Get(k))
-		}
+// DecodeError decodes a backend Triton error into a more usable Go error type.
+// The returned *errors.APIError records the request ID the operator of the API
+// needs to trace the failed request.
+func (c *Client) DecodeError(resp *http.Response, requestMethod string, consumeBody bool) error {
+	var path string
+	if resp.Request != nil && resp.Request.URL != nil {
+		path = resp.Request.URL.Path
 	}
-}
 
-// resetHeader will reset the struct field that stores custom header
-// information
-func (c *Client) resetHeader() {
-	c.RequestHeader = nil
+	return decodeError(resp, requestMethod, path, consumeBody)
 }
 
-// -----------------------------------------------------------------------------
-
-type RequestInput struct {
-	Method  string
-	Path    string
-	Query   *url.Values
-	Headers *http.Header
-	Body    interface{}
-
-	// If the response has the HTTP status code 410 (i.e., "Gone"), should we preserve the contents of the body for the caller?
-	PreserveGone bool
-}
-
-func (c *Client) ExecuteRequestURIParams(ctx context.Context, inputs RequestInput) (io.ReadCloser, error) {
-	defer c.resetHeader()
-
-	method := inputs.Method
-	path := inputs.Path
-	body := inputs.Body
-	query := inputs.Query
-
-	var requestBody io.Reader
-	if body != nil {
-		marshaled, err := json.MarshalIndent(body, "", "    ")
-		if err != nil {
-			return nil, err
-		}
-		requestBody = bytes.NewReader(marshaled)
-	}
-
-	endpoint := c.TritonURL
-	endpoint.Path = path
-	if query != nil {
-		endpoint.RawQuery = query.Encode()
+func decodeError(resp *http.Response, method, path string, consumeBody bool) error {
+	err := &errors.APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  requestID(resp.Header),
+		ServerName: resp.Header.Get("X-Server-Name"),
+		Method:     method,
+		Path:       path,
 	}
-
-	req, err := http.NewRequest(method, endpoint.String(), requestBody)
-	if err != nil {
-		return nil, pkgerrors.Wrapf(err, "unable to construct HTTP request")
+	if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		err.RetryAfter = wait
 	}
 
-	dateHeader := time.Now().UTC().Format(time.RFC1123)
-	req.Header.Set("date", dateHeader)
-
-	// NewClient ensures there's always an authorizer (unless this is called
-	// outside that constructor).
-	authHeader, err := c.Authorizers[0].Sign(dateHeader, false)
-	if err != nil {
-		return nil, pkgerrors.Wrapf(err, "unable to sign HTTP request")
+	if method != http.MethodHead && resp.Body != nil && consumeBody {
+		errorDecoder := json.NewDecoder(resp.Body)
+		if err := errorDecoder.Decode(err); err != nil {
+			return pkgerrors.Wrapf(err, "unable to decode error response")
+		}
 	}
-	req.Header.Set("Authorization", authHeader)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept-Version", triton.CloudAPIMajorVersion)
-	req.Header.Set("User-Agent", triton.UserAgent())
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if err.Message == "" {
+		err.Message = fmt.Sprintf("HTTP response returned status code %d", err.StatusCode)
 	}
 
-	c.overrideHeader(req)
-
-	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, pkgerrors.Wrapf(err, "unable to execute HTTP request")
-	}
+	return err
+}
 
-	// We will only return a response from the API it is in the HTTP StatusCode
-	// 2xx range
-	// StatusMultipleChoices is StatusCode 300
-	if resp.StatusCode >= http.StatusOK &&
-		resp.StatusCode < http.StatusMultipleChoices {
-		return resp.Body, nil
-	}
+type headerContextKey struct{}
 
-	return nil, c.DecodeError(resp, req.Method, true)
+// ContextWithHeader returns a copy of ctx carrying header. Every request
+// executed with the returned context has the values of header set on it, in
+// addition to any headers bound to the client with SetHeader or WithHeader.
+// This is the preferred way of setting per-request headers on a Client shared
+// between goroutines.
+func ContextWithHeader(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, headerContextKey{}, header)
 }
 
-func (c *Client) ExecuteRequest(ctx context.Context, inputs RequestInput) (io.ReadCloser, error) {
-	return c.ExecuteRequestURIParams(ctx, inputs)
+func headerFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headerContextKey{}).(http.Header)
+	return header
 }
 
-func (c *Client) ExecuteRequestRaw(ctx context.Context, inputs RequestInput) (*http.Response, error) {
-	defer c.resetHeader()
-
-	method := inputs.Method
-	path := inputs.Path
-	body := inputs.Body
-	query := inputs.Query
+// WithHeader returns a shallow copy of the client which sets header on every
+// request it executes. The original client is left untouched, so both can be
+// used concurrently.
+func (c *Client) WithHeader(header *http.Header) *Client {
+	newClient := *c
+	newClient.RequestHeader = nil
+	newClient.header = atomic.Value{}
+	newClient.SetHeader(header)
+	return &newClient
+}
 
-	var requestBody io.Reader
-	if body != nil {
-		marshaled, err := json.MarshalIndent(body, "", "    ")
-		if err != nil {
-			return nil, err
-		}
-		requestBody = bytes.NewReader(marshaled)
+// SetHeader replaces the headers set on every request the client executes
+// with a copy of header, or removes them if header is nil. Requests already
+// started keep the headers they were started with. SetHeader is safe to
+// call while the client is in use.
+func (c *Client) SetHeader(header *http.Header) {
+	var bound http.Header
+	if header != nil {
+		bound = header.Clone()
 	}
+	c.header.Store(bound)
+}
 
-	endpoint := c.TritonURL
-	endpoint.Path = path
-	if query != nil {
-		endpoint.RawQuery = query.Encode()
-	}
+// boundHeader returns the headers last set with SetHeader or WithHeader.
+func (c *Client) boundHeader() http.Header {
+	header, _ := c.header.Load().(http.Header)
+	return header
+}
 
-	req, err := http.NewRequest(method, endpoint.String(), requestBody)
+// WithTritonURL returns a shallow copy of the client which sends CloudAPI
+// requests to tritonURL, e.g. of another data center. The copy shares the
+// HTTP client, signers and middleware of c, and its Services URL is derived
+// from tritonURL. Its TritonLimiter and ServicesLimiter are unset, since they
+// would throttle other endpoints.
+func (c *Client) WithTritonURL(tritonURL string) (*Client, error) {
+	cloudURL, err := url.Parse(tritonURL)
 	if err != nil {
-		return nil, pkgerrors.Wrapf(err, "unable to construct HTTP request")
+		return nil, pkgerrors.Wrapf(err, InvalidTritonURL)
 	}
 
-	dateHeader := time.Now().UTC().Format(time.RFC1123)
-	req.Header.Set("date", dateHeader)
-
-	// NewClient ensures there's always an authorizer (unless this is called
-	// outside that constructor).
-	authHeader, err := c.Authorizers[0].Sign(dateHeader, false)
+	servicesURL, err := servicesURLFor(tritonURL)
 	if err != nil {
-		return nil, pkgerrors.Wrapf(err, "unable to sign HTTP request")
-	}
-	req.Header.Set("Authorization", authHeader)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept-Version", triton.CloudAPIMajorVersion)
-	req.Header.Set("User-Agent", triton.UserAgent())
-
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+		return nil, err
 	}
 
-	c.overrideHeader(req)
-
-	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, pkgerrors.Wrapf(err, "unable to execute HTTP request")
-	}
+	newClient := *c
+	newClient.TritonURL = *cloudURL
+	newClient.ServicesURL = *servicesURL
+	newClient.TritonLimiter = nil
+	newClient.ServicesLimiter = nil
+	return &newClient, nil
+}
 
-	// We will only return a response from the API it is in the HTTP StatusCode
-	// 2xx range
-	// StatusMultipleChoices is StatusCode 300
-	if resp.StatusCode >= http.StatusOK &&
-		resp.StatusCode < http.StatusMultipleChoices {
-		return resp, nil
+// overrideHeader overrides the header of the passed in HTTP request with the
+// headers bound to the client, the client's RequestHeader and the headers
+// bound to the request's context.
+func (c *Client) overrideHeader(ctx context.Context, req *http.Request) {
+	var requestHeader http.Header
+	if c.RequestHeader != nil {
+		requestHeader = *c.RequestHeader
 	}
 
-	// GetMachine returns a HTTP 410 response for deleted instances, but the body of the response is still a valid machine object with a State value of "deleted". Return the object to the caller as well as an error.
-	if inputs.PreserveGone && resp.StatusCode == http.StatusGone {
-		// Do not consume the response body.
-		return resp, c.DecodeError(resp, req.Method, false)
+	for _, header := range []http.Header{c.boundHeader(), requestHeader, headerFromContext(ctx)} {
+		for k := range header {
+			req.Header.Set(k, header.Get(k))
+		}
 	}
-
-	return nil, c.DecodeError(resp, req.Method, true)
 }
 
-func (c *Client) ExecuteRequestStorage(ctx context.Context, inputs RequestInput) (io.ReadCloser, http.Header, error) {
-	defer c.resetHeader()
+// -----------------------------------------------------------------------------
 
-	method := inputs.Method
-	path := inputs.Path
-	query := inputs.Query
-	headers := inputs.Headers
-	body := inputs.Body
+type RequestInput struct {
+	// Operation names the API call in logs and traces, e.g.
+	// "compute.instances.create".
+	Operation string
 
-	endpoint := c.MantaURL
-	endpoint.Path = path
+	Method  string
+	Path    string
+	Query   *url.Values
+	Headers *http.Header
+	Body    interface{}
 
-	var requestBody io.Reader
-	if body != nil {
-		marshaled, err := json.MarshalIndent(body, "", "    ")
-		if err != nil {
-			return nil, nil, err
-		}
-		requestBody = bytes.NewReader(marshaled)
-	}
+	// If the response has the HTTP status code 410 (i.e., "Gone"), should we preserve the contents of the body for the caller?
+	PreserveGone bool
+}
 
-	req, err := http.NewRequest(method, endpoint.String(), requestBody)
+func (c *Client) ExecuteRequestURIParams(ctx context.Context, inputs RequestInput) (io.ReadCloser, error) {
+	req, err := newAPIRequest(c.TritonURL, inputs, false)
 	if err != nil {
-		return nil, nil, pkgerrors.Wrapf(err, "unable to construct HTTP request")
+		return nil, err
 	}
+	req.limiter = c.TritonLimiter
 
-	if body != nil && (headers == nil || headers.Get("Content-Type") == "") {
-		req.Header.Set("Content-Type", "application/json")
-	}
-	if headers != nil {
-		for key, values := range *headers {
-			for _, value := range values {
-				req.Header.Set(key, value)
-			}
-		}
+	resp, err := c.execute(ctx, req)
+	if resp != nil {
+		return resp.Body, err
 	}
+	return nil, err
+}
 
-	dateHeader := time.Now().UTC().Format(time.RFC1123)
-	req.Header.Set("date", dateHeader)
+func (c *Client) ExecuteRequest(ctx context.Context, inputs RequestInput) (io.ReadCloser, error) {
+	return c.ExecuteRequestURIParams(ctx, inputs)
+}
 
-	authHeader, err := c.Authorizers[0].Sign(dateHeader, true)
+func (c *Client) ExecuteRequestRaw(ctx context.Context, inputs RequestInput) (*http.Response, error) {
+	req, err := newAPIRequest(c.TritonURL, inputs, false)
 	if err != nil {
-		return nil, nil, pkgerrors.Wrapf(err, "unable to sign HTTP request")
-	}
-	req.Header.Set("Authorization", authHeader)
-	req.Header.Set("Accept", "*/*")
-	req.Header.Set("User-Agent", triton.UserAgent())
-
-	if query != nil {
-		req.URL.RawQuery = query.Encode()
+		return nil, err
 	}
+	req.limiter = c.TritonLimiter
 
-	c.overrideHeader(req)
+	return c.execute(ctx, req)
+}
 
-	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
+func (c *Client) ExecuteRequestStorage(ctx context.Context, inputs RequestInput) (io.ReadCloser, http.Header, error) {
+	req, err := newAPIRequest(c.MantaURL, inputs, true)
 	if err != nil {
-		return nil, nil, pkgerrors.Wrapf(err, "unable to execute HTTP request")
+		return nil, nil, err
 	}
+	req.limiter = c.MantaLimiter
 
-	// We will only return a response from the API it is in the HTTP StatusCode
-	// 2xx range
-	// StatusMultipleChoices is StatusCode 300
-	if resp.StatusCode >= http.StatusOK &&
-		resp.StatusCode < http.StatusMultipleChoices {
-		return resp.Body, resp.Header, nil
+	resp, err := c.execute(ctx, req)
+	if resp != nil {
+		return resp.Body, resp.Header, err
 	}
-
-	return nil, nil, c.DecodeError(resp, req.Method, true)
+	return nil, nil, err
 }
 
 type RequestNoEncodeInput struct {
+	// Operation names the API call in logs and traces, e.g.
+	// "storage.objects.put".
+	Operation string
+
 	Method  string
 	Path    string
 	Query   *url.Values
@@ -516,121 +499,37 @@ type RequestNoEncodeInput struct {
 }
 
 func (c *Client) ExecuteRequestNoEncode(ctx context.Context, inputs RequestNoEncodeInput) (io.ReadCloser, http.Header, error) {
-	defer c.resetHeader()
-
-	method := inputs.Method
-	path := inputs.Path
-	query := inputs.Query
-	headers := inputs.Headers
-	body := inputs.Body
+	req := apiRequest{
+		operation: inputs.Operation,
+		endpoint:  c.MantaURL,
+		method:    inputs.Method,
+		path:      inputs.Path,
+		query:     inputs.Query,
+		headers:   inputs.Headers,
+		body:      inputs.Body,
+		isManta:   true,
+		limiter:   c.MantaLimiter,
 
-	endpoint := c.MantaURL
-	endpoint.Path = path
-
-	req, err := http.NewRequest(method, endpoint.String(), body)
-	if err != nil {
-		return nil, nil, pkgerrors.Wrapf(err, "unable to construct HTTP request")
+		acceptVersion: true,
 	}
 
-	if headers != nil {
-		for key, values := range *headers {
-			for _, value := range values {
-				req.Header.Set(key, value)
-			}
-		}
+	resp, err := c.execute(ctx, req)
+	if resp != nil {
+		return resp.Body, resp.Header, err
 	}
-
-	dateHeader := time.Now().UTC().Format(time.RFC1123)
-	req.Header.Set("date", dateHeader)
-
-	authHeader, err := c.Authorizers[0].Sign(dateHeader, true)
-	if err != nil {
-		return nil, nil, pkgerrors.Wrapf(err, "unable to sign HTTP request")
-	}
-	req.Header.Set("Authorization", authHeader)
-	req.Header.Set("Accept", "*/*")
-	req.Header.Set("Accept-Version", triton.CloudAPIMajorVersion)
-	req.Header.Set("User-Agent", triton.UserAgent())
-
-	if query != nil {
-		req.URL.RawQuery = query.Encode()
-	}
-
-	c.overrideHeader(req)
-
-	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, nil, pkgerrors.Wrapf(err, "unable to execute HTTP request")
-	}
-
-	// We will only return a response from the API it is in the HTTP StatusCode
-	// 2xx range
-	// StatusMultipleChoices is StatusCode 300
-	if resp.StatusCode >= http.StatusOK &&
-		resp.StatusCode < http.StatusMultipleChoices {
-		return resp.Body, resp.Header, nil
-	}
-
-	return nil, nil, c.DecodeError(resp, req.Method, true)
+	return nil, nil, err
 }
 
 func (c *Client) ExecuteRequestTSG(ctx context.Context, inputs RequestInput) (io.ReadCloser, error) {
-	defer c.resetHeader()
-
-	method := inputs.Method
-	path := inputs.Path
-	body := inputs.Body
-	query := inputs.Query
-
-	var requestBody io.Reader
-	if body != nil {
-		marshaled, err := json.MarshalIndent(body, "", "    ")
-		if err != nil {
-			return nil, err
-		}
-		requestBody = bytes.NewReader(marshaled)
-	}
-
-	endpoint := c.ServicesURL
-	endpoint.Path = path
-	if query != nil {
-		endpoint.RawQuery = query.Encode()
-	}
-
-	req, err := http.NewRequest(method, endpoint.String(), requestBody)
+	req, err := newAPIRequest(c.ServicesURL, inputs, false)
 	if err != nil {
-		return nil, pkgerrors.Wrapf(err, "unable to construct HTTP request")
+		return nil, err
 	}
+	req.limiter = c.ServicesLimiter
 
-	dateHeader := time.Now().UTC().Format(time.RFC1123)
-	req.Header.Set("date", dateHeader)
-
-	// NewClient ensures there's always an authorizer (unless this is called
-	// outside that constructor).
-	authHeader, err := c.Authorizers[0].Sign(dateHeader, false)
-	if err != nil {
-		return nil, pkgerrors.Wrapf(err, "unable to sign HTTP request")
-	}
-	req.Header.Set("Authorization", authHeader)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Accept-Version", triton.CloudAPIMajorVersion)
-	req.Header.Set("User-Agent", triton.UserAgent())
-
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	resp, err := c.execute(ctx, req)
+	if resp != nil {
+		return resp.Body, err
 	}
-
-	c.overrideHeader(req)
-
-	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, pkgerrors.Wrapf(err, "unable to execute HTTP request")
-	}
-
-	if resp.StatusCode >= http.StatusOK &&
-		resp.StatusCode < http.StatusMultipleChoices {
-		return resp.Body, nil
-	}
-
-	return nil, fmt.Errorf("could not process backend TSG request")
+	return nil, err
 }
@@ -9,11 +9,21 @@
 package client
 
 import (
+	"context"
+	stderrors "errors"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	triton "github.com/joyent/triton-go/v2"
 	auth "github.com/joyent/triton-go/v2/authentication"
+	"github.com/joyent/triton-go/v2/errors"
+	pkgerrors "github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
 )
 
 const BadURL = "**ftp://man($$"
@@ -194,3 +204,174 @@ func TestNew(t *testing.T) {
 		}
 	})
 }
+
+func TestDefaultAuth(t *testing.T) {
+	accountName := "test.user"
+	jpcTritonURL := "https://us-east-1.api.joyent.com"
+
+	// setup points the default credential chain at an empty home directory
+	// with no SSH agent, and returns that directory.
+	setup := func(t *testing.T) string {
+		home, err := ioutil.TempDir("", "triton-go-home")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, name := range []string{"SSH_AUTH_SOCK", "SDC_KEY_ID", "TRITON_KEY_MATERIAL",
+			"SDC_KEY_MATERIAL", "TRITON_KEY_FILE", "SDC_KEY_FILE", "TRITON_KEY_PASSPHRASE"} {
+			os.Unsetenv(name)
+		}
+		os.Setenv("HOME", home)
+		os.Setenv("TRITON_KEY_ID", DummyAuth.Fingerprint)
+
+		return home
+	}
+
+	writeKey := func(t *testing.T, dir string, name string) string {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, DummyAuth.PrivateKey, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path+".pub", DummyAuth.PublicKey, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	home := os.Getenv("HOME")
+	defer func() {
+		os.Setenv("HOME", home)
+		os.Unsetenv("TRITON_KEY_ID")
+		os.Unsetenv("TRITON_KEY_FILE")
+	}()
+
+	t.Run("key file", func(t *testing.T) {
+		dir := setup(t)
+		defer os.RemoveAll(dir)
+		os.Setenv("TRITON_KEY_FILE", writeKey(t, filepath.Join(dir, "keys"), "triton"))
+		defer os.Unsetenv("TRITON_KEY_FILE")
+
+		c, err := New(jpcTritonURL, "", accountName)
+		if err != nil {
+			t.Fatalf("expected error to be nil: received %v", err)
+		}
+		if _, ok := c.ActiveSigner().(*auth.PrivateKeySigner); !ok {
+			t.Errorf("expected a private key signer: got %T", c.ActiveSigner())
+		}
+	})
+
+	t.Run("sha256 key id", func(t *testing.T) {
+		dir := setup(t)
+		defer os.RemoveAll(dir)
+		pub, _, _, _, err := ssh.ParseAuthorizedKey(DummyAuth.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.Setenv("TRITON_KEY_ID", ssh.FingerprintSHA256(pub))
+		os.Setenv("TRITON_KEY_MATERIAL", string(DummyAuth.PrivateKey))
+		defer os.Unsetenv("TRITON_KEY_MATERIAL")
+
+		c, err := New(jpcTritonURL, "", accountName)
+		if err != nil {
+			t.Fatalf("expected error to be nil: received %v", err)
+		}
+		if _, ok := c.ActiveSigner().(*auth.PrivateKeySigner); !ok {
+			t.Errorf("expected a private key signer: got %T", c.ActiveSigner())
+		}
+	})
+
+	t.Run("home directory", func(t *testing.T) {
+		dir := setup(t)
+		defer os.RemoveAll(dir)
+		writeKey(t, filepath.Join(dir, ".ssh"), "id_rsa")
+
+		c, err := New(jpcTritonURL, "", accountName)
+		if err != nil {
+			t.Fatalf("expected error to be nil: received %v", err)
+		}
+		if len(c.Authorizers) != 1 {
+			t.Errorf("expected one authorizer: got %d", len(c.Authorizers))
+		}
+	})
+
+	t.Run("config key id", func(t *testing.T) {
+		dir := setup(t)
+		defer os.RemoveAll(dir)
+		os.Unsetenv("TRITON_KEY_ID")
+		writeKey(t, filepath.Join(dir, ".ssh"), "id_rsa")
+
+		c, err := NewFromConfig(&triton.ClientConfig{
+			TritonURL:   jpcTritonURL,
+			AccountName: accountName,
+			Username:    "operator",
+			KeyID:       DummyAuth.Fingerprint,
+		})
+		if err != nil {
+			t.Fatalf("expected error to be nil: received %v", err)
+		}
+		if len(c.Authorizers) != 1 || c.Username != "operator" {
+			t.Errorf("expected one authorizer for the operator user: got %d for %q", len(c.Authorizers), c.Username)
+		}
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		dir := setup(t)
+		defer os.RemoveAll(dir)
+
+		_, err := New(jpcTritonURL, "", accountName)
+		authErr, ok := err.(*DefaultAuthError)
+		if !ok {
+			t.Fatalf("expected a DefaultAuthError: received %v", err)
+		}
+		if len(authErr.Errors) != len(defaultCredentialSources) {
+			t.Errorf("expected an error for every credential source: got %v", authErr.Errors)
+		}
+		if pkgerrors.Cause(err) != ErrDefaultAuth {
+			t.Errorf("expected error to be caused by ErrDefaultAuth: received %v", err)
+		}
+		for _, source := range []string{"SSH agent", "TRITON_KEY_MATERIAL", "TRITON_KEY_FILE", "~/.ssh"} {
+			if !strings.Contains(err.Error(), source) {
+				t.Errorf("expected error to explain %s: received %v", source, err)
+			}
+		}
+	})
+}
+
+func TestDecodeError(t *testing.T) {
+	c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp := newTestResponse(http.StatusServiceUnavailable, `{"code":"ServiceUnavailable","message":"try later"}`)
+		resp.Header.Set("X-Request-Id", "d5b5e2a2-4bd1-4a4a-9ad1-55fd8d0a8a9c")
+		resp.Header.Set("X-Server-Name", "0a3c1a84-cloudapi")
+		resp.Header.Set("Retry-After", "7")
+		return resp, nil
+	}))
+
+	_, err := c.ExecuteRequest(context.Background(), RequestInput{
+		Method: http.MethodPost,
+		Path:   "/test.user/machines",
+	})
+	err = pkgerrors.Wrap(err, "unable to create machine")
+
+	var apiErr *errors.APIError
+	if !stderrors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError: got %v", err)
+	}
+	if apiErr.RequestID != "d5b5e2a2-4bd1-4a4a-9ad1-55fd8d0a8a9c" || apiErr.ServerName != "0a3c1a84-cloudapi" {
+		t.Errorf("expected request id and server name: got %q, %q", apiErr.RequestID, apiErr.ServerName)
+	}
+	if apiErr.Method != http.MethodPost || apiErr.Path != "/test.user/machines" {
+		t.Errorf("expected POST /test.user/machines: got %s %s", apiErr.Method, apiErr.Path)
+	}
+	if apiErr.RetryAfter != 7*time.Second {
+		t.Errorf("expected RetryAfter of 7s: got %s", apiErr.RetryAfter)
+	}
+	if !strings.Contains(err.Error(), "request id: d5b5e2a2-4bd1-4a4a-9ad1-55fd8d0a8a9c") {
+		t.Errorf("expected the request id in the message: got %q", err)
+	}
+	if !stderrors.Is(err, errors.ErrServiceUnavailable) || !apiErr.Retryable() {
+		t.Errorf("expected a retryable ServiceUnavailable error: got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+const concurrentRequests = 200
+
+// echoHeaderTransport fails any request whose header does not match the tag
+// carried in its query string, which is how a request that lost or stole
+// another request's headers shows up.
+func echoHeaderTransport(t *testing.T, header string) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		expected := req.URL.Query().Get("tag")
+		if got := req.Header.Get(header); got != expected {
+			t.Errorf("expected %s %q: got %q", header, expected, got)
+		}
+		return newTestResponse(http.StatusOK, `{}`), nil
+	})
+}
+
+func hammer(t *testing.T, fn func(ctx context.Context, tag string) error) {
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentRequests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := fn(context.Background(), fmt.Sprintf("tag-%d", i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestConcurrentHeaders(t *testing.T) {
+	t.Run("context headers", func(t *testing.T) {
+		c := newTestClient(echoHeaderTransport(t, "X-Request-Tag"))
+
+		hammer(t, func(ctx context.Context, tag string) error {
+			ctx = ContextWithHeader(ctx, http.Header{"X-Request-Tag": []string{tag}})
+			body, err := c.ExecuteRequest(ctx, RequestInput{
+				Method: http.MethodGet,
+				Path:   "/test.user/machines",
+				Query:  &url.Values{"tag": []string{tag}},
+			})
+			if err != nil {
+				return err
+			}
+			return body.Close()
+		})
+	})
+
+	t.Run("request input headers", func(t *testing.T) {
+		c := newTestClient(echoHeaderTransport(t, "X-Request-Tag"))
+
+		hammer(t, func(ctx context.Context, tag string) error {
+			body, _, err := c.ExecuteRequestStorage(ctx, RequestInput{
+				Method:  http.MethodGet,
+				Path:    "/test.user/stor",
+				Query:   &url.Values{"tag": []string{tag}},
+				Headers: &http.Header{"X-Request-Tag": []string{tag}},
+			})
+			if err != nil {
+				return err
+			}
+			return body.Close()
+		})
+	})
+
+	t.Run("bound headers", func(t *testing.T) {
+		c := newTestClient(echoHeaderTransport(t, "X-Request-Tag"))
+
+		hammer(t, func(ctx context.Context, tag string) error {
+			tagged := c.WithHeader(&http.Header{"X-Request-Tag": []string{tag}})
+			body, err := tagged.ExecuteRequestURIParams(ctx, RequestInput{
+				Method: http.MethodGet,
+				Path:   "/test.user/machines",
+				Query:  &url.Values{"tag": []string{tag}},
+			})
+			if err != nil {
+				return err
+			}
+			return body.Close()
+		})
+	})
+
+	t.Run("no headers leak", func(t *testing.T) {
+		c := newTestClient(echoHeaderTransport(t, "X-Request-Tag"))
+		c.RetryPolicy = testRetryPolicy()
+
+		hammer(t, func(ctx context.Context, tag string) error {
+			if tag[len(tag)-1]%2 == 0 {
+				tag = ""
+			}
+
+			query := &url.Values{}
+			if tag != "" {
+				query.Set("tag", tag)
+				ctx = ContextWithHeader(ctx, http.Header{"X-Request-Tag": []string{tag}})
+			}
+
+			body, err := c.ExecuteRequestRaw(ctx, RequestInput{
+				Method: http.MethodGet,
+				Path:   "/test.user/machines",
+				Query:  query,
+			})
+			if err != nil {
+				return err
+			}
+			return body.Body.Close()
+		})
+	})
+}
+
+func TestWithHeader(t *testing.T) {
+	c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return newTestResponse(http.StatusOK, `{}`), nil
+	}))
+
+	header := &http.Header{}
+	header.Set("X-Test-Header", "value")
+	tagged := c.WithHeader(header)
+	header.Set("X-Test-Header", "changed")
+
+	if c.boundHeader() != nil {
+		t.Error("expected original client to have no bound headers")
+	}
+	if got := tagged.boundHeader().Get("X-Test-Header"); got != "value" {
+		t.Errorf("expected bound header to be copied: got %q", got)
+	}
+	if cleared := tagged.WithHeader(nil); cleared.boundHeader() != nil {
+		t.Error("expected WithHeader(nil) to clear bound headers")
+	}
+}
+
+func TestSetHeader(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		seen[req.Header.Get("X-Request-Tag")] = true
+		mu.Unlock()
+		return newTestResponse(http.StatusOK, `{}`), nil
+	}))
+
+	input := RequestInput{Method: http.MethodGet, Path: "/test.user/machines"}
+	hammer(t, func(ctx context.Context, tag string) error {
+		header := &http.Header{"X-Request-Tag": []string{tag}}
+		c.SetHeader(header)
+		header.Set("X-Request-Tag", "modified")
+
+		body, err := c.ExecuteRequest(ctx, input)
+		if err != nil {
+			return err
+		}
+		return body.Close()
+	})
+
+	if seen["modified"] || seen[""] {
+		t.Errorf("expected every request to carry a header set before it: got %v", seen)
+	}
+
+	c.SetHeader(nil)
+	c.RequestHeader = &http.Header{"X-Request-Tag": []string{"deprecated"}}
+	for i := 0; i < 2; i++ {
+		body, err := c.ExecuteRequest(context.Background(), input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body.Close()
+	}
+	if len(seen) != concurrentRequests+1 || !seen["deprecated"] {
+		t.Errorf("expected RequestHeader to be set on requests: got %d tags", len(seen))
+	}
+	if tagged := c.WithHeader(nil); tagged.RequestHeader != nil {
+		t.Error("expected WithHeader to drop RequestHeader")
+	}
+}
@@ -0,0 +1,223 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	triton "github.com/joyent/triton-go/v2"
+	"github.com/joyent/triton-go/v2/authentication"
+	pkgerrors "github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// DefaultAuthError is returned by DefaultAuth when none of the default
+// credential sources provides a signer for the configured key. Errors holds
+// why each source was rejected, in the order they were tried.
+type DefaultAuthError struct {
+	KeyID  string
+	Errors []error
+}
+
+// Error implements interface Error on the DefaultAuthError type.
+func (e *DefaultAuthError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "unable to find a signer for key %s:", e.KeyID)
+	for _, err := range e.Errors {
+		fmt.Fprintf(&b, "\n  - %s", err)
+	}
+
+	return b.String()
+}
+
+// Cause makes DefaultAuthError compatible with pkgerrors.Cause, which reports
+// ErrDefaultAuth.
+func (e *DefaultAuthError) Cause() error {
+	return ErrDefaultAuth
+}
+
+// Unwrap makes DefaultAuthError compatible with errors.Is.
+func (e *DefaultAuthError) Unwrap() error {
+	return ErrDefaultAuth
+}
+
+// credentialSource looks up a signer for a key in one place.
+type credentialSource struct {
+	name   string
+	signer func(c *Client, keyID string) (authentication.Signer, error)
+}
+
+// defaultCredentialSources is the chain DefaultAuth walks, in order.
+var defaultCredentialSources = []credentialSource{
+	{"SSH agent", sshAgentCredentials},
+	{"TRITON_KEY_MATERIAL", envKeyCredentials("KEY_MATERIAL")},
+	{"TRITON_KEY_FILE", envKeyCredentials("KEY_FILE")},
+	{"~/.ssh", homeKeyCredentials},
+}
+
+// errSourceUnset marks a credential source which is not configured at all.
+var errSourceUnset = pkgerrors.New("not set")
+
+// DefaultAuth provides a default key signer for a client. This should only be
+// used if the client has no other key signer for authenticating with Triton.
+//
+// The key is identified by SDC_KEY_ID or TRITON_KEY_ID and looked up, in
+// order:
+//
+//   - in the SSH agent listening on SSH_AUTH_SOCK,
+//   - in TRITON_KEY_MATERIAL, holding either a private key or its path,
+//   - in the private key file at TRITON_KEY_FILE,
+//   - among the ~/.ssh/id_* private keys.
+//
+// Encrypted keys are decrypted with TRITON_KEY_PASSPHRASE. The first signer
+// found is added to the client's Authorizers. Otherwise a *DefaultAuthError
+// describing each step is returned.
+func (c *Client) DefaultAuth() error {
+	return c.defaultAuth(triton.GetEnv("KEY_ID"))
+}
+
+// defaultAuth walks the default credential chain for keyID.
+func (c *Client) defaultAuth(keyID string) error {
+	if keyID == "" {
+		return ErrDefaultAuth
+	}
+
+	authErr := &DefaultAuthError{KeyID: keyID}
+	for _, source := range defaultCredentialSources {
+		signer, err := source.signer(c, keyID)
+		if err != nil {
+			authErr.Errors = append(authErr.Errors, pkgerrors.Wrap(err, source.name))
+			continue
+		}
+
+		c.Authorizers = append(c.Authorizers, signer)
+		return nil
+	}
+
+	return authErr
+}
+
+func sshAgentCredentials(c *Client, keyID string) (authentication.Signer, error) {
+	signer, err := authentication.NewSSHAgentSigner(authentication.SSHAgentSignerInput{
+		KeyID:       keyID,
+		AccountName: c.AccountName,
+		Username:    c.Username,
+	})
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "unable to initialize NewSSHAgentSigner")
+	}
+
+	return signer, nil
+}
+
+// envKeyCredentials returns a credential source reading the private key, or
+// the path to it, from the given environment variable.
+func envKeyCredentials(name string) func(c *Client, keyID string) (authentication.Signer, error) {
+	return func(c *Client, keyID string) (authentication.Signer, error) {
+		value := triton.GetEnv(name)
+		if value == "" {
+			return nil, errSourceUnset
+		}
+
+		material := []byte(value)
+		if _, err := os.Stat(value); err == nil || name == "KEY_FILE" {
+			if material, err = ioutil.ReadFile(value); err != nil {
+				return nil, pkgerrors.Wrap(err, "unable to read private key")
+			}
+		}
+
+		return c.privateKeyCredentials(keyID, material)
+	}
+}
+
+func homeKeyCredentials(c *Client, keyID string) (authentication.Signer, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(home, ".ssh", "id_*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var tried []string
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".pub") {
+			continue
+		}
+		tried = append(tried, filepath.Base(path))
+
+		// Skip keys whose public half is known not to match, so that other
+		// keys' passphrases are not needed.
+		matched := false
+		if pub, err := readPublicKey(path + ".pub"); err == nil {
+			if !matchesFingerprint(pub, keyID) {
+				continue
+			}
+			matched = true
+		}
+
+		material, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		signer, err := c.privateKeyCredentials(keyID, material)
+		if err != nil {
+			if matched {
+				return nil, pkgerrors.Wrap(err, filepath.Base(path))
+			}
+			continue
+		}
+
+		return signer, nil
+	}
+
+	if len(tried) == 0 {
+		return nil, fmt.Errorf("no private keys found in %s", filepath.Join(home, ".ssh"))
+	}
+
+	return nil, fmt.Errorf("none of %s matches the key", strings.Join(tried, ", "))
+}
+
+func (c *Client) privateKeyCredentials(keyID string, material []byte) (authentication.Signer, error) {
+	return authentication.NewPrivateKeySigner(authentication.PrivateKeySignerInput{
+		KeyID:              keyID,
+		PrivateKeyMaterial: material,
+		AccountName:        c.AccountName,
+		Username:           c.Username,
+		Passphrase:         []byte(triton.GetEnv("KEY_PASSPHRASE")),
+	})
+}
+
+func readPublicKey(path string) (ssh.PublicKey, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(b)
+	return pub, err
+}
+
+// matchesFingerprint reports whether pub has the given MD5 or SHA256
+// fingerprint, in any of the formats accepted for TRITON_KEY_ID.
+func matchesFingerprint(pub ssh.PublicKey, fingerprint string) bool {
+	fingerprint = strings.TrimPrefix(fingerprint, "MD5:")
+	if strings.HasPrefix(fingerprint, "SHA256:") {
+		return fingerprint == ssh.FingerprintSHA256(pub)
+	}
+
+	md5 := strings.Replace(ssh.FingerprintLegacyMD5(pub), ":", "", -1)
+	return strings.Replace(fingerprint, ":", "", -1) == md5
+}
@@ -0,0 +1,180 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LimiterInput configures a Limiter. Zero values disable the respective
+// limit.
+type LimiterInput struct {
+	// RequestsPerSecond is the rate at which requests are let through on
+	// average.
+	RequestsPerSecond float64
+
+	// Burst is how many requests may be let through at once after a quiet
+	// period. It defaults to 1 when RequestsPerSecond is set.
+	Burst int
+
+	// MaxInFlight caps the number of requests awaiting a response at any
+	// time. A request leaves its slot as soon as the response headers
+	// arrive, so that a response body streamed while other requests are
+	// made, e.g. a directory listing being walked, cannot starve them.
+	MaxInFlight int
+}
+
+// LimiterStats reports how much a Limiter has slowed requests down.
+type LimiterStats struct {
+	// Requests is the number of requests let through.
+	Requests uint64
+
+	// Delayed is the number of requests which had to wait.
+	Delayed uint64
+
+	// TotalWait and MaxWait are the cumulated and longest time a request
+	// waited.
+	TotalWait time.Duration
+	MaxWait   time.Duration
+
+	// InFlight is the number of requests currently awaiting a response.
+	InFlight int
+}
+
+// Limiter throttles the requests a Client sends to one API endpoint with a
+// token bucket and caps how many of them are in flight. A Limiter is safe for
+// concurrent use and may be shared by several clients.
+type Limiter struct {
+	rate  float64
+	burst float64
+	slots chan struct{}
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	stats  LimiterStats
+}
+
+// NewLimiter returns a Limiter enforcing the limits of input.
+func NewLimiter(input LimiterInput) *Limiter {
+	l := &Limiter{
+		rate:  input.RequestsPerSecond,
+		burst: float64(input.Burst),
+	}
+	if l.burst < 1 {
+		l.burst = 1
+	}
+	l.tokens = l.burst
+
+	if input.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, input.MaxInFlight)
+	}
+
+	return l
+}
+
+// Stats returns the statistics of the limiter since it was created.
+func (l *Limiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats
+	stats.InFlight = len(l.slots)
+	return stats
+}
+
+// acquire waits until a request may be sent, or ctx is done. It returns how
+// long it waited and a function releasing the request's in-flight slot,
+// which must be called once its response headers were received.
+func (l *Limiter) acquire(ctx context.Context) (time.Duration, func(), error) {
+	if l == nil {
+		return 0, func() {}, nil
+	}
+
+	start := time.Now()
+	release := func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+			release = func() { <-l.slots }
+		case <-ctx.Done():
+			return time.Since(start), nil, ctx.Err()
+		}
+	}
+
+	if delay := l.reserve(); delay > 0 {
+		if err := sleepContext(ctx, delay); err != nil {
+			l.cancel()
+			release()
+			return time.Since(start), nil, err
+		}
+	}
+
+	waited := time.Since(start)
+	l.record(waited)
+
+	return waited, release, nil
+}
+
+// reserve takes a token from the bucket, returning how long to wait until it
+// is available.
+func (l *Limiter) reserve() time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns the token of a request which was abandoned while waiting.
+func (l *Limiter) cancel() {
+	if l.rate <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
+
+func (l *Limiter) record(waited time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats.Requests++
+	// Ignore the scheduling noise of requests which were let through at
+	// once.
+	if waited < time.Millisecond {
+		return
+	}
+	l.stats.Delayed++
+	l.stats.TotalWait += waited
+	if waited > l.stats.MaxWait {
+		l.stats.MaxWait = waited
+	}
+}
@@ -0,0 +1,124 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	input := RequestInput{Method: http.MethodGet, Path: "/test.user/machines"}
+
+	t.Run("rate", func(t *testing.T) {
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return newTestResponse(http.StatusOK, `{}`), nil
+		}))
+		c.TritonLimiter = NewLimiter(LimiterInput{RequestsPerSecond: 100, Burst: 2})
+
+		start := time.Now()
+		for i := 0; i < 6; i++ {
+			if _, err := c.ExecuteRequest(context.Background(), input); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Two requests pass at once, the other four are spaced by 10ms.
+		if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+			t.Errorf("expected requests to be throttled: took %s", elapsed)
+		}
+		stats := c.TritonLimiter.Stats()
+		if stats.Requests != 6 || stats.Delayed < 3 || stats.TotalWait == 0 || stats.MaxWait == 0 {
+			t.Errorf("expected wait time to be recorded: got %+v", stats)
+		}
+	})
+
+	t.Run("max in flight", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return newTestResponse(http.StatusOK, `{}`), nil
+		}))
+		c.TritonLimiter = NewLimiter(LimiterInput{MaxInFlight: 2})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				body, err := c.ExecuteRequest(context.Background(), input)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				body.Close()
+			}()
+		}
+		wg.Wait()
+
+		if maxInFlight != 2 {
+			t.Errorf("expected at most 2 requests in flight: got %d", maxInFlight)
+		}
+		if stats := c.TritonLimiter.Stats(); stats.InFlight != 0 || stats.Requests != 8 {
+			t.Errorf("expected all slots to be released: got %+v", stats)
+		}
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		var sent int
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent++
+			return newTestResponse(http.StatusOK, `{}`), nil
+		}))
+		c.TritonLimiter = NewLimiter(LimiterInput{RequestsPerSecond: 0.1})
+
+		if _, err := c.ExecuteRequest(context.Background(), input); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if _, err := c.ExecuteRequest(ctx, input); err == nil {
+			t.Fatal("expected the request to time out waiting for the limiter")
+		}
+		if sent != 1 {
+			t.Errorf("expected one request to be sent: got %d", sent)
+		}
+	})
+
+	t.Run("per endpoint", func(t *testing.T) {
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return newTestResponse(http.StatusOK, `{}`), nil
+		}))
+		c.TritonLimiter = NewLimiter(LimiterInput{})
+		c.MantaLimiter = NewLimiter(LimiterInput{})
+
+		if _, _, err := c.ExecuteRequestStorage(context.Background(), input); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.MantaLimiter.Stats().Requests; got != 1 {
+			t.Errorf("expected Manta request to use MantaLimiter: got %d requests", got)
+		}
+		if got := c.TritonLimiter.Stats().Requests; got != 0 {
+			t.Errorf("expected TritonLimiter to be unused: got %d requests", got)
+		}
+	})
+}
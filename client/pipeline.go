@@ -0,0 +1,319 @@
+//
+// Copyright 2020 Joyent, Inc.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	triton "github.com/joyent/triton-go/v2"
+	"github.com/joyent/triton-go/v2/authentication"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Middleware hooks into the request pipeline shared by every Execute* method
+// of a Client, regardless of whether the request targets CloudAPI, Manta or
+// TSG. Either hook may be nil.
+type Middleware struct {
+	// BeforeSign is called for every attempt of a request, after all headers
+	// have been set and right before the request is signed. Returning an
+	// error aborts the request.
+	BeforeSign func(req *http.Request) error
+
+	// AfterResponse is called for every attempt of a request with the
+	// response or transport error returned by the HTTP client. The returned
+	// response and error replace the ones passed in, which allows responses
+	// to be inspected, rewritten or faked. It must return either a response
+	// or an error; returning neither fails the request with
+	// ErrNilResponse.
+	AfterResponse func(req *http.Request, resp *http.Response, err error) (*http.Response, error)
+}
+
+// ErrNilResponse is returned for requests whose AfterResponse middleware
+// returned neither a response nor an error.
+var ErrNilResponse = pkgerrors.New("middleware returned neither a response nor an error")
+
+// Use appends middleware to the request pipeline of the client. Middleware
+// runs in the order it was added. Use is not safe to call while requests are
+// being executed.
+func (c *Client) Use(middleware ...Middleware) {
+	// Always reallocate, so copies made by WithHeader never share the
+	// backing array with the client.
+	n := len(c.Middleware)
+	c.Middleware = append(c.Middleware[:n:n], middleware...)
+}
+
+// apiRequest describes a request before it is turned into an *http.Request
+// by the pipeline.
+type apiRequest struct {
+	// operation names the API call for logs and traces.
+	operation string
+
+	endpoint url.URL
+	method   string
+	path     string
+	query    *url.Values
+	headers  *http.Header
+	body     io.Reader
+
+	// jsonBody is true when body holds a marshaled JSON document.
+	jsonBody bool
+
+	// isManta selects the Manta flavor of request signing and headers.
+	isManta bool
+
+	// acceptVersion sets the Accept-Version header on Manta requests too.
+	acceptVersion bool
+
+	// preserveGone returns the response of an HTTP 410 alongside its error
+	// without consuming the body.
+	preserveGone bool
+
+	// limiter throttles requests to endpoint. It may be nil.
+	limiter *Limiter
+}
+
+// newAPIRequest builds an apiRequest from a RequestInput, marshaling its body
+// as JSON.
+func newAPIRequest(endpoint url.URL, inputs RequestInput, isManta bool) (apiRequest, error) {
+	req := apiRequest{
+		operation:    inputs.Operation,
+		endpoint:     endpoint,
+		method:       inputs.Method,
+		path:         inputs.Path,
+		query:        inputs.Query,
+		headers:      inputs.Headers,
+		isManta:      isManta,
+		preserveGone: inputs.PreserveGone,
+	}
+
+	if inputs.Body != nil {
+		marshaled, err := json.MarshalIndent(inputs.Body, "", "    ")
+		if err != nil {
+			return req, err
+		}
+		req.body = bytes.NewReader(marshaled)
+		req.jsonBody = true
+	}
+
+	return req, nil
+}
+
+// newHTTPRequest constructs the *http.Request for r, setting the default
+// headers for the targeted API followed by the headers of r and finally the
+// headers bound to the client or ctx.
+func (c *Client) newHTTPRequest(ctx context.Context, r apiRequest) (*http.Request, error) {
+	endpoint := r.endpoint
+	endpoint.Path = r.path
+	if r.query != nil {
+		endpoint.RawQuery = r.query.Encode()
+	}
+
+	req, err := http.NewRequest(r.method, endpoint.String(), r.body)
+	if err != nil {
+		return nil, pkgerrors.Wrapf(err, "unable to construct HTTP request")
+	}
+
+	if r.isManta {
+		req.Header.Set("Accept", "*/*")
+		if r.acceptVersion {
+			req.Header.Set("Accept-Version", triton.CloudAPIMajorVersion)
+		}
+	} else {
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Accept-Version", triton.CloudAPIMajorVersion)
+	}
+	req.Header.Set("User-Agent", triton.UserAgent())
+
+	if r.jsonBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if r.headers != nil {
+		for key, values := range *r.headers {
+			for _, value := range values {
+				req.Header.Set(key, value)
+			}
+		}
+	}
+
+	c.overrideHeader(ctx, req)
+
+	return req, nil
+}
+
+// execute runs r through the request pipeline. Responses with a status code
+// in the 2xx range are returned to the caller, every other response is
+// decoded into an error.
+func (c *Client) execute(ctx context.Context, r apiRequest) (resp *http.Response, err error) {
+	req, err := c.newHTTPRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, call := c.startCall(ctx, r)
+	defer func() {
+		call.end(ctx, resp, err)
+	}()
+
+	resp, err = c.doRequest(ctx, req, r, call)
+	if err != nil {
+		return nil, err
+	}
+
+	// We will only return a response from the API it is in the HTTP StatusCode
+	// 2xx range
+	// StatusMultipleChoices is StatusCode 300
+	if resp.StatusCode >= http.StatusOK &&
+		resp.StatusCode < http.StatusMultipleChoices {
+		return resp, nil
+	}
+
+	// GetMachine returns a HTTP 410 response for deleted instances, but the
+	// body of the response is still a valid machine object with a State value
+	// of "deleted". Return the object to the caller as well as an error.
+	if r.preserveGone && resp.StatusCode == http.StatusGone {
+		// Do not consume the response body.
+		return resp, decodeError(resp, req.Method, req.URL.Path, false)
+	}
+
+	defer resp.Body.Close()
+
+	return nil, decodeError(resp, req.Method, req.URL.Path, true)
+}
+
+// signRequest sets the date header of req and signs it with signer, covering
+// the client's SignedHeaders when signer supports it. It is called for every
+// attempt of a request, so that retried requests carry a fresh date.
+func (c *Client) signRequest(req *http.Request, isManta bool, signer authentication.Signer) error {
+	dateHeader := time.Now().UTC().Format(time.RFC1123)
+	req.Header.Set("date", dateHeader)
+
+	var authHeader string
+	var err error
+	if headersSigner, ok := signer.(authentication.HeadersSigner); ok && len(c.SignedHeaders) > 0 {
+		var headers []authentication.SignedHeader
+		headers, err = signedHeaderValues(req, c.SignedHeaders)
+		if err == nil {
+			authHeader, err = headersSigner.SignHeaders(headers, isManta)
+		}
+	} else {
+		authHeader, err = signer.Sign(dateHeader, isManta)
+	}
+	if err != nil {
+		return pkgerrors.Wrapf(err, "unable to sign HTTP request")
+	}
+	req.Header.Set("Authorization", authHeader)
+
+	return nil
+}
+
+// prepare runs the BeforeSign middleware for req and signs it with signer.
+func (c *Client) prepare(req *http.Request, isManta bool, signer authentication.Signer) error {
+	for _, mw := range c.Middleware {
+		if mw.BeforeSign == nil {
+			continue
+		}
+		if err := mw.BeforeSign(req); err != nil {
+			return err
+		}
+	}
+
+	return c.signRequest(req, isManta, signer)
+}
+
+// roundTrip sends req once and runs the AfterResponse middleware on the
+// outcome.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.HTTPClient.Do(req)
+	for _, mw := range c.Middleware {
+		if mw.AfterResponse != nil {
+			resp, err = mw.AfterResponse(req, resp, err)
+		}
+	}
+	if resp == nil && err == nil {
+		return nil, ErrNilResponse
+	}
+
+	return resp, err
+}
+
+// doRequest signs and sends req, retrying it according to the client's
+// RetryPolicy. Every attempt passes through the client's middleware.
+//
+// Requests are signed with the client's active signer first. When the API
+// rejects its key, the request is sent again signed with the next of the
+// client's authorizers, and the first one accepted becomes the active
+// signer. Switching signers does not count as a retry. Retries are recorded
+// on call, which may be nil.
+//
+// Every attempt waits for the limiter of r before it is signed, so that its
+// date header is not stale.
+func (c *Client) doRequest(ctx context.Context, req *http.Request, r apiRequest, call *apiCall) (*http.Response, error) {
+	req = req.WithContext(ctx)
+
+	signers := c.signerOrder()
+	if len(signers) == 0 {
+		return nil, ErrMissingSigner
+	}
+
+	for attempt := 1; ; attempt++ {
+		waited, release, err := r.limiter.acquire(ctx)
+		call.wait(waited)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "unable to execute HTTP request")
+		}
+
+		signer := signers[0]
+		if err := c.prepare(req, r.isManta, signer); err != nil {
+			release()
+			return nil, err
+		}
+
+		resp, err := c.roundTrip(req)
+		release()
+		if err == nil && len(signers) > 1 && isRewindable(req) && isAuthFailure(req, resp) {
+			discardResponse(resp)
+			if err := rewindBody(req); err != nil {
+				return nil, pkgerrors.Wrapf(err, "unable to rewind HTTP request body")
+			}
+			signers = signers[1:]
+			attempt--
+			continue
+		}
+		if err == nil &&
+			resp.StatusCode != http.StatusUnauthorized &&
+			resp.StatusCode != http.StatusForbidden {
+			c.rememberSigner(signer)
+		}
+
+		if !c.RetryPolicy.shouldRetry(req, resp, err, attempt) {
+			if err != nil {
+				return nil, pkgerrors.Wrapf(err, "unable to execute HTTP request")
+			}
+			return resp, nil
+		}
+
+		wait := c.RetryPolicy.backoff(attempt, resp)
+		call.retry(ctx, attempt, wait, resp, err)
+		discardResponse(resp)
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, pkgerrors.Wrapf(err, "unable to execute HTTP request")
+		}
+		if err := rewindBody(req); err != nil {
+			return nil, pkgerrors.Wrapf(err, "unable to rewind HTTP request body")
+		}
+	}
+}
@@ -0,0 +1,159 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	triton "github.com/joyent/triton-go/v2"
+	"github.com/joyent/triton-go/v2/errors"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Run("runs for every API", func(t *testing.T) {
+		var hosts []string
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return newTestResponse(http.StatusOK, `{}`), nil
+		}))
+		c.ServicesURL = c.TritonURL
+		c.ServicesURL.Host = "tsg.us-east-1.svc.joyent.zone"
+		c.Use(Middleware{
+			BeforeSign: func(req *http.Request) error {
+				if req.Header.Get("Authorization") != "" {
+					t.Error("expected BeforeSign to run before signing")
+				}
+				req.Header.Set("X-Audit", "yes")
+				return nil
+			},
+			AfterResponse: func(req *http.Request, resp *http.Response, err error) (*http.Response, error) {
+				if req.Header.Get("X-Audit") != "yes" {
+					t.Error("expected header set in BeforeSign to be sent")
+				}
+				hosts = append(hosts, req.URL.Host)
+				return resp, err
+			},
+		})
+
+		ctx := context.Background()
+		input := RequestInput{Method: http.MethodGet, Path: "/test"}
+		if _, err := c.ExecuteRequest(ctx, input); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := c.ExecuteRequestStorage(ctx, input); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.ExecuteRequestTSG(ctx, input); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "us-east-1.api.joyent.com,us-east.manta.joyent.com,tsg.us-east-1.svc.joyent.zone"
+		if got := strings.Join(hosts, ","); got != expected {
+			t.Errorf("expected middleware to see %q: got %q", expected, got)
+		}
+	})
+
+	t.Run("BeforeSign aborts request", func(t *testing.T) {
+		sent := false
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = true
+			return newTestResponse(http.StatusOK, `{}`), nil
+		}))
+		abort := pkgerrors.New("aborted")
+		c.Use(Middleware{
+			BeforeSign: func(req *http.Request) error {
+				return abort
+			},
+		})
+
+		_, err := c.ExecuteRequest(context.Background(), RequestInput{Method: http.MethodGet, Path: "/test"})
+		if err != abort {
+			t.Errorf("expected middleware error: received %v", err)
+		}
+		if sent {
+			t.Error("expected request not to be sent")
+		}
+	})
+
+	t.Run("AfterResponse injects faults", func(t *testing.T) {
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return newTestResponse(http.StatusOK, `{}`), nil
+		}))
+		c.Use(Middleware{
+			AfterResponse: func(req *http.Request, resp *http.Response, err error) (*http.Response, error) {
+				resp.Body.Close()
+				return newTestResponse(http.StatusNotFound, `{"code":"ResourceNotFound","message":"gone fishing"}`), nil
+			},
+		})
+
+		_, err := c.ExecuteRequest(context.Background(), RequestInput{Method: http.MethodGet, Path: "/test"})
+		if !errors.IsResourceNotFound(err) {
+			t.Errorf("expected injected ResourceNotFound error: received %v", err)
+		}
+	})
+}
+
+func TestMiddlewareNilResponse(t *testing.T) {
+	attempts := 0
+	c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return newTestResponse(http.StatusOK, `{}`), nil
+	}))
+	c.RetryPolicy = testRetryPolicy()
+	c.Use(Middleware{
+		AfterResponse: func(req *http.Request, resp *http.Response, err error) (*http.Response, error) {
+			resp.Body.Close()
+			return nil, nil
+		},
+	})
+
+	_, err := c.ExecuteRequest(context.Background(), RequestInput{Method: http.MethodGet, Path: "/test"})
+	if pkgerrors.Cause(err) != ErrNilResponse {
+		t.Errorf("expected ErrNilResponse: received %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected the request not to be retried: got %d attempts", attempts)
+	}
+}
+
+func TestExecuteRequestNoEncodeHeaders(t *testing.T) {
+	c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if got := req.Header.Get("Accept-Version"); got != triton.CloudAPIMajorVersion {
+			t.Errorf("expected Accept-Version %q: got %q", triton.CloudAPIMajorVersion, got)
+		}
+		if got := req.Header.Get("Accept"); got != "*/*" {
+			t.Errorf("expected Accept */*: got %q", got)
+		}
+		return newTestResponse(http.StatusOK, `{}`), nil
+	}))
+
+	body, _, err := c.ExecuteRequestNoEncode(context.Background(), RequestNoEncodeInput{
+		Method: http.MethodPut,
+		Path:   "/test.user/stor/object",
+		Body:   strings.NewReader("data"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body.Close()
+}
+
+func TestExecuteRequestTSGDecodesErrors(t *testing.T) {
+	c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return newTestResponse(http.StatusBadRequest, `{"code":"InvalidArgument","message":"bad template"}`), nil
+	}))
+
+	_, err := c.ExecuteRequestTSG(context.Background(), RequestInput{Method: http.MethodGet, Path: "/v1/tsg/templates"})
+	if !errors.IsInvalidArgument(err) {
+		t.Errorf("expected InvalidArgument error: received %v", err)
+	}
+}
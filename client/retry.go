@@ -0,0 +1,183 @@
+//
+// Copyright 2020 Joyent, Inc.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryMinBackoff  = 500 * time.Millisecond
+	defaultRetryMaxBackoff  = 30 * time.Second
+)
+
+// RetryPolicy describes how a Client retries requests which failed because
+// the remote API throttled them (HTTP 429), was temporarily unavailable (HTTP
+// 503) or because the connection failed before a response was received.
+//
+// Only requests whose body can be replayed are retried. Connection failures
+// are additionally only retried for idempotent methods, since the server may
+// have acted on the request before the connection was lost.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a single request,
+	// including the first one. A value lower than 2 disables retries.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry. The delay doubles with
+	// every subsequent retry.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts. A Retry-After header
+	// returned by the server takes precedence over this value.
+	MaxBackoff time.Duration
+
+	// DisableJitter turns off the randomization of the computed backoff.
+	DisableJitter bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with sensible defaults for use
+// against CloudAPI, Manta and TSG.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		MinBackoff:  defaultRetryMinBackoff,
+		MaxBackoff:  defaultRetryMaxBackoff,
+	}
+}
+
+// shouldRetry reports whether another attempt should be made for req after
+// receiving resp and err on the given attempt (starting at 1).
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+
+	if req.Context().Err() != nil || !isRewindable(req) {
+		return false
+	}
+
+	if err != nil {
+		return err != ErrNilResponse && isIdempotent(req.Method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	}
+
+	return false
+}
+
+// backoff returns how long to wait before the attempt following the given
+// one (starting at 1).
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return wait
+		}
+	}
+
+	wait := p.MinBackoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+
+	if !p.DisableJitter && wait > 1 {
+		half := wait / 2
+		wait = half + time.Duration(rand.Int63n(int64(half)+1))
+	}
+
+	return wait
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+
+	return false
+}
+
+func isRewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewindBody resets the body of req so that it can be sent again.
+func rewindBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+
+	return nil
+}
+
+// discardResponse drains and closes the body of a response which will not be
+// handed back to the caller, allowing the connection to be reused.
+func discardResponse(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+}
+
+// sleepContext waits for the given duration, returning early with the
+// context's error if it is cancelled first.
+func sleepContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
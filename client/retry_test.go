@@ -0,0 +1,261 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	auth "github.com/joyent/triton-go/v2/authentication"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newTestClient(rt http.RoundTripper) *Client {
+	signer, _ := auth.NewTestSigner()
+	tritonURL, _ := url.Parse("https://us-east-1.api.joyent.com")
+	mantaURL, _ := url.Parse("https://us-east.manta.joyent.com")
+
+	return &Client{
+		HTTPClient:  &http.Client{Transport: rt},
+		Authorizers: []auth.Signer{signer},
+		TritonURL:   *tritonURL,
+		MantaURL:    *mantaURL,
+		AccountName: "test.user",
+	}
+}
+
+func newTestResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("retries throttled requests", func(t *testing.T) {
+		attempts := 0
+		dates := map[string]bool{}
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			dates[req.Header.Get("Authorization")] = true
+			if attempts < 3 {
+				return newTestResponse(http.StatusTooManyRequests, `{"code":"RequestThrottled"}`), nil
+			}
+			return newTestResponse(http.StatusOK, `{}`), nil
+		}))
+		c.RetryPolicy = testRetryPolicy()
+
+		body, err := c.ExecuteRequest(context.Background(), RequestInput{
+			Method: http.MethodGet,
+			Path:   "/test.user/machines",
+		})
+		if err != nil {
+			t.Fatalf("expected error to be nil: received %v", err)
+		}
+		body.Close()
+
+		if attempts != 3 {
+			t.Errorf("expected 3 attempts: got %d", attempts)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return newTestResponse(http.StatusServiceUnavailable, `{"code":"ServiceUnavailable"}`), nil
+		}))
+		c.RetryPolicy = testRetryPolicy()
+
+		_, err := c.ExecuteRequest(context.Background(), RequestInput{
+			Method: http.MethodGet,
+			Path:   "/test.user/machines",
+		})
+		if err == nil {
+			t.Fatal("expected error not to be nil")
+		}
+
+		if attempts != 3 {
+			t.Errorf("expected 3 attempts: got %d", attempts)
+		}
+	})
+
+	t.Run("replays request body", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			body, _ := ioutil.ReadAll(req.Body)
+			if !strings.Contains(string(body), `"name": "test"`) {
+				t.Errorf("expected body to be replayed on attempt %d: got %q", attempts, body)
+			}
+			if attempts == 1 {
+				return newTestResponse(http.StatusServiceUnavailable, `{}`), nil
+			}
+			return newTestResponse(http.StatusOK, `{}`), nil
+		}))
+		c.RetryPolicy = testRetryPolicy()
+
+		body, err := c.ExecuteRequest(context.Background(), RequestInput{
+			Method: http.MethodPost,
+			Path:   "/test.user/machines",
+			Body:   map[string]string{"name": "test"},
+		})
+		if err != nil {
+			t.Fatalf("expected error to be nil: received %v", err)
+		}
+		body.Close()
+
+		if attempts != 2 {
+			t.Errorf("expected 2 attempts: got %d", attempts)
+		}
+	})
+
+	t.Run("does not retry non-idempotent connection errors", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return nil, syscall.ECONNRESET
+		}))
+		c.RetryPolicy = testRetryPolicy()
+
+		_, err := c.ExecuteRequest(context.Background(), RequestInput{
+			Method: http.MethodPost,
+			Path:   "/test.user/machines",
+		})
+		if err == nil {
+			t.Fatal("expected error not to be nil")
+		}
+
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt: got %d", attempts)
+		}
+	})
+
+	t.Run("does not retry unrewindable bodies", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return newTestResponse(http.StatusServiceUnavailable, `{}`), nil
+		}))
+		c.RetryPolicy = testRetryPolicy()
+
+		_, _, err := c.ExecuteRequestNoEncode(context.Background(), RequestNoEncodeInput{
+			Method: http.MethodPut,
+			Path:   "/test.user/stor/object",
+			Body:   ioutil.NopCloser(strings.NewReader("data")),
+		})
+		if err == nil {
+			t.Fatal("expected error not to be nil")
+		}
+
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt: got %d", attempts)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		attempts := 0
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return newTestResponse(http.StatusTooManyRequests, `{}`), nil
+		}))
+
+		_, _, err := c.ExecuteRequestStorage(context.Background(), RequestInput{
+			Method: http.MethodGet,
+			Path:   "/test.user/stor",
+		})
+		if err == nil {
+			t.Fatal("expected error not to be nil")
+		}
+
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt: got %d", attempts)
+		}
+	})
+
+	t.Run("stops on context cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			cancel()
+			resp := newTestResponse(http.StatusTooManyRequests, `{}`)
+			resp.Header.Set("Retry-After", "60")
+			return resp, nil
+		}))
+		c.RetryPolicy = testRetryPolicy()
+
+		_, err := c.ExecuteRequestTSG(ctx, RequestInput{
+			Method: http.MethodGet,
+			Path:   "/v1/tsg/groups",
+		})
+		if err == nil {
+			t.Fatal("expected error not to be nil")
+		}
+
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt: got %d", attempts)
+		}
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:   5,
+		MinBackoff:    100 * time.Millisecond,
+		MaxBackoff:    300 * time.Millisecond,
+		DisableJitter: true,
+	}
+
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		300 * time.Millisecond,
+		300 * time.Millisecond,
+	}
+	for i, want := range expected {
+		if got := policy.backoff(i+1, nil); got != want {
+			t.Errorf("attempt %d: expected backoff %s: got %s", i+1, want, got)
+		}
+	}
+
+	resp := newTestResponse(http.StatusTooManyRequests, "")
+	resp.Header.Set("Retry-After", "2")
+	if got := policy.backoff(1, resp); got != 2*time.Second {
+		t.Errorf("expected Retry-After to be honoured: got %s", got)
+	}
+
+	jittered := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for i := 0; i < 10; i++ {
+		got := jittered.backoff(1, nil)
+		if got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Errorf("expected jittered backoff between 50ms and 100ms: got %s", got)
+		}
+	}
+}
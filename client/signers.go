@@ -0,0 +1,181 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/joyent/triton-go/v2/authentication"
+)
+
+// maxAuthErrorSize bounds how much of a 401 or 403 response body is read to
+// find out whether the request was rejected because of its signer.
+const maxAuthErrorSize = 64 * 1024
+
+// authFailureCodes are the API error codes which mean that the key a request
+// was signed with was not accepted, so that another signer may succeed.
+var authFailureCodes = map[string]bool{
+	"InvalidSignature": true,
+	"InvalidKeyId":     true,
+	"KeyDoesNotExist":  true,
+}
+
+// signerChain remembers which of a client's authorizers last authenticated
+// successfully. It is shared by all copies of a client made by WithHeader.
+type signerChain struct {
+	mu     sync.Mutex
+	active authentication.Signer
+}
+
+// ActiveSigner returns the signer requests are signed with first: the last
+// one of c.Authorizers the API accepted, or the first of them if none has
+// been accepted yet. It returns nil if the client has no authorizers.
+func (c *Client) ActiveSigner() authentication.Signer {
+	signers := c.signerOrder()
+	if len(signers) == 0 {
+		return nil
+	}
+
+	return signers[0]
+}
+
+// signerOrder returns c.Authorizers with the active signer moved to the
+// front. The remaining signers keep their configured order.
+func (c *Client) signerOrder() []authentication.Signer {
+	if c.signers == nil {
+		return c.Authorizers
+	}
+
+	c.signers.mu.Lock()
+	active := c.signers.active
+	c.signers.mu.Unlock()
+
+	for i, signer := range c.Authorizers {
+		if signer != active || i == 0 {
+			continue
+		}
+
+		ordered := make([]authentication.Signer, 0, len(c.Authorizers))
+		ordered = append(ordered, signer)
+		ordered = append(ordered, c.Authorizers[:i]...)
+		ordered = append(ordered, c.Authorizers[i+1:]...)
+		return ordered
+	}
+
+	return c.Authorizers
+}
+
+// rememberSigner makes signer the active signer of the client.
+func (c *Client) rememberSigner(signer authentication.Signer) {
+	if c.signers == nil {
+		return
+	}
+
+	c.signers.mu.Lock()
+	c.signers.active = signer
+	c.signers.mu.Unlock()
+}
+
+// isAuthFailure reports whether resp rejects the key its request was signed
+// with. Bodies of 401 and 403 responses are buffered so that they can still
+// be decoded by the caller.
+func isAuthFailure(req *http.Request, resp *http.Response) bool {
+	if resp == nil ||
+		resp.StatusCode != http.StatusUnauthorized &&
+			resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+
+	// HEAD responses carry no error body to inspect.
+	if req.Method == http.MethodHead || resp.Body == nil {
+		return resp.StatusCode == http.StatusUnauthorized
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxAuthErrorSize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+
+	var apiErr struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		return false
+	}
+
+	return authFailureCodes[apiErr.Code]
+}
+
+// signedHeaderValues looks up the values of the named headers of req for
+// signing. A Content-MD5 header is computed for requests with a rewindable
+// body. Other headers req does not carry are left out of the signature.
+func signedHeaderValues(req *http.Request, names []string) ([]authentication.SignedHeader, error) {
+	headers := make([]authentication.SignedHeader, 0, len(names))
+	for _, name := range names {
+		var value string
+		switch name = strings.ToLower(name); name {
+		case authentication.RequestTarget:
+			value = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			value = req.URL.Host
+			if req.Host != "" {
+				value = req.Host
+			}
+		case "content-md5":
+			if err := setContentMD5(req); err != nil {
+				return nil, err
+			}
+			value = req.Header.Get(name)
+		default:
+			value = req.Header.Get(name)
+		}
+
+		if value == "" {
+			continue
+		}
+		headers = append(headers, authentication.SignedHeader{Name: name, Value: value})
+	}
+
+	return headers, nil
+}
+
+// setContentMD5 sets the Content-MD5 header of req to the digest of its body,
+// unless the header is already set or the body cannot be read twice.
+func setContentMD5(req *http.Request) error {
+	if req.Header.Get("Content-MD5") != "" ||
+		req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return err
+	}
+	req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(hash.Sum(nil)))
+
+	return nil
+}
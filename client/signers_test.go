@@ -0,0 +1,267 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/sha512"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	auth "github.com/joyent/triton-go/v2/authentication"
+	"github.com/joyent/triton-go/v2/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// namedSigner signs requests with its name, so that tests can tell which
+// signer was used.
+type namedSigner struct {
+	name string
+}
+
+func (s *namedSigner) DefaultAlgorithm() string { return "rsa-sha256" }
+func (s *namedSigner) KeyFingerprint() string   { return s.name }
+func (s *namedSigner) Sign(dateHeader string, isManta bool) (string, error) {
+	return s.name, nil
+}
+func (s *namedSigner) SignRaw(toSign string) (string, string, error) {
+	return s.name, "rsa-sha256", nil
+}
+
+// rejectKeysTransport answers requests signed with any of the rejected keys
+// with the given error code, and records the key of every request.
+func rejectKeysTransport(used *[]string, status int, code string, rejected ...string) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		key := req.Header.Get("Authorization")
+		*used = append(*used, key)
+		for _, r := range rejected {
+			if key == r {
+				return newTestResponse(status, `{"code":"`+code+`","message":"rejected"}`), nil
+			}
+		}
+		return newTestResponse(http.StatusOK, `{}`), nil
+	})
+}
+
+func newSignerTestClient(rt http.RoundTripper, names ...string) *Client {
+	c := newTestClient(rt)
+	c.signers = &signerChain{}
+	c.Authorizers = nil
+	for _, name := range names {
+		c.Authorizers = append(c.Authorizers, &namedSigner{name: name})
+	}
+	return c
+}
+
+func TestSignerFallback(t *testing.T) {
+	input := RequestInput{Method: http.MethodGet, Path: "/test.user/machines"}
+
+	t.Run("falls back and remembers", func(t *testing.T) {
+		var used []string
+		c := newSignerTestClient(rejectKeysTransport(&used, http.StatusUnauthorized, "InvalidKeyId", "agent"), "agent", "file")
+
+		for i := 0; i < 2; i++ {
+			if _, err := c.ExecuteRequest(context.Background(), input); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if got := strings.Join(used, ","); got != "agent,file,file" {
+			t.Errorf("expected keys agent,file,file to be used: got %s", got)
+		}
+		if got := c.ActiveSigner().KeyFingerprint(); got != "file" {
+			t.Errorf("expected file to be the active signer: got %s", got)
+		}
+		if got := c.WithHeader(nil).ActiveSigner().KeyFingerprint(); got != "file" {
+			t.Errorf("expected copies to share the active signer: got %s", got)
+		}
+	})
+
+	t.Run("resends request body", func(t *testing.T) {
+		var bodies []string
+		c := newSignerTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			if req.Header.Get("Authorization") == "agent" {
+				return newTestResponse(http.StatusForbidden, `{"code":"KeyDoesNotExist","message":"rejected"}`), nil
+			}
+			return newTestResponse(http.StatusOK, `{}`), nil
+		}), "agent", "file")
+
+		_, err := c.ExecuteRequest(context.Background(), RequestInput{
+			Method: http.MethodPost,
+			Path:   "/test.user/machines",
+			Body:   map[string]string{"name": "test"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[0] == "" {
+			t.Errorf("expected the body to be sent twice: got %q", bodies)
+		}
+	})
+
+	t.Run("other errors do not fall back", func(t *testing.T) {
+		var used []string
+		c := newSignerTestClient(rejectKeysTransport(&used, http.StatusForbidden, "NotAuthorized", "agent"), "agent", "file")
+
+		_, err := c.ExecuteRequest(context.Background(), input)
+		if !errors.IsSpecificError(err, "NotAuthorized") {
+			t.Errorf("expected NotAuthorized error: got %v", err)
+		}
+		if len(used) != 1 {
+			t.Errorf("expected one request: got %d", len(used))
+		}
+	})
+
+	t.Run("all signers rejected", func(t *testing.T) {
+		var used []string
+		c := newSignerTestClient(rejectKeysTransport(&used, http.StatusUnauthorized, "InvalidSignature", "agent", "file"), "agent", "file")
+
+		_, err := c.ExecuteRequest(context.Background(), input)
+		if !errors.IsInvalidSignatureError(err) {
+			t.Errorf("expected InvalidSignature error: got %v", err)
+		}
+		if got := strings.Join(used, ","); got != "agent,file" {
+			t.Errorf("expected keys agent,file to be used: got %s", got)
+		}
+		if got := c.ActiveSigner().KeyFingerprint(); got != "agent" {
+			t.Errorf("expected active signer to be unchanged: got %s", got)
+		}
+	})
+
+	t.Run("no signers", func(t *testing.T) {
+		c := newSignerTestClient(rejectKeysTransport(new([]string), http.StatusOK, ""))
+
+		if _, err := c.ExecuteRequest(context.Background(), input); err != ErrMissingSigner {
+			t.Errorf("expected ErrMissingSigner: got %v", err)
+		}
+		if c.ActiveSigner() != nil {
+			t.Error("expected no active signer")
+		}
+	})
+}
+
+// headersSigner records the headers it is asked to sign.
+type headersSigner struct {
+	namedSigner
+	signed []auth.SignedHeader
+}
+
+func (s *headersSigner) SignHeaders(headers []auth.SignedHeader, isManta bool) (string, error) {
+	s.signed = headers
+	return s.name + "-headers", nil
+}
+
+func TestSignedHeaders(t *testing.T) {
+	var authHeader string
+	var sentBody []byte
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		authHeader = req.Header.Get("Authorization")
+		sentBody, _ = ioutil.ReadAll(req.Body)
+		return newTestResponse(http.StatusOK, `{}`), nil
+	})
+	input := RequestInput{
+		Method: http.MethodPost,
+		Path:   "/test.user/machines",
+		Query:  &url.Values{"action": []string{"start"}},
+		Body:   map[string]string{"name": "test"},
+	}
+
+	t.Run("signs listed headers", func(t *testing.T) {
+		signer := &headersSigner{namedSigner: namedSigner{name: "key"}}
+		c := newTestClient(rt)
+		c.Authorizers = []auth.Signer{signer}
+		c.SignedHeaders = []string{"(request-target)", "Host", "date", "content-md5", "x-missing"}
+
+		if _, err := c.ExecuteRequest(context.Background(), input); err != nil {
+			t.Fatal(err)
+		}
+
+		digest := md5.Sum(sentBody)
+		expected := []string{
+			"(request-target)", "post /test.user/machines?action=start",
+			"host", "us-east-1.api.joyent.com",
+			"date", "",
+			"content-md5", base64.StdEncoding.EncodeToString(digest[:]),
+		}
+		if len(signer.signed) != len(expected)/2 {
+			t.Fatalf("expected %d signed headers: got %v", len(expected)/2, signer.signed)
+		}
+		for i, header := range signer.signed {
+			if header.Name != expected[2*i] {
+				t.Errorf("expected header %d to be %q: got %q", i, expected[2*i], header.Name)
+			}
+			if value := expected[2*i+1]; value != "" && header.Value != value {
+				t.Errorf("expected %s to be %q: got %q", header.Name, value, header.Value)
+			}
+		}
+		if authHeader != "key-headers" {
+			t.Errorf("expected request to be signed with SignHeaders: got %q", authHeader)
+		}
+	})
+
+	t.Run("falls back to date", func(t *testing.T) {
+		c := newTestClient(rt)
+		c.Authorizers = []auth.Signer{&namedSigner{name: "key"}}
+		c.SignedHeaders = []string{"(request-target)", "date"}
+
+		if _, err := c.ExecuteRequest(context.Background(), input); err != nil {
+			t.Fatal(err)
+		}
+		if authHeader != "key" {
+			t.Errorf("expected request to be signed with Sign: got %q", authHeader)
+		}
+	})
+
+	t.Run("private key signature", func(t *testing.T) {
+		signer, err := auth.NewPrivateKeySigner(auth.PrivateKeySignerInput{
+			KeyID:              DummyAuth.Fingerprint,
+			PrivateKeyMaterial: DummyAuth.PrivateKey,
+			AccountName:        "test.user",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		authHeader, err := signer.SignHeaders([]auth.SignedHeader{
+			{Name: auth.RequestTarget, Value: "get /test.user/machines"},
+			{Name: "Date", Value: "Thu, 05 Jan 2014 21:31:40 GMT"},
+		}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(authHeader, `headers="(request-target) date"`) {
+			t.Fatalf("expected signed header list: got %s", authHeader)
+		}
+
+		signature := authHeader[strings.Index(authHeader, `signature="`)+len(`signature="`) : len(authHeader)-1]
+		raw, err := base64.StdEncoding.DecodeString(signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, _, _, _, err := ssh.ParseAuthorizedKey(DummyAuth.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := sha512.Sum512([]byte("(request-target): get /test.user/machines\ndate: Thu, 05 Jan 2014 21:31:40 GMT"))
+		rsaKey := pub.(ssh.CryptoPublicKey).CryptoPublicKey().(*rsa.PublicKey)
+		if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA512, digest[:], raw); err != nil {
+			t.Errorf("expected signature to verify: %v", err)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/joyent/triton-go/v2/errors"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// Attribute keys set on spans and log records of API calls.
+const (
+	AttrOperation  = "triton.operation"
+	AttrAccount    = "triton.account"
+	AttrDatacenter = "triton.datacenter"
+	AttrMethod     = "http.request.method"
+	AttrPath       = "url.path"
+	AttrStatusCode = "http.response.status_code"
+	AttrRequestID  = "triton.request_id"
+	AttrErrorCode  = "triton.error_code"
+	AttrDuration   = "triton.duration"
+	AttrAttempt    = "triton.attempt"
+
+	// AttrLimiterWait is how long the call waited for the client's Limiter.
+	AttrLimiterWait = "triton.limiter_wait"
+)
+
+// Logger receives structured records of the API calls made by a Client. Args
+// are alternating keys and values, so a *slog.Logger can be used as is.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// Tracer starts a span for every API call made by a Client. The context
+// returned by Start is the one the request is sent with, so that Middleware
+// can propagate the span, e.g. in a traceparent header.
+type Tracer interface {
+	Start(ctx context.Context, operation string) (context.Context, Span)
+}
+
+// Span is a traced API call, ended once the response headers are received or
+// the call failed. Implementations wrap the span type of a tracing library,
+// such as OpenTelemetry's trace.Span.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// apiCall records an API call for the client's Logger and Tracer.
+type apiCall struct {
+	client *Client
+	span   Span
+	start  time.Time
+	attrs  []interface{}
+	waited time.Duration
+}
+
+// startCall starts observing r. It returns the context r is sent with.
+func (c *Client) startCall(ctx context.Context, r apiRequest) (context.Context, *apiCall) {
+	if c.Logger == nil && c.Tracer == nil {
+		return ctx, nil
+	}
+
+	operation := r.operation
+	if operation == "" {
+		operation = "triton.request"
+		if r.isManta {
+			operation = "manta.request"
+		}
+	}
+
+	call := &apiCall{
+		client: c,
+		start:  time.Now(),
+		attrs: []interface{}{
+			AttrOperation, operation,
+			AttrAccount, c.AccountName,
+			AttrDatacenter, c.datacenter(r.endpoint),
+			AttrMethod, r.method,
+			AttrPath, r.path,
+		},
+	}
+	if c.Tracer != nil {
+		ctx, call.span = c.Tracer.Start(ctx, operation)
+		call.setAttributes(call.attrs...)
+	}
+
+	return ctx, call
+}
+
+// datacenter returns the name of the client's data center, or the host of
+// endpoint if it cannot be told from the CloudAPI URL.
+func (c *Client) datacenter(endpoint url.URL) string {
+	if dc, _, err := parseDC(c.TritonURL.String()); err == nil {
+		return dc
+	}
+
+	return endpoint.Host
+}
+
+func (call *apiCall) setAttributes(attrs ...interface{}) {
+	if call.span == nil {
+		return
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		call.span.SetAttribute(attrs[i].(string), attrs[i+1])
+	}
+}
+
+// wait records that an attempt of the call waited for a Limiter.
+func (call *apiCall) wait(waited time.Duration) {
+	if call != nil {
+		call.waited += waited
+	}
+}
+
+// retry records that the call is attempted again after wait.
+func (call *apiCall) retry(ctx context.Context, attempt int, wait time.Duration, resp *http.Response, err error) {
+	if call == nil || call.client.Logger == nil {
+		return
+	}
+
+	attrs := append(call.attrs[:len(call.attrs):len(call.attrs)], AttrAttempt, attempt, "wait", wait)
+	if resp != nil {
+		attrs = append(attrs, AttrStatusCode, resp.StatusCode)
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err.Error())
+	}
+	call.client.Logger.DebugContext(ctx, "retrying Triton API call", attrs...)
+}
+
+// end records the outcome of the call. resp may be set for failed calls.
+func (call *apiCall) end(ctx context.Context, resp *http.Response, err error) {
+	if call == nil {
+		return
+	}
+
+	attrs := append(call.attrs[:len(call.attrs):len(call.attrs)], AttrDuration, time.Since(call.start))
+	if call.waited > 0 {
+		attrs = append(attrs, AttrLimiterWait, call.waited)
+	}
+	if resp != nil {
+		attrs = append(attrs, AttrStatusCode, resp.StatusCode)
+		if id := requestID(resp.Header); id != "" {
+			attrs = append(attrs, AttrRequestID, id)
+		}
+	}
+
+	var apiErr *errors.APIError
+	if err != nil && pkgerrors.As(err, &apiErr) {
+		if resp == nil {
+			attrs = append(attrs, AttrStatusCode, apiErr.StatusCode)
+			if apiErr.RequestID != "" {
+				attrs = append(attrs, AttrRequestID, apiErr.RequestID)
+			}
+		}
+		attrs = append(attrs, AttrErrorCode, apiErr.Code)
+	}
+
+	if call.span != nil {
+		call.setAttributes(attrs[len(call.attrs):]...)
+		if err != nil {
+			call.span.RecordError(err)
+		}
+		call.span.End()
+	}
+
+	if logger := call.client.Logger; logger != nil {
+		if err != nil {
+			logger.WarnContext(ctx, "Triton API call failed", append(attrs, "error", err.Error())...)
+		} else {
+			logger.DebugContext(ctx, "Triton API call", attrs...)
+		}
+	}
+}
+
+// requestID returns the ID the API assigned to the request of a response.
+func requestID(header http.Header) string {
+	if id := header.Get("X-Request-Id"); id != "" {
+		return id
+	}
+
+	return header.Get("Request-Id")
+}
@@ -0,0 +1,175 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type testLogRecord struct {
+	level string
+	msg   string
+	attrs map[string]interface{}
+}
+
+type testLogger struct {
+	records []testLogRecord
+}
+
+func (l *testLogger) log(level, msg string, args []interface{}) {
+	attrs := map[string]interface{}{}
+	for i := 0; i+1 < len(args); i += 2 {
+		attrs[args[i].(string)] = args[i+1]
+	}
+	l.records = append(l.records, testLogRecord{level, msg, attrs})
+}
+
+func (l *testLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("debug", msg, args)
+}
+
+func (l *testLogger) WarnContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("warn", msg, args)
+}
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	operation string
+	attrs     map[string]interface{}
+	err       error
+	ended     bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.err = err }
+func (s *testSpan) End()                                       { s.ended = true }
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, operation string) (context.Context, Span) {
+	span := &testSpan{operation: operation, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func TestObservability(t *testing.T) {
+	input := RequestInput{
+		Operation: "compute.instances.list",
+		Method:    http.MethodGet,
+		Path:      "/test.user/machines",
+	}
+
+	t.Run("successful call", func(t *testing.T) {
+		var attempts int
+		var propagated bool
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			_, propagated = req.Context().Value(testSpanKey{}).(*testSpan)
+			if attempts == 1 {
+				return newTestResponse(http.StatusServiceUnavailable, `{}`), nil
+			}
+			resp := newTestResponse(http.StatusOK, `[]`)
+			resp.Header.Set("X-Request-Id", "0f6a4c1e")
+			return resp, nil
+		}))
+		c.RetryPolicy = testRetryPolicy()
+		logger, tracer := &testLogger{}, &testTracer{}
+		c.Logger, c.Tracer = logger, tracer
+
+		if _, err := c.ExecuteRequest(context.Background(), input); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(tracer.spans) != 1 {
+			t.Fatalf("expected one span: got %d", len(tracer.spans))
+		}
+		span := tracer.spans[0]
+		if span.operation != "compute.instances.list" || !span.ended || span.err != nil {
+			t.Errorf("expected ended compute.instances.list span: got %+v", span)
+		}
+		expected := map[string]interface{}{
+			AttrOperation:  "compute.instances.list",
+			AttrAccount:    "test.user",
+			AttrDatacenter: "us-east-1",
+			AttrMethod:     http.MethodGet,
+			AttrPath:       "/test.user/machines",
+			AttrStatusCode: http.StatusOK,
+			AttrRequestID:  "0f6a4c1e",
+		}
+		for key, value := range expected {
+			if span.attrs[key] != value {
+				t.Errorf("expected span attribute %s to be %v: got %v", key, value, span.attrs[key])
+			}
+		}
+		if _, ok := span.attrs[AttrDuration]; !ok {
+			t.Errorf("expected span attribute %s", AttrDuration)
+		}
+		if !propagated {
+			t.Error("expected the request to be sent with the span's context")
+		}
+
+		if len(logger.records) != 2 {
+			t.Fatalf("expected a retry and a call record: got %+v", logger.records)
+		}
+		if record := logger.records[0]; record.level != "debug" || record.attrs[AttrAttempt] != 1 ||
+			record.attrs[AttrStatusCode] != http.StatusServiceUnavailable {
+			t.Errorf("expected a retry record: got %+v", record)
+		}
+		if record := logger.records[1]; record.level != "debug" || record.attrs[AttrRequestID] != "0f6a4c1e" {
+			t.Errorf("expected a call record: got %+v", record)
+		}
+	})
+
+	t.Run("failed call", func(t *testing.T) {
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp := newTestResponse(http.StatusNotFound, `{"code":"ResourceNotFound","message":"not found"}`)
+			resp.Header.Set("X-Request-Id", "7d1e2b4c")
+			return resp, nil
+		}))
+		logger, tracer := &testLogger{}, &testTracer{}
+		c.Logger, c.Tracer = logger, tracer
+
+		if _, err := c.ExecuteRequest(context.Background(), input); err == nil {
+			t.Fatal("expected an error")
+		}
+
+		span := tracer.spans[0]
+		if span.err == nil || span.attrs[AttrErrorCode] != "ResourceNotFound" ||
+			span.attrs[AttrStatusCode] != http.StatusNotFound || span.attrs[AttrRequestID] != "7d1e2b4c" {
+			t.Errorf("expected span to record the API error: got %+v", span)
+		}
+		if len(logger.records) != 1 || logger.records[0].level != "warn" {
+			t.Fatalf("expected a warning: got %+v", logger.records)
+		}
+	})
+
+	t.Run("no credentials logged", func(t *testing.T) {
+		c := newTestClient(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return newTestResponse(http.StatusOK, `[]`), nil
+		}))
+		logger := &testLogger{}
+		c.Logger = logger
+
+		if _, err := c.ExecuteRequest(context.Background(), input); err != nil {
+			t.Fatal(err)
+		}
+
+		record := fmt.Sprint(logger.records)
+		if strings.Contains(record, "Signature") || strings.Contains(record, "keyId") {
+			t.Errorf("expected no credentials in log records: got %s", record)
+		}
+	})
+}
@@ -10,10 +10,9 @@ package compute
 
 import (
 	"context"
-	"net/http"
-	"time"
-
 	"fmt"
+	"net/http"
+	"strconv"
 
 	"github.com/imdario/mergo"
 	"github.com/joyent/triton-go/v2/cmd/config"
@@ -350,8 +349,24 @@ func (c *AgentComputeClient) GetInstance() (*tcc.Instance, error) {
 
 func (c *AgentComputeClient) CreateInstance() (*tcc.Instance, error) {
 	params := &tcc.CreateInstanceInput{
-		Name:            config.GetMachineName(),
-		FirewallEnabled: config.GetMachineFirewall(),
+		Name:               config.GetMachineName(),
+		FirewallEnabled:    config.GetMachineFirewall(),
+		DeletionProtection: config.GetMachineDeletionProtection(),
+		Encrypted:          config.GetMachineEncrypted(),
+		AllowSharedImages:  config.GetMachineAllowSharedImages(),
+	}
+
+	for _, size := range config.GetMachineDisks() {
+		if size == "remaining" {
+			params.Disks = append(params.Disks, tcc.InstanceDisk{Remaining: true})
+			continue
+		}
+
+		mib, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("Invalid disk size %q: must be a number of MiB or \"remaining\"", size)
+		}
+		params.Disks = append(params.Disks, tcc.InstanceDisk{Size: mib})
 	}
 
 	md := make(map[string]interface{}, 0)
@@ -425,24 +440,241 @@ func (c *AgentComputeClient) CreateInstance() (*tcc.Instance, error) {
 	}
 
 	if config.IsBlockingAction() {
-		state := make(chan *tcc.Instance, 1)
-		go func(machineID string) {
-			for {
-				time.Sleep(1 * time.Second)
-				instance, err := c.getInstanceByID(machineID)
-				if err != nil {
-					panic(err)
-				}
-				if instance.State == "running" {
-					state <- instance
-				}
-			}
-		}(machine.ID)
+		instance, err := c.client.Instances().WaitForState(context.Background(), &tcc.WaitForInstanceStateInput{
+			ID:    machine.ID,
+			State: "running",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return instance, nil
 	}
 
 	return machine, nil
 }
 
+func (c *AgentComputeClient) GetInstanceAudit() (*tcc.Instance, []*tcc.AuditEntry, error) {
+	instance, err := c.findInstance()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := c.client.Instances().Audit(context.Background(), &tcc.AuditInstanceInput{
+		ID: instance.ID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return instance, entries, nil
+}
+
+func (c *AgentComputeClient) ListDisks() (*tcc.Instance, []*tcc.Disk, error) {
+	instance, err := c.findInstance()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	disks, err := c.client.Disks().List(context.Background(), &tcc.ListDisksInput{
+		InstanceID: instance.ID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return instance, disks, nil
+}
+
+func (c *AgentComputeClient) GetDisk() (*tcc.Instance, *tcc.Disk, error) {
+	instance, err := c.findInstance()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	disk, err := c.client.Disks().Get(context.Background(), &tcc.GetDiskInput{
+		InstanceID: instance.ID,
+		ID:         config.GetDiskID(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return instance, disk, nil
+}
+
+func (c *AgentComputeClient) AddDisk() (*tcc.Instance, *tcc.Disk, error) {
+	instance, err := c.findInstance()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params := &tcc.CreateDiskInput{
+		InstanceID:  instance.ID,
+		PCISlot:     config.GetDiskAddPCISlot(),
+		CheckBudget: true,
+	}
+
+	size := config.GetDiskAddSize()
+	if size == "remaining" {
+		params.Remaining = true
+	} else {
+		params.Size, err = strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return nil, nil, errors.Errorf("Invalid disk size %q: must be a number of MiB or \"remaining\"", size)
+		}
+	}
+
+	disk, err := c.client.Disks().Create(context.Background(), params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if config.GetDiskWait() {
+		disk, err = c.client.Disks().WaitForState(context.Background(), &tcc.WaitForDiskStateInput{
+			InstanceID: instance.ID,
+			ID:         disk.ID,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return instance, disk, nil
+}
+
+func (c *AgentComputeClient) ResizeDisk() (*tcc.Instance, *tcc.Disk, error) {
+	instance, err := c.findInstance()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	disk, err := c.client.Disks().Resize(context.Background(), &tcc.ResizeDiskInput{
+		InstanceID:           instance.ID,
+		ID:                   config.GetDiskID(),
+		Size:                 config.GetDiskResizeSize(),
+		DangerousAllowShrink: config.GetDiskResizeAllowShrink(),
+		CheckBudget:          true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if config.GetDiskWait() {
+		disk, err = c.client.Disks().WaitForState(context.Background(), &tcc.WaitForDiskStateInput{
+			InstanceID: instance.ID,
+			ID:         disk.ID,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return instance, disk, nil
+}
+
+func (c *AgentComputeClient) DeleteDisk() (*tcc.Instance, error) {
+	instance, err := c.findInstance()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.client.Disks().Delete(context.Background(), &tcc.DeleteDiskInput{
+		InstanceID: instance.ID,
+		ID:         config.GetDiskID(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if config.GetDiskWait() {
+		err = c.client.Disks().WaitForDeletion(context.Background(), &tcc.WaitForDiskDeletionInput{
+			InstanceID: instance.ID,
+			ID:         config.GetDiskID(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
+
+func (c *AgentComputeClient) ListMigrations() ([]*tcc.Migration, error) {
+	migrations, err := c.client.Migrations().List(context.Background(), &tcc.ListMigrationsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrations, nil
+}
+
+func (c *AgentComputeClient) GetMigration() (*tcc.Instance, *tcc.Migration, error) {
+	instance, err := c.findInstance()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migration, err := c.client.Migrations().Get(context.Background(), &tcc.GetMigrationInput{
+		InstanceID: instance.ID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return instance, migration, nil
+}
+
+func (c *AgentComputeClient) MigrateInstance(action tcc.MigrationAction) (*tcc.Instance, *tcc.Migration, error) {
+	instance, err := c.findInstance()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migration, err := c.client.Migrations().Migrate(context.Background(), &tcc.MigrateInstanceInput{
+		InstanceID: instance.ID,
+		Action:     action,
+		Affinity:   config.GetMigrationAffinityRules(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return instance, migration, nil
+}
+
+// WatchMigration calls onProgress with every progress event of the migration
+// of the instance until the running phase ends.
+func (c *AgentComputeClient) WatchMigration(onProgress func(*tcc.MigrationProgress)) error {
+	instance, err := c.findInstance()
+	if err != nil {
+		return err
+	}
+
+	watcher := c.client.Migrations().Watch(context.Background(), &tcc.WatchMigrationInput{
+		InstanceID: instance.ID,
+	})
+	defer watcher.Close()
+
+	for watcher.Next() {
+		onProgress(watcher.Progress())
+	}
+
+	return watcher.Err()
+}
+
+func (c *AgentComputeClient) findInstance() (*tcc.Instance, error) {
+	instance, err := c.GetInstance()
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, errors.New("Either `id` or `name` must be specified")
+	}
+
+	return instance, nil
+}
+
 func (c *AgentComputeClient) getInstanceByName(instanceName string) (*tcc.Instance, error) {
 	instances, err := c.client.Instances().List(context.Background(), &tcc.ListInstancesInput{
 		Name: instanceName,
@@ -516,3 +748,18 @@ func (c *AgentComputeClient) FormatImageName(images []*tcc.Image, imgID string)
 
 	return string(imgID[:8])
 }
+
+func (c *AgentComputeClient) FormatMigrationProgress(progress *tcc.MigrationProgress) string {
+	line := fmt.Sprintf("%s: %s", progress.Phase, progress.State)
+	if progress.TotalProgress > 0 {
+		line += fmt.Sprintf(" %d/%d (%d%%)", progress.CurrentProgress, progress.TotalProgress, progress.CurrentProgress*100/progress.TotalProgress)
+	}
+	if progress.Message != "" {
+		line += " - " + progress.Message
+	}
+	if progress.Error != "" {
+		line += " - error: " + progress.Error
+	}
+
+	return line
+}
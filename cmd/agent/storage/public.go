@@ -9,10 +9,21 @@
 package storage
 
 import (
+	"bufio"
 	"context"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/joyent/triton-go/v2/cmd/config"
+	terrors "github.com/joyent/triton-go/v2/errors"
 	tsc "github.com/joyent/triton-go/v2/storage"
+	tss "github.com/joyent/triton-go/v2/storage/sync"
 	"github.com/pkg/errors"
 )
 
@@ -44,3 +55,218 @@ func (c *AgentStorageClient) GetDirectoryListing(args []string) (*tsc.ListDirect
 
 	return directoryOutput, nil
 }
+
+// Sync mirrors localPath into the Manta directory remotePath, or remotePath
+// into localPath with --reverse. onAction is called for every change.
+func (c *AgentStorageClient) Sync(localPath, remotePath string, onAction func(tss.Action)) (*tss.Output, error) {
+	input := &tss.Input{
+		LocalPath:   localPath,
+		RemotePath:  remotePath,
+		Delete:      config.GetSyncDelete(),
+		DryRun:      config.GetSyncDryRun(),
+		Exclude:     config.GetSyncExclude(),
+		Checksum:    config.GetSyncChecksum(),
+		Concurrency: config.GetSyncParallel(),
+		OnAction:    onAction,
+	}
+
+	syncer := tss.New(c.client)
+	if config.GetSyncReverse() {
+		return syncer.Download(context.Background(), input)
+	}
+	return syncer.Upload(context.Background(), input)
+}
+
+// GetObject returns the object at objectPath. The caller must close its
+// ObjectReader.
+func (c *AgentStorageClient) GetObject(objectPath string) (*tsc.GetObjectOutput, error) {
+	return c.client.Objects().Get(context.Background(), &tsc.GetObjectInput{
+		ObjectPath: objectPath,
+	})
+}
+
+// DownloadObject downloads the object at objectPath into the file named by
+// --output, fetching large objects in parallel ranges.
+func (c *AgentStorageClient) DownloadObject(objectPath string) (*tsc.DownloadOutput, error) {
+	f, err := os.Create(config.GetGetOutput())
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	output, err := c.client.Downloader().Download(context.Background(), &tsc.DownloadInput{
+		ObjectPath: objectPath,
+		Writer:     f,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output, f.Close()
+}
+
+// PutObject stores the file named by --file, or stdin, at objectPath. The
+// content type is taken from --content-type, the extension of the object or
+// file, or the first bytes of the content, in that order.
+func (c *AgentStorageClient) PutObject(objectPath string) error {
+	var r io.Reader = os.Stdin
+	var size int64
+	extensions := []string{path.Ext(objectPath)}
+
+	if name := config.GetPutFile(); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		r, size = f, info.Size()
+		extensions = append(extensions, path.Ext(name))
+	}
+
+	contentType := config.GetPutContentType()
+	for _, ext := range extensions {
+		if contentType == "" && ext != "" {
+			contentType = mime.TypeByExtension(ext)
+		}
+	}
+	if contentType == "" {
+		buffered := bufio.NewReader(r)
+		head, _ := buffered.Peek(512)
+		contentType = http.DetectContentType(head)
+		r = buffered
+	}
+
+	ctx := context.Background()
+	if size > tsc.DefaultUploadPartSize {
+		_, err := c.client.Uploader().Upload(ctx, &tsc.UploadInput{
+			ObjectPath:      objectPath,
+			ObjectReader:    r,
+			ContentLength:   uint64(size),
+			Headers:         map[string]string{"content-type": contentType},
+			DurabilityLevel: config.GetPutCopies(),
+			ForceInsert:     config.GetPutParents(),
+		})
+		return err
+	}
+
+	return c.client.Objects().Put(ctx, &tsc.PutObjectInput{
+		ObjectPath:      objectPath,
+		ObjectReader:    r,
+		ContentLength:   uint64(size),
+		ContentType:     contentType,
+		DurabilityLevel: config.GetPutCopies(),
+		ForceInsert:     config.GetPutParents(),
+	})
+}
+
+// DeleteObject deletes the object or empty directory at objectPath. With
+// --recursive the objects below a directory are deleted concurrently while
+// it is walked, then its directories are deleted deepest first.
+func (c *AgentStorageClient) DeleteObject(objectPath string) error {
+	ctx := context.Background()
+	deleteObject := func(objectPath string) error {
+		return c.client.Objects().Delete(ctx, &tsc.DeleteObjectInput{
+			ObjectPath: objectPath,
+		})
+	}
+
+	if !config.GetRmRecursive() {
+		return deleteObject(objectPath)
+	}
+
+	isDir, err := c.client.Objects().IsDir(ctx, objectPath)
+	if err != nil {
+		return err
+	}
+	if !isDir {
+		return deleteObject(objectPath)
+	}
+
+	// Walk calls its WalkFunc from several goroutines at once, so objects
+	// are deleted concurrently as soon as they are listed.
+	var mu sync.Mutex
+	dirs := []string{path.Clean(objectPath)}
+	err = c.client.Dir().Walk(ctx, objectPath, func(entryPath string, entry *tsc.DirectoryEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type == "directory" {
+			mu.Lock()
+			dirs = append(dirs, entryPath)
+			mu.Unlock()
+			return nil
+		}
+		return deleteObject(entryPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], "/") > strings.Count(dirs[j], "/")
+	})
+	for _, dir := range dirs {
+		if err := deleteObject(dir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MakeDirectory creates the directory at dirPath. With --parents missing
+// parents are created as well.
+func (c *AgentStorageClient) MakeDirectory(dirPath string) error {
+	ctx := context.Background()
+	err := c.client.Dir().Put(ctx, &tsc.PutDirectoryInput{DirectoryName: dirPath})
+	if config.GetMkdirParents() && terrors.IsDirectoryDoesNotExistError(err) && path.Dir(dirPath) != dirPath {
+		if err := c.MakeDirectory(path.Dir(dirPath)); err != nil {
+			return err
+		}
+		err = c.client.Dir().Put(ctx, &tsc.PutDirectoryInput{DirectoryName: dirPath})
+	}
+	return err
+}
+
+// GetObjectInfo returns the headers and metadata of the object at
+// objectPath.
+func (c *AgentStorageClient) GetObjectInfo(objectPath string) (*tsc.GetInfoOutput, error) {
+	return c.client.Objects().GetInfo(context.Background(), &tsc.GetInfoInput{
+		ObjectPath: objectPath,
+	})
+}
+
+// PutSnapLink creates a SnapLink at linkPath to the object at sourcePath.
+func (c *AgentStorageClient) PutSnapLink(sourcePath, linkPath string) error {
+	return c.client.SnapLinks().Put(context.Background(), &tsc.PutSnapLinkInput{
+		SourcePath: sourcePath,
+		LinkPath:   linkPath,
+	})
+}
+
+// SignURL returns a URL to objectPath usable without credentials for
+// --expires with --method.
+func (c *AgentStorageClient) SignURL(objectPath string) (string, error) {
+	accountPrefix := path.Join("/", c.client.Client.AccountName) + "/"
+	objectPath = "/" + strings.TrimPrefix(path.Clean("/"+objectPath), accountPrefix)
+
+	output, err := c.client.SignURL(&tsc.SignURLInput{
+		ObjectPath:     objectPath,
+		Method:         config.GetSignMethod(),
+		ValidityPeriod: config.GetSignExpires(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	scheme := c.client.Client.MantaURL.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	return output.SignedURL(scheme), nil
+}
@@ -0,0 +1,278 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package storage
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	tsc "github.com/joyent/triton-go/v2/storage"
+	"github.com/joyent/triton-go/v2/testutils"
+	"github.com/spf13/viper"
+)
+
+const accountURL = "testing"
+
+func mockStorageClient() *AgentStorageClient {
+	return &AgentStorageClient{
+		client: &tsc.StorageClient{
+			Client: testutils.NewMockClient(testutils.MockClientInput{
+				AccountName: accountURL,
+			}),
+		},
+	}
+}
+
+// setConfig sets the viper key to value for the duration of the test.
+func setConfig(t *testing.T, key string, value interface{}) {
+	viper.Set(key, value)
+	t.Cleanup(func() { viper.Set(key, nil) })
+}
+
+func response(status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func listing(entries ...tsc.DirectoryEntry) testutils.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		var body strings.Builder
+		for _, entry := range entries {
+			line, _ := json.Marshal(entry)
+			body.Write(line)
+			body.WriteString("\n")
+		}
+
+		header := http.Header{}
+		header.Set("Content-Type", "application/x-json-stream")
+		header.Set("Result-Set-Size", "2")
+		return response(http.StatusOK, header, body.String()), nil
+	}
+}
+
+func TestGetDirectoryListing(t *testing.T) {
+	defer testutils.DeactivateClient()
+	testutils.RegisterResponder("GET", path.Join("/", accountURL, "stor"), listing(
+		tsc.DirectoryEntry{Name: "logs", Type: "directory"},
+		tsc.DirectoryEntry{Name: "README", Type: "object", Size: 10},
+	))
+
+	output, err := mockStorageClient().GetDirectoryListing([]string{"/stor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output.ResultSetSize != 2 || len(output.Entries) != 2 || output.Entries[1].Name != "README" {
+		t.Errorf("unexpected listing: %+v", output)
+	}
+}
+
+func TestGetObject(t *testing.T) {
+	defer testutils.DeactivateClient()
+	testutils.RegisterResponder("GET", path.Join("/", accountURL, "stor/hello.txt"), func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Set("Content-Type", "text/plain")
+		header.Set("Content-Length", "5")
+		return response(http.StatusOK, header, "hello"), nil
+	})
+
+	output, err := mockStorageClient().GetObject("/stor/hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer output.ObjectReader.Close()
+
+	body, err := ioutil.ReadAll(output.ObjectReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "hello" || output.ContentType != "text/plain" || output.ContentLength != 5 {
+		t.Errorf("unexpected object %q: %+v", body, output)
+	}
+}
+
+func TestPutObject(t *testing.T) {
+	dir, err := ioutil.TempDir("", "triton-go-put")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "data.json")
+	if err := ioutil.WriteFile(file, []byte(`{"hello":"world"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setConfig(t, config.KeyPutFile, file)
+
+	put := func(t *testing.T) (string, string) {
+		defer testutils.DeactivateClient()
+
+		var contentType, body string
+		testutils.RegisterResponder("PUT", path.Join("/", accountURL, "stor/data"), func(req *http.Request) (*http.Response, error) {
+			b, _ := ioutil.ReadAll(req.Body)
+			contentType, body = req.Header.Get("Content-Type"), string(b)
+			return response(http.StatusNoContent, nil, ""), nil
+		})
+
+		if err := mockStorageClient().PutObject("/stor/data"); err != nil {
+			t.Fatal(err)
+		}
+		return contentType, body
+	}
+
+	t.Run("file extension", func(t *testing.T) {
+		contentType, body := put(t)
+		if contentType != "application/json" || body != `{"hello":"world"}` {
+			t.Errorf("unexpected object of type %q: %q", contentType, body)
+		}
+	})
+
+	t.Run("content type", func(t *testing.T) {
+		setConfig(t, config.KeyPutContentType, "text/plain")
+
+		if contentType, _ := put(t); contentType != "text/plain" {
+			t.Errorf("expected --content-type to be used: got %q", contentType)
+		}
+	})
+}
+
+func TestDeleteObject(t *testing.T) {
+	// registerDeletes records the order in which paths are deleted.
+	registerDeletes := func(paths ...string) *[]string {
+		var mu sync.Mutex
+		var deleted []string
+		for _, p := range paths {
+			p := p
+			testutils.RegisterResponder("DELETE", path.Join("/", accountURL, p), func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				deleted = append(deleted, p)
+				mu.Unlock()
+				return response(http.StatusNoContent, nil, ""), nil
+			})
+		}
+		return &deleted
+	}
+
+	t.Run("object", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		deleted := registerDeletes("/stor/hello.txt")
+
+		if err := mockStorageClient().DeleteObject("/stor/hello.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if len(*deleted) != 1 {
+			t.Errorf("expected the object to be deleted: got %v", *deleted)
+		}
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		setConfig(t, config.KeyRmRecursive, true)
+
+		testutils.RegisterResponder("HEAD", path.Join("/", accountURL, "stor/tree"), func(req *http.Request) (*http.Response, error) {
+			header := http.Header{}
+			header.Set("Content-Type", "application/x-json-stream; type=directory")
+			return response(http.StatusOK, header, ""), nil
+		})
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "stor/tree")+"?limit=1024", listing(
+			tsc.DirectoryEntry{Name: "a", Type: "directory"},
+			tsc.DirectoryEntry{Name: "x.txt", Type: "object"},
+		))
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "stor/tree/a")+"?limit=1024", listing(
+			tsc.DirectoryEntry{Name: "b", Type: "directory"},
+			tsc.DirectoryEntry{Name: "y.txt", Type: "object"},
+		))
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "stor/tree/a/b")+"?limit=1024", listing(
+			tsc.DirectoryEntry{Name: "z.txt", Type: "object"},
+		))
+		deleted := registerDeletes("/stor/tree", "/stor/tree/x.txt", "/stor/tree/a", "/stor/tree/a/y.txt", "/stor/tree/a/b", "/stor/tree/a/b/z.txt")
+
+		if err := mockStorageClient().DeleteObject("/stor/tree/"); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(*deleted) != 6 {
+			t.Fatalf("expected every entry to be deleted: got %v", *deleted)
+		}
+		dirs := strings.Join((*deleted)[3:], ",")
+		if dirs != "/stor/tree/a/b,/stor/tree/a,/stor/tree" {
+			t.Errorf("expected objects, then directories deepest first: got %v", *deleted)
+		}
+	})
+}
+
+func TestGetObjectInfo(t *testing.T) {
+	defer testutils.DeactivateClient()
+	testutils.RegisterResponder("HEAD", path.Join("/", accountURL, "stor/hello.txt"), func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Set("Content-Type", "text/plain")
+		header.Set("Content-Length", "5")
+		header.Set("Content-MD5", "XUFAKrxLKna5cZ2REBfFkg==")
+		header.Set("Last-Modified", "Mon, 06 Jul 2020 12:00:00 GMT")
+		return response(http.StatusOK, header, ""), nil
+	})
+
+	info, err := mockStorageClient().GetObjectInfo("/stor/hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.ContentLength != 5 || info.ContentMD5 != "XUFAKrxLKna5cZ2REBfFkg==" || info.ContentType != "text/plain" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+	if !info.LastModified.Equal(time.Date(2020, 7, 6, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected modification time %v", info.LastModified)
+	}
+}
+
+func TestSignURL(t *testing.T) {
+	setConfig(t, config.KeySignMethod, http.MethodGet)
+	setConfig(t, config.KeySignExpires, time.Hour)
+
+	c := mockStorageClient()
+	c.client.Client.MantaURL = url.URL{Host: "us-east.manta.example.com"}
+
+	signed, err := c.SignURL("/testing/stor/hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "https" || u.Host != "us-east.manta.example.com" || u.Path != "/testing/stor/hello.txt" {
+		t.Errorf("unexpected signed URL %s", signed)
+	}
+
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	if err != nil || time.Until(time.Unix(expires, 0)) <= 59*time.Minute {
+		t.Errorf("expected the URL to expire in an hour: got %s", u.RawQuery)
+	}
+	if !strings.HasPrefix(u.Query().Get("keyId"), "/testing/keys") {
+		t.Errorf("unexpected keyId in %s", u.RawQuery)
+	}
+}
@@ -21,16 +21,18 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 )
 
 type TritonClientConfig struct {
 	Config *triton.ClientConfig
 }
 
-func buildSSHAgentSigner(keyID string, accountName string) (*authentication.SSHAgentSigner, error) {
+func buildSSHAgentSigner(keyID string, accountName string, userName string) (*authentication.SSHAgentSigner, error) {
 	signer, err := authentication.NewSSHAgentSigner(authentication.SSHAgentSignerInput{
 		KeyID:       keyID,
 		AccountName: accountName,
+		Username:    userName,
 	})
 	if err != nil {
 		return nil, err
@@ -38,11 +40,14 @@ func buildSSHAgentSigner(keyID string, accountName string) (*authentication.SSHA
 	return signer, nil
 }
 
-func buildPrivateKeySigner(keyID string, accountName string, keyMaterial []byte) (*authentication.PrivateKeySigner, error) {
+func buildPrivateKeySigner(keyID string, accountName string, userName string, keyMaterial []byte, passphrase string) (*authentication.PrivateKeySigner, error) {
 	signer, err := authentication.NewPrivateKeySigner(authentication.PrivateKeySignerInput{
 		KeyID:              keyID,
 		PrivateKeyMaterial: keyMaterial,
 		AccountName:        accountName,
+		Username:           userName,
+		Passphrase:         []byte(passphrase),
+		PassphraseFunc:     promptKeyPassphrase(keyID),
 	})
 	if err != nil {
 		return nil, err
@@ -63,13 +68,6 @@ func getKeyMaterialContents(keyMaterial string) ([]byte, error) {
 			return nil, fmt.Errorf(
 				"failed to read key material '%s': no key found", keyMaterial)
 		}
-
-		if block.Headers["Proc-Type"] == "4,ENCRYPTED" {
-			return nil, fmt.Errorf(
-				"failed to read key '%s': password protected keys are\n"+
-					"not currently supported. Please decrypt the key prior to use.", keyMaterial)
-		}
-
 	} else {
 		keyBytes = []byte(keyMaterial)
 	}
@@ -77,15 +75,45 @@ func getKeyMaterialContents(keyMaterial string) ([]byte, error) {
 	return keyBytes, nil
 }
 
+// promptKeyPassphrase returns a function which asks for the passphrase of the
+// key identified by keyID on the controlling terminal. It fails when stdin is
+// not a terminal, in which case the passphrase has to be provided via the
+// TRITON_KEY_PASSPHRASE environment variable instead.
+func promptKeyPassphrase(keyID string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return nil, errors.New("private key is passphrase protected: set TRITON_KEY_PASSPHRASE or run from a terminal")
+		}
+
+		fmt.Fprintf(os.Stderr, "Enter passphrase for key %s: ", keyID)
+		passphrase, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, err
+		}
+
+		return passphrase, nil
+	}
+}
+
 func NewTritonConfig() (*TritonClientConfig, error) {
 	viper.AutomaticEnv()
 
+	profile, err := SelectedProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := tritonKeyID(profile)
+	account := tritonAccount(profile)
+	user := tritonUser(profile)
+
 	var signer authentication.Signer
-	var err error
 
 	keyMaterial := GetTritonKeyMaterial()
 	if keyMaterial == "" {
-		signer, err = buildSSHAgentSigner(GetTritonKeyID(), GetTritonAccount())
+		signer, err = buildSSHAgentSigner(keyID, account, user)
 		if err != nil {
 			log.Fatal().Str("func", "initConfig").Msg("Error Creating Triton SSH Agent Signer")
 			return nil, err
@@ -96,16 +124,18 @@ func NewTritonConfig() (*TritonClientConfig, error) {
 			return nil, err
 		}
 
-		signer, err = buildPrivateKeySigner(GetTritonKeyID(), GetTritonAccount(), keyMaterial)
+		signer, err = buildPrivateKeySigner(keyID, account, user, keyMaterial, GetTritonKeyPassphrase())
 		if err != nil {
 			return nil, errors.Wrap(err, "Error Creating Triton SSH Private Key Signer")
 		}
 	}
 
 	config := &triton.ClientConfig{
-		TritonURL:   GetTritonURL(),
-		AccountName: GetTritonAccount(),
-		Signers:     []authentication.Signer{signer},
+		TritonURL:             tritonURL(profile),
+		AccountName:           account,
+		Username:              user,
+		Signers:               []authentication.Signer{signer},
+		InsecureSkipTLSVerify: profile != nil && profile.Insecure,
 	}
 
 	return &TritonClientConfig{
@@ -116,12 +146,20 @@ func NewTritonConfig() (*TritonClientConfig, error) {
 func NewMantaConfig() (*TritonClientConfig, error) {
 	viper.AutomaticEnv()
 
+	profile, err := SelectedProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := mantaKeyID(profile)
+	account := mantaAccount(profile)
+	user := mantaUser(profile)
+
 	var signer authentication.Signer
-	var err error
 
 	keyMaterial := GetMantaKeyMaterial()
 	if keyMaterial == "" {
-		signer, err = buildSSHAgentSigner(GetMantaKeyID(), GetMantaAccount())
+		signer, err = buildSSHAgentSigner(keyID, account, user)
 		if err != nil {
 			log.Fatal().Str("func", "initConfig").Msg("Error Creating Manta SSH Agent Signer")
 			return nil, err
@@ -132,16 +170,18 @@ func NewMantaConfig() (*TritonClientConfig, error) {
 			return nil, err
 		}
 
-		signer, err = buildPrivateKeySigner(GetMantaKeyID(), GetMantaAccount(), keyMaterial)
+		signer, err = buildPrivateKeySigner(keyID, account, user, keyMaterial, GetMantaKeyPassphrase())
 		if err != nil {
 			return nil, errors.Wrap(err, "Error Creating Manta SSH Private Key Signer")
 		}
 	}
 
 	config := &triton.ClientConfig{
-		MantaURL:    GetMantaURL(),
-		AccountName: GetMantaAccount(),
-		Signers:     []authentication.Signer{signer},
+		MantaURL:              GetMantaURL(),
+		AccountName:           account,
+		Username:              user,
+		Signers:               []authentication.Signer{signer},
+		InsecureSkipTLSVerify: profile != nil && profile.Insecure,
 	}
 
 	return &TritonClientConfig{
@@ -162,6 +202,63 @@ func getTritonEnvVar(name string) string {
 	return ""
 }
 
+// SelectedProfile returns the profile chosen with --profile, TRITON_PROFILE
+// or `triton profile set-current`. It returns nil when settings are taken
+// from the environment, i.e. when the "env" profile is selected.
+func SelectedProfile() (*triton.Profile, error) {
+	name := GetProfileName()
+	if name == "" {
+		current, err := triton.CurrentProfileName()
+		if err != nil {
+			return nil, err
+		}
+		name = current
+	}
+
+	if name == triton.EnvProfileName {
+		return nil, nil
+	}
+
+	return triton.GetProfile(name)
+}
+
+// selectedProfile returns the selected profile, or nil if none can be read.
+// It is used by the getters below, which resolve the profile on every call;
+// NewTritonConfig and NewMantaConfig resolve it once instead.
+func selectedProfile() *triton.Profile {
+	profile, err := SelectedProfile()
+	if err != nil {
+		return nil
+	}
+
+	return profile
+}
+
+// getSetting returns the first non-empty of the value set with a flag, the
+// value of the profile and the value of the environment, in that order.
+// profile may be nil, in which case it is skipped.
+func getSetting(flag string, profile *triton.Profile, value func(profile *triton.Profile) string, env string) string {
+	if flag != "" {
+		return flag
+	}
+	if profile != nil {
+		if v := value(profile); v != "" {
+			return v
+		}
+	}
+
+	return env
+}
+
+func GetProfileName() string {
+	name := viper.GetString(config.KeyProfile)
+	if name == "" {
+		name = getTritonEnvVar("PROFILE")
+	}
+
+	return name
+}
+
 func getMantaEnvVar(name string) string {
 	for _, prefix := range mantaEnvPrefixes {
 		if val := viper.GetString(prefix + "_" + name); val != "" {
@@ -173,12 +270,12 @@ func getMantaEnvVar(name string) string {
 }
 
 func GetTritonURL() string {
-	url := viper.GetString(config.KeyTritonURL)
-	if url == "" {
-		url = getTritonEnvVar("URL")
-	}
+	return tritonURL(selectedProfile())
+}
 
-	return url
+func tritonURL(profile *triton.Profile) string {
+	return getSetting(viper.GetString(config.KeyTritonURL), profile,
+		func(p *triton.Profile) string { return p.URL }, getTritonEnvVar("URL"))
 }
 
 func GetTritonKeyMaterial() string {
@@ -190,22 +287,35 @@ func GetTritonKeyMaterial() string {
 	return url
 }
 
+func GetTritonKeyPassphrase() string {
+	return getTritonEnvVar("KEY_PASSPHRASE")
+}
+
 func GetTritonAccount() string {
-	account := viper.GetString(config.KeyTritonAccount)
-	if account == "" {
-		account = getTritonEnvVar("ACCOUNT")
-	}
+	return tritonAccount(selectedProfile())
+}
+
+func tritonAccount(profile *triton.Profile) string {
+	return getSetting(viper.GetString(config.KeyTritonAccount), profile,
+		func(p *triton.Profile) string { return p.Account }, getTritonEnvVar("ACCOUNT"))
+}
+
+func GetTritonUser() string {
+	return tritonUser(selectedProfile())
+}
 
-	return account
+func tritonUser(profile *triton.Profile) string {
+	return getSetting(viper.GetString(config.KeyTritonUser), profile,
+		func(p *triton.Profile) string { return p.User }, getTritonEnvVar("USER"))
 }
 
 func GetTritonKeyID() string {
-	keyID := viper.GetString(config.KeyTritonSSHKeyID)
-	if keyID == "" {
-		keyID = getTritonEnvVar("KEY_ID")
-	}
+	return tritonKeyID(selectedProfile())
+}
 
-	return keyID
+func tritonKeyID(profile *triton.Profile) string {
+	return getSetting(viper.GetString(config.KeyTritonSSHKeyID), profile,
+		func(p *triton.Profile) string { return p.KeyID }, getTritonEnvVar("KEY_ID"))
 }
 
 func GetMantaURL() string {
@@ -226,22 +336,35 @@ func GetMantaKeyMaterial() string {
 	return url
 }
 
+func GetMantaKeyPassphrase() string {
+	return getMantaEnvVar("KEY_PASSPHRASE")
+}
+
 func GetMantaAccount() string {
-	account := viper.GetString(config.KeyMantaAccount)
-	if account == "" {
-		account = getMantaEnvVar("USER")
-	}
+	return mantaAccount(selectedProfile())
+}
+
+func mantaAccount(profile *triton.Profile) string {
+	return getSetting(viper.GetString(config.KeyMantaAccount), profile,
+		func(p *triton.Profile) string { return p.Account }, getMantaEnvVar("USER"))
+}
 
-	return account
+func GetMantaUser() string {
+	return mantaUser(selectedProfile())
+}
+
+func mantaUser(profile *triton.Profile) string {
+	return getSetting(viper.GetString(config.KeyMantaUser), profile,
+		func(p *triton.Profile) string { return p.User }, getMantaEnvVar("SUBUSER"))
 }
 
 func GetMantaKeyID() string {
-	keyID := viper.GetString(config.KeyMantaSSHKeyID)
-	if keyID == "" {
-		keyID = getMantaEnvVar("KEY_ID")
-	}
+	return mantaKeyID(selectedProfile())
+}
 
-	return keyID
+func mantaKeyID(profile *triton.Profile) string {
+	return getSetting(viper.GetString(config.KeyMantaSSHKeyID), profile,
+		func(p *triton.Profile) string { return p.KeyID }, getMantaEnvVar("KEY_ID"))
 }
 
 func GetPkgID() string {
@@ -322,6 +445,49 @@ func GetMachineAffinityRules() []string {
 	return nil
 }
 
+func GetMigrationAffinityRules() []string {
+	if viper.IsSet(config.KeyMigrationAffinity) {
+		return viper.GetStringSlice(config.KeyMigrationAffinity)
+	}
+	return nil
+}
+
+func GetMigrationWatch() bool {
+	return viper.GetBool(config.KeyMigrationWatch)
+}
+
+func GetInstanceAuditJSON() bool {
+	return viper.GetBool(config.KeyInstanceAuditJSON)
+}
+
+func GetDiskID() string {
+	return viper.GetString(config.KeyDiskID)
+}
+
+func GetDiskWait() bool {
+	return viper.GetBool(config.KeyDiskWait)
+}
+
+func GetDiskAddSize() string {
+	return viper.GetString(config.KeyDiskAddSize)
+}
+
+func GetDiskAddPCISlot() string {
+	return viper.GetString(config.KeyDiskAddPCISlot)
+}
+
+func GetDiskResizeSize() int64 {
+	return viper.GetInt64(config.KeyDiskResizeSize)
+}
+
+func GetDiskResizeAllowShrink() bool {
+	return viper.GetBool(config.KeyDiskResizeAllowShrink)
+}
+
+func GetUseUTC() bool {
+	return viper.GetBool(config.KeyUseUTC)
+}
+
 func GetMachineTags() map[string]interface{} {
 	if viper.IsSet(config.KeyInstanceTag) {
 		tags := make(map[string]interface{}, 0)
@@ -371,6 +537,22 @@ func GetMachineUserdata() string {
 	return viper.GetString(config.KeyInstanceUserdata)
 }
 
+func GetMachineDisks() []string {
+	return viper.GetStringSlice(config.KeyInstanceDisks)
+}
+
+func GetMachineDeletionProtection() bool {
+	return viper.GetBool(config.KeyInstanceDeletionProtection)
+}
+
+func GetMachineEncrypted() bool {
+	return viper.GetBool(config.KeyInstanceEncrypted)
+}
+
+func GetMachineAllowSharedImages() bool {
+	return viper.GetBool(config.KeyInstanceAllowSharedImages)
+}
+
 func GetAccountEmail() string {
 	return viper.GetString(config.KeyAccountEmail)
 }
@@ -426,10 +608,74 @@ func GetSSHKey() string {
 	return viper.GetString(config.KeySSHKey)
 }
 
+func GetProfileInsecure() bool {
+	return viper.GetBool(config.KeyProfileInsecure)
+}
+
 func GetAccessKeyID() string {
 	return viper.GetString(config.KeyAccessKeyID)
 }
 
+func GetSyncDelete() bool {
+	return viper.GetBool(config.KeySyncDelete)
+}
+
+func GetSyncDryRun() bool {
+	return viper.GetBool(config.KeySyncDryRun)
+}
+
+func GetSyncExclude() []string {
+	return viper.GetStringSlice(config.KeySyncExclude)
+}
+
+func GetSyncChecksum() bool {
+	return viper.GetBool(config.KeySyncChecksum)
+}
+
+func GetSyncReverse() bool {
+	return viper.GetBool(config.KeySyncReverse)
+}
+
+func GetSyncParallel() int {
+	return viper.GetInt(config.KeySyncParallel)
+}
+
+func GetGetOutput() string {
+	return viper.GetString(config.KeyGetOutput)
+}
+
+func GetPutFile() string {
+	return viper.GetString(config.KeyPutFile)
+}
+
+func GetPutParents() bool {
+	return viper.GetBool(config.KeyPutParents)
+}
+
+func GetPutContentType() string {
+	return viper.GetString(config.KeyPutContentType)
+}
+
+func GetPutCopies() uint64 {
+	return uint64(viper.GetInt(config.KeyPutCopies))
+}
+
+func GetRmRecursive() bool {
+	return viper.GetBool(config.KeyRmRecursive)
+}
+
+func GetMkdirParents() bool {
+	return viper.GetBool(config.KeyMkdirParents)
+}
+
+func GetSignExpires() time.Duration {
+	return viper.GetDuration(config.KeySignExpires)
+}
+
+func GetSignMethod() string {
+	return viper.GetString(config.KeySignMethod)
+}
+
 func IsBlockingAction() bool {
 	return viper.GetBool(config.KeyInstanceWait)
 }
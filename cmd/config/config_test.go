@@ -0,0 +1,114 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	triton "github.com/joyent/triton-go/v2"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/spf13/viper"
+)
+
+const (
+	encryptedKeyPath        = "../../authentication/testdata/rsa_encrypted.pem"
+	encryptedKeyFingerprint = "85:1c:23:52:bd:37:c3:cc:a9:ed:e1:ff:0f:a2:b2:5c"
+)
+
+// withoutTerminal runs fn with stdin redirected from an empty file, so that no
+// passphrase can be prompted for.
+func withoutTerminal(t *testing.T, fn func()) {
+	f, err := ioutil.TempFile("", "triton-go-stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	stdin := os.Stdin
+	os.Stdin = f
+	defer func() { os.Stdin = stdin }()
+
+	fn()
+}
+
+func TestBuildPrivateKeySigner(t *testing.T) {
+	keyMaterial, err := getKeyMaterialContents(encryptedKeyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("passphrase", func(t *testing.T) {
+		signer, err := buildPrivateKeySigner(encryptedKeyFingerprint, "test.user", "", keyMaterial, "triton-go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signer.KeyFingerprint() != encryptedKeyFingerprint {
+			t.Errorf("expected fingerprint %s: got %s", encryptedKeyFingerprint, signer.KeyFingerprint())
+		}
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		_, err := buildPrivateKeySigner(encryptedKeyFingerprint, "test.user", "", keyMaterial, "wrong")
+		if err == nil || !strings.Contains(err.Error(), "decryption password incorrect") {
+			t.Errorf("expected incorrect passphrase error: got %v", err)
+		}
+	})
+
+	t.Run("no terminal", func(t *testing.T) {
+		withoutTerminal(t, func() {
+			_, err := buildPrivateKeySigner(encryptedKeyFingerprint, "test.user", "", keyMaterial, "")
+			if err == nil || !strings.Contains(err.Error(), "set TRITON_KEY_PASSPHRASE or run from a terminal") {
+				t.Errorf("expected missing passphrase error: got %v", err)
+			}
+		})
+	})
+}
+
+func TestSettingPrecedence(t *testing.T) {
+	viper.AutomaticEnv()
+	os.Setenv("TRITON_USER", "env.user")
+	os.Setenv("TRITON_ACCOUNT", "env.account")
+	defer os.Unsetenv("TRITON_USER")
+	defer os.Unsetenv("TRITON_ACCOUNT")
+
+	profile := &triton.Profile{Account: "profile.account", User: "profile.user"}
+
+	t.Run("env", func(t *testing.T) {
+		if user := tritonUser(nil); user != "env.user" {
+			t.Errorf("expected the environment to be used: got %q", user)
+		}
+	})
+
+	t.Run("profile", func(t *testing.T) {
+		if user := tritonUser(profile); user != "profile.user" {
+			t.Errorf("expected the profile to take precedence over the environment: got %q", user)
+		}
+		if account := tritonAccount(profile); account != "profile.account" {
+			t.Errorf("expected the profile to take precedence over the environment: got %q", account)
+		}
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		viper.Set(config.KeyTritonUser, "flag.user")
+		viper.Set(config.KeyTritonAccount, "flag.account")
+		defer viper.Set(config.KeyTritonUser, nil)
+		defer viper.Set(config.KeyTritonAccount, nil)
+
+		if user := tritonUser(profile); user != "flag.user" {
+			t.Errorf("expected the flag to take precedence over the profile: got %q", user)
+		}
+		if account := tritonAccount(profile); account != "flag.account" {
+			t.Errorf("expected the flag to take precedence over the profile: got %q", account)
+		}
+	})
+}
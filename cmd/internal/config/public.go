@@ -9,15 +9,19 @@
 package config
 
 const (
+	KeyProfile = "general.profile"
+
 	KeyTritonAccount        = "general.triton.account"
 	KeyTritonURL            = "general.triton.url"
 	KeyTritonSSHKeyMaterial = "general.triton.key-material"
 	KeyTritonSSHKeyID       = "general.triton.key-id"
+	KeyTritonUser           = "general.triton.user"
 
 	KeyMantaAccount        = "general.manta.account"
 	KeyMantaURL            = "general.manta.url"
 	KeyMantaSSHKeyMaterial = "general.manta.key-material"
 	KeyMantaSSHKeyID       = "general.manta.key-id"
+	KeyMantaUser           = "general.manta.user"
 
 	DefaultManDir = "./docs/man"
 	ManSect       = 8
@@ -53,6 +57,23 @@ const (
 	KeyInstanceUserdata     = "compute.instance.userdata"
 	KeyInstanceNamePrefix   = "compute.instance.name-prefix"
 
+	KeyInstanceDisks              = "compute.instance.disks"
+	KeyInstanceDeletionProtection = "compute.instance.deletion-protection"
+	KeyInstanceEncrypted          = "compute.instance.encrypted"
+	KeyInstanceAllowSharedImages  = "compute.instance.allow-shared-images"
+
+	KeyMigrationAffinity = "compute.instance.migration.affinity"
+	KeyMigrationWatch    = "compute.instance.migration.watch"
+
+	KeyInstanceAuditJSON = "compute.instance.audit.json"
+
+	KeyDiskID                = "compute.instance.disk.id"
+	KeyDiskWait              = "compute.instance.disk.wait"
+	KeyDiskAddSize           = "compute.instance.disk.add.size"
+	KeyDiskAddPCISlot        = "compute.instance.disk.add.pci-slot"
+	KeyDiskResizeSize        = "compute.instance.disk.resize.size"
+	KeyDiskResizeAllowShrink = "compute.instance.disk.resize.dangerous-allow-shrink"
+
 	KeyPackageName   = "compute.package.name"
 	KeyPackageID     = "compute.package.id"
 	KeyPackageMemory = "compute.package.memory"
@@ -69,6 +90,8 @@ const (
 
 	KeyAccessKeyID = "accesskeys.accesskeyid"
 
+	KeyProfileInsecure = "profile.insecure"
+
 	KeyAccountEmail            = "account.email"
 	KeyAccountCompanyName      = "account.companyname"
 	KeyAccountFirstName        = "account.firstname"
@@ -80,4 +103,21 @@ const (
 	KeyAccountCountry          = "account.country"
 	KeyAccountPhone            = "account.phone"
 	KeyAccountTritonCNSEnabled = "account.triton_cns_enabled"
+
+	KeySyncDelete   = "storage.sync.delete"
+	KeySyncDryRun   = "storage.sync.dry-run"
+	KeySyncExclude  = "storage.sync.exclude"
+	KeySyncChecksum = "storage.sync.checksum"
+	KeySyncReverse  = "storage.sync.reverse"
+	KeySyncParallel = "storage.sync.parallel"
+
+	KeyGetOutput      = "storage.get.output"
+	KeyPutFile        = "storage.put.file"
+	KeyPutParents     = "storage.put.parents"
+	KeyPutContentType = "storage.put.content-type"
+	KeyPutCopies      = "storage.put.copies"
+	KeyRmRecursive    = "storage.rm.recursive"
+	KeyMkdirParents   = "storage.mkdir.parents"
+	KeySignExpires    = "storage.sign.expires"
+	KeySignMethod     = "storage.sign.method"
 )
@@ -0,0 +1,76 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package get
+
+import (
+	"io"
+	"os"
+
+	"github.com/joyent/triton-go/v2/cmd/agent/storage"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Use:          "get OBJECT",
+		Short:        "download an object",
+		SilenceUsage: true,
+		Example: `
+$ manta get /stor/build.log
+$ manta get -o build.tgz /stor/builds/latest.tgz
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := cfg.NewMantaConfig()
+			if err != nil {
+				return err
+			}
+
+			s, err := storage.NewStorageClient(c)
+			if err != nil {
+				return err
+			}
+
+			if cfg.GetGetOutput() != "" {
+				_, err := s.DownloadObject(args[0])
+				return err
+			}
+
+			object, err := s.GetObject(args[0])
+			if err != nil {
+				return err
+			}
+			defer object.ObjectReader.Close()
+
+			_, err = io.Copy(os.Stdout, object.ObjectReader)
+			return err
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		{
+			const (
+				key          = config.KeyGetOutput
+				longName     = "output"
+				shortName    = "o"
+				defaultValue = ""
+				description  = "Write the object to a file rather than stdout, fetching large objects in parallel ranges"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.StringP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,75 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package info
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/joyent/triton-go/v2/cmd/agent/storage"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Use:          "info PATH",
+		Short:        "show the headers and metadata of an object or directory",
+		SilenceUsage: true,
+		Example: `
+$ manta info /stor/builds/latest.tgz
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			c, err := cfg.NewMantaConfig()
+			if err != nil {
+				return err
+			}
+
+			s, err := storage.NewStorageClient(c)
+			if err != nil {
+				return err
+			}
+
+			info, err := s.GetObjectInfo(args[0])
+			if err != nil {
+				return err
+			}
+
+			cons.Write([]byte(fmt.Sprintf("content-length: %d\n", info.ContentLength)))
+			if info.ContentMD5 != "" {
+				cons.Write([]byte(fmt.Sprintf("content-md5: %s\n", info.ContentMD5)))
+			}
+			cons.Write([]byte(fmt.Sprintf("content-type: %s\n", info.ContentType)))
+			cons.Write([]byte(fmt.Sprintf("etag: %s\n", info.ETag)))
+			if !info.LastModified.IsZero() {
+				cons.Write([]byte(fmt.Sprintf("last-modified: %s\n", info.LastModified.UTC().Format(http.TimeFormat))))
+			}
+
+			keys := make([]string, 0, len(info.Metadata))
+			for key := range info.Metadata {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				cons.Write([]byte(fmt.Sprintf("%s: %s\n", key, info.Metadata[key])))
+			}
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		return nil
+	},
+}
@@ -49,10 +49,12 @@ $ manta ls /stor
 				return err
 			}
 
-			cons.Write([]byte(fmt.Sprintf("Found %d directory entries", directoryOutput.ResultSetSize)))
-
 			for _, entry := range directoryOutput.Entries {
-				cons.Write([]byte(fmt.Sprintf("\n%s/", entry.Name)))
+				name := entry.Name
+				if entry.Type == "directory" {
+					name += "/"
+				}
+				cons.Write([]byte(fmt.Sprintf("%s\n", name)))
 			}
 
 			return nil
@@ -0,0 +1,44 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package ln
+
+import (
+	"github.com/joyent/triton-go/v2/cmd/agent/storage"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.ExactArgs(2),
+		Use:          "ln SOURCE LINK",
+		Short:        "make a SnapLink to an object",
+		SilenceUsage: true,
+		Example: `
+$ manta ln /stor/builds/1234.tgz /stor/builds/latest.tgz
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := cfg.NewMantaConfig()
+			if err != nil {
+				return err
+			}
+
+			s, err := storage.NewStorageClient(c)
+			if err != nil {
+				return err
+			}
+
+			return s.PutSnapLink(args[0], args[1])
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		return nil
+	},
+}
@@ -0,0 +1,68 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package mkdir
+
+import (
+	"github.com/joyent/triton-go/v2/cmd/agent/storage"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.MinimumNArgs(1),
+		Use:          "mkdir DIRECTORY...",
+		Short:        "make directories",
+		SilenceUsage: true,
+		Example: `
+$ manta mkdir /stor/builds
+$ manta mkdir -p /stor/builds/2020/06
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := cfg.NewMantaConfig()
+			if err != nil {
+				return err
+			}
+
+			s, err := storage.NewStorageClient(c)
+			if err != nil {
+				return err
+			}
+
+			for _, arg := range args {
+				if err := s.MakeDirectory(arg); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		{
+			const (
+				key          = config.KeyMkdirParents
+				longName     = "parents"
+				shortName    = "p"
+				defaultValue = false
+				description  = "Create missing parent directories"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
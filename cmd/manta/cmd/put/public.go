@@ -0,0 +1,103 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package put
+
+import (
+	"github.com/joyent/triton-go/v2/cmd/agent/storage"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Use:          "put OBJECT",
+		Short:        "upload an object from a file or stdin",
+		SilenceUsage: true,
+		Example: `
+$ manta put -f build.tgz /stor/builds/latest.tgz
+$ tar cz . | manta put --parents --content-type application/gzip /stor/backups/site.tgz
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := cfg.NewMantaConfig()
+			if err != nil {
+				return err
+			}
+
+			s, err := storage.NewStorageClient(c)
+			if err != nil {
+				return err
+			}
+
+			return s.PutObject(args[0])
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		{
+			const (
+				key          = config.KeyPutFile
+				longName     = "file"
+				shortName    = "f"
+				defaultValue = ""
+				description  = "File to upload (defaults to stdin)"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.StringP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		{
+			const (
+				key          = config.KeyPutParents
+				longName     = "parents"
+				shortName    = "p"
+				defaultValue = false
+				description  = "Create missing parent directories"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = config.KeyPutContentType
+				longName     = "content-type"
+				defaultValue = ""
+				description  = "Content type of the object (defaults to one detected from the file extension or content)"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.String(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		{
+			const (
+				key          = config.KeyPutCopies
+				longName     = "copies"
+				shortName    = "c"
+				defaultValue = 0
+				description  = "Number of copies Manta stores of the object, i.e. its durability level (defaults to Manta's default of 2)"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.IntP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,68 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package rm
+
+import (
+	"github.com/joyent/triton-go/v2/cmd/agent/storage"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.MinimumNArgs(1),
+		Use:          "rm PATH...",
+		Short:        "remove objects and directories",
+		SilenceUsage: true,
+		Example: `
+$ manta rm /stor/build.log
+$ manta rm -r /stor/builds/2019
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := cfg.NewMantaConfig()
+			if err != nil {
+				return err
+			}
+
+			s, err := storage.NewStorageClient(c)
+			if err != nil {
+				return err
+			}
+
+			for _, arg := range args {
+				if err := s.DeleteObject(arg); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		{
+			const (
+				key          = config.KeyRmRecursive
+				longName     = "recursive"
+				shortName    = "r"
+				defaultValue = false
+				description  = "Remove directories and their contents"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
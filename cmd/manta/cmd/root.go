@@ -15,8 +15,16 @@ import (
 	"github.com/joyent/triton-go/v2/cmd/internal/config"
 	"github.com/joyent/triton-go/v2/cmd/internal/logger"
 	"github.com/joyent/triton-go/v2/cmd/manta/cmd/docs"
+	"github.com/joyent/triton-go/v2/cmd/manta/cmd/get"
+	"github.com/joyent/triton-go/v2/cmd/manta/cmd/info"
 	"github.com/joyent/triton-go/v2/cmd/manta/cmd/list"
+	"github.com/joyent/triton-go/v2/cmd/manta/cmd/ln"
+	"github.com/joyent/triton-go/v2/cmd/manta/cmd/mkdir"
+	"github.com/joyent/triton-go/v2/cmd/manta/cmd/put"
+	"github.com/joyent/triton-go/v2/cmd/manta/cmd/rm"
 	"github.com/joyent/triton-go/v2/cmd/manta/cmd/shell"
+	"github.com/joyent/triton-go/v2/cmd/manta/cmd/sign"
+	"github.com/joyent/triton-go/v2/cmd/manta/cmd/sync"
 	"github.com/joyent/triton-go/v2/cmd/manta/cmd/version"
 	isatty "github.com/mattn/go-isatty"
 	"github.com/sean-/conswriter"
@@ -27,6 +35,14 @@ import (
 var subCommands = []*command.Command{
 	version.Cmd,
 	list.Cmd,
+	get.Cmd,
+	put.Cmd,
+	rm.Cmd,
+	mkdir.Cmd,
+	info.Cmd,
+	ln.Cmd,
+	sign.Cmd,
+	sync.Cmd,
 	docs.Cmd,
 	shell.Cmd,
 }
@@ -118,6 +134,19 @@ var rootCmd = &command.Command{
 			viper.SetDefault(key, defaultValue)
 		}
 
+		{
+			const (
+				key          = config.KeyProfile
+				longName     = "profile"
+				defaultValue = ""
+				description  = "Name of the profile (see 'triton profile list') to take the URL, account and key from. If not specified, the environment variable TRITON_PROFILE or the current profile will be used"
+			)
+
+			flags := parent.Cobra.PersistentFlags()
+			flags.String(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
 		{
 			const (
 				key          = config.KeyMantaAccount
@@ -132,6 +161,19 @@ var rootCmd = &command.Command{
 			viper.BindPFlag(key, flags.Lookup(longName))
 		}
 
+		{
+			const (
+				key          = config.KeyMantaUser
+				longName     = "user"
+				defaultValue = ""
+				description  = "Name of the RBAC sub-user of the account to authenticate as. If not specified, the environment variable MANTA_SUBUSER will be used"
+			)
+
+			flags := parent.Cobra.PersistentFlags()
+			flags.String(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
 		{
 			const (
 				key          = config.KeyMantaURL
@@ -165,7 +207,7 @@ var rootCmd = &command.Command{
 				key          = config.KeyTritonSSHKeyMaterial
 				longName     = "key-material"
 				defaultValue = ""
-				description  = "This is the private key of an SSH key associated with the Triton account to be used. If this is not set, the private key corresponding to the fingerprint in key_id must be available via an SSH Agent. It can be provided via the SDC_KEY_MATERIAL or TRITON_KEY_MATERIAL environment variables."
+				description  = "This is the private key of an SSH key associated with the Triton account to be used. If this is not set, the private key corresponding to the fingerprint in key_id must be available via an SSH Agent. It can be provided via the SDC_KEY_MATERIAL or TRITON_KEY_MATERIAL environment variables. The passphrase of an encrypted key is read from the TRITON_KEY_PASSPHRASE environment variable, or prompted for on a terminal."
 			)
 
 			flags := parent.Cobra.PersistentFlags()
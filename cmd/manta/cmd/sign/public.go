@@ -0,0 +1,91 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package sign
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/joyent/triton-go/v2/cmd/agent/storage"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Use:          "sign OBJECT",
+		Short:        "create a signed URL to an object",
+		SilenceUsage: true,
+		Example: `
+$ manta sign /stor/builds/latest.tgz
+$ manta sign --expires 24h /stor/builds/latest.tgz
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			c, err := cfg.NewMantaConfig()
+			if err != nil {
+				return err
+			}
+
+			s, err := storage.NewStorageClient(c)
+			if err != nil {
+				return err
+			}
+
+			signedURL, err := s.SignURL(args[0])
+			if err != nil {
+				return err
+			}
+
+			cons.Write([]byte(fmt.Sprintf("%s\n", signedURL)))
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		{
+			const (
+				key          = config.KeySignExpires
+				longName     = "expires"
+				shortName    = "e"
+				defaultValue = time.Hour
+				description  = "How long the URL is valid for"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.DurationP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = config.KeySignMethod
+				longName     = "method"
+				shortName    = "m"
+				defaultValue = http.MethodGet
+				description  = "HTTP method the URL is valid for"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.StringP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
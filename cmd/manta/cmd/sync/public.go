@@ -0,0 +1,160 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package sync
+
+import (
+	"fmt"
+
+	"github.com/joyent/triton-go/v2/cmd/agent/storage"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	tss "github.com/joyent/triton-go/v2/storage/sync"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.ExactArgs(2),
+		Use:          "sync LOCAL_DIR MANTA_DIR",
+		Short:        "sync a local directory with a Manta directory",
+		SilenceUsage: true,
+		Example: `
+$ manta sync ./build /stor/builds/latest
+$ manta sync --delete --exclude '*.tmp' ./site /public/site
+$ manta sync --reverse ./artifacts /stor/builds/latest
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			c, err := cfg.NewMantaConfig()
+			if err != nil {
+				return err
+			}
+
+			s, err := storage.NewStorageClient(c)
+			if err != nil {
+				return err
+			}
+
+			prefix := ""
+			if cfg.GetSyncDryRun() {
+				prefix = "(dry run) "
+			}
+
+			output, err := s.Sync(args[0], args[1], func(action tss.Action) {
+				path := action.Path
+				if action.Dir {
+					path += "/"
+				}
+				cons.Write([]byte(fmt.Sprintf("%s%-8s %s\n", prefix, action.Type, path)))
+			})
+			if err != nil {
+				return err
+			}
+
+			cons.Write([]byte(fmt.Sprintf("%s%d changes, %d files unchanged", prefix, len(output.Actions), output.Unchanged)))
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		{
+			const (
+				key          = config.KeySyncReverse
+				longName     = "reverse"
+				shortName    = "r"
+				defaultValue = false
+				description  = "Sync from MANTA_DIR to LOCAL_DIR rather than from LOCAL_DIR to MANTA_DIR"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = config.KeySyncDelete
+				longName     = "delete"
+				shortName    = "d"
+				defaultValue = false
+				description  = "Delete files and directories of the destination which do not exist at the source"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = config.KeySyncDryRun
+				longName     = "dry-run"
+				shortName    = "n"
+				defaultValue = false
+				description  = "Print the changes without making them"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key         = config.KeySyncExclude
+				longName    = "exclude"
+				shortName   = "x"
+				description = "Skip files and directories whose name or relative path matches a glob pattern. This option can be used multiple times."
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.StringSliceP(longName, shortName, nil, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		{
+			const (
+				key          = config.KeySyncChecksum
+				longName     = "checksum"
+				shortName    = "c"
+				defaultValue = false
+				description  = "Compare the MD5 of files of the same size rather than their modification times"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = config.KeySyncParallel
+				longName     = "parallel"
+				shortName    = "p"
+				defaultValue = 4
+				description  = "Number of files transferred at once"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.IntP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
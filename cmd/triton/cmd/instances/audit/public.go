@@ -0,0 +1,131 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joyent/triton-go/v2/cmd/agent/compute"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.NoArgs,
+		Use:          "audit",
+		Short:        "list the actions taken on a triton instance",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.GetMachineID() == "" && cfg.GetMachineName() == "" {
+				return errors.New("Either `id` or `name` must be specified")
+			}
+
+			if cfg.GetMachineID() != "" && cfg.GetMachineName() != "" {
+				return errors.New("Only 1 of `id` or `name` must be specified")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			c, err := cfg.NewTritonConfig()
+			if err != nil {
+				return err
+			}
+
+			a, err := compute.NewComputeClient(c)
+			if err != nil {
+				return err
+			}
+
+			_, entries, err := a.GetInstanceAudit()
+			if err != nil {
+				return err
+			}
+
+			if cfg.GetInstanceAuditJSON() {
+				b, err := json.Marshal(entries)
+				if err != nil {
+					return err
+				}
+
+				output, _ := prettyPrintJSON(b)
+				cons.Write(output)
+
+				return nil
+			}
+
+			table := tablewriter.NewWriter(cons)
+			table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeaderLine(false)
+			table.SetAutoFormatHeaders(true)
+
+			table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT})
+			table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+			table.SetCenterSeparator("")
+			table.SetColumnSeparator("")
+			table.SetRowSeparator("")
+
+			table.SetHeader([]string{"TIME", "ACTION", "CALLER", "IP", "SUCCESS"})
+
+			for _, entry := range entries {
+				t := entry.Time.Local()
+				if cfg.GetUseUTC() {
+					t = entry.Time.UTC()
+				}
+
+				caller := entry.Caller.Type
+				if entry.Caller.KeyID != "" {
+					caller = fmt.Sprintf("%s (%s)", entry.Caller.Type, entry.Caller.KeyID)
+				}
+
+				table.Append([]string{t.Format(time.RFC3339), entry.Action, caller, entry.Caller.IP, fmt.Sprintf("%t", entry.Success)})
+			}
+
+			table.Render()
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		{
+			const (
+				key          = config.KeyInstanceAuditJSON
+				longName     = "json"
+				shortName    = "j"
+				defaultValue = false
+				description  = "Print the audit entries as JSON"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
+
+func prettyPrintJSON(b []byte) ([]byte, error) {
+	var out bytes.Buffer
+	err := json.Indent(&out, b, "", "    ")
+	return out.Bytes(), err
+}
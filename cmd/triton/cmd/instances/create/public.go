@@ -148,6 +148,67 @@ var Cmd = &command.Command{
 			viper.SetDefault(key, defaultValue)
 		}
 
+		{
+			const (
+				key          = config.KeyInstanceDeletionProtection
+				longName     = "deletion-protection"
+				defaultValue = false
+				description  = "Enable deletion protection on this instance (defaults to false)"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.Bool(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = config.KeyInstanceEncrypted
+				longName     = "encrypted"
+				defaultValue = false
+				description  = "Provision this instance on an encrypted compute node (defaults to false)"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.Bool(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key          = config.KeyInstanceAllowSharedImages
+				longName     = "allow-shared-images"
+				defaultValue = false
+				description  = "Allow this instance to be created from an image shared with the account (defaults to false)"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.Bool(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+
+			viper.SetDefault(key, defaultValue)
+		}
+
+		{
+			const (
+				key         = config.KeyInstanceDisks
+				longName    = "disks"
+				description = `One or more comma-separated disk sizes in MiB for a bhyve
+instance whose package has flexible disk. The first disk is the
+boot disk, sized after the image if 0. One disk may be "remaining"
+to take the space left in the package. This option can be used
+multiple times.`
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.StringSlice(longName, nil, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
 		{
 			const (
 				key         = config.KeyInstanceNetwork
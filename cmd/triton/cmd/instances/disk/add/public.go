@@ -0,0 +1,97 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package add
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joyent/triton-go/v2/cmd/agent/compute"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.NoArgs,
+		Use:          "add",
+		Short:        "add a disk to a stopped bhyve instance",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.GetMachineID() == "" && cfg.GetMachineName() == "" {
+				return errors.New("Either `id` or `name` must be specified")
+			}
+
+			if cfg.GetMachineID() != "" && cfg.GetMachineName() != "" {
+				return errors.New("Only 1 of `id` or `name` must be specified")
+			}
+
+			if cfg.GetDiskAddSize() == "" {
+				return errors.New("`size` must be specified")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			c, err := cfg.NewTritonConfig()
+			if err != nil {
+				return err
+			}
+
+			a, err := compute.NewComputeClient(c)
+			if err != nil {
+				return err
+			}
+
+			instance, disk, err := a.AddDisk()
+			if err != nil {
+				return err
+			}
+
+			cons.Write([]byte(fmt.Sprintf("Added disk %s (%d MiB) to instance %q: %s\n", disk.ID, disk.Size, instance.Name, disk.State)))
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		{
+			const (
+				key          = config.KeyDiskAddSize
+				longName     = "size"
+				defaultValue = ""
+				description  = "Size of the disk in MiB, or \"remaining\" for all the space left in the flexible disk budget of the instance"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.String(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		{
+			const (
+				key          = config.KeyDiskAddPCISlot
+				longName     = "pci-slot"
+				defaultValue = ""
+				description  = "PCI slot to attach the disk to, e.g. 0:4:1"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.String(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,69 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package get
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joyent/triton-go/v2/cmd/agent/compute"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.NoArgs,
+		Use:          "get",
+		Short:        "get a disk of a bhyve instance",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.GetMachineID() == "" && cfg.GetMachineName() == "" {
+				return errors.New("Either `id` or `name` must be specified")
+			}
+
+			if cfg.GetMachineID() != "" && cfg.GetMachineName() != "" {
+				return errors.New("Only 1 of `id` or `name` must be specified")
+			}
+
+			if cfg.GetDiskID() == "" {
+				return errors.New("`disk` must be specified")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			c, err := cfg.NewTritonConfig()
+			if err != nil {
+				return err
+			}
+
+			a, err := compute.NewComputeClient(c)
+			if err != nil {
+				return err
+			}
+
+			_, disk, err := a.GetDisk()
+			if err != nil {
+				return err
+			}
+
+			cons.Write([]byte(fmt.Sprintf("ID: %s\nSize: %d MiB\nBoot: %t\nState: %s\nPCI Slot: %s\n", disk.ID, disk.Size, disk.Boot, disk.State, disk.PCISlot)))
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		return nil
+	},
+}
@@ -0,0 +1,75 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package disk
+
+import (
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/disk/add"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/disk/delete"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/disk/get"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/disk/list"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/disk/resize"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Use:     "disk",
+		Aliases: []string{"disks"},
+		Short:   "manage the disks of bhyve instances",
+	},
+
+	Setup: func(parent *command.Command) error {
+
+		cmds := []*command.Command{
+			list.Cmd,
+			get.Cmd,
+			add.Cmd,
+			resize.Cmd,
+			delete.Cmd,
+		}
+
+		for _, cmd := range cmds {
+			cmd.Setup(cmd)
+			parent.Cobra.AddCommand(cmd.Cobra)
+		}
+
+		{
+			const (
+				key          = config.KeyDiskID
+				longName     = "disk"
+				defaultValue = ""
+				description  = "Disk ID"
+			)
+
+			flags := parent.Cobra.PersistentFlags()
+			flags.String(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		{
+			const (
+				key          = config.KeyDiskWait
+				longName     = "wait"
+				shortName    = "w"
+				defaultValue = false
+				description  = "Wait until the disk is running, or deleted, before returning"
+			)
+
+			flags := parent.Cobra.PersistentFlags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
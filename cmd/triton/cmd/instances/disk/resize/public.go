@@ -0,0 +1,102 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package resize
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joyent/triton-go/v2/cmd/agent/compute"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.NoArgs,
+		Use:          "resize",
+		Short:        "resize a disk of a stopped bhyve instance",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.GetMachineID() == "" && cfg.GetMachineName() == "" {
+				return errors.New("Either `id` or `name` must be specified")
+			}
+
+			if cfg.GetMachineID() != "" && cfg.GetMachineName() != "" {
+				return errors.New("Only 1 of `id` or `name` must be specified")
+			}
+
+			if cfg.GetDiskID() == "" {
+				return errors.New("`disk` must be specified")
+			}
+
+			if cfg.GetDiskResizeSize() <= 0 {
+				return errors.New("`size` must be specified")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			c, err := cfg.NewTritonConfig()
+			if err != nil {
+				return err
+			}
+
+			a, err := compute.NewComputeClient(c)
+			if err != nil {
+				return err
+			}
+
+			instance, disk, err := a.ResizeDisk()
+			if err != nil {
+				return err
+			}
+
+			cons.Write([]byte(fmt.Sprintf("Resized disk %s of instance %q to %d MiB: %s\n", disk.ID, instance.Name, disk.Size, disk.State)))
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		{
+			const (
+				key          = config.KeyDiskResizeSize
+				longName     = "size"
+				defaultValue = 0
+				description  = "New size of the disk in MiB"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.Int64(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		{
+			const (
+				key          = config.KeyDiskResizeAllowShrink
+				longName     = "dangerous-allow-shrink"
+				defaultValue = false
+				description  = "Allow the disk to shrink. Any data past the new size is lost"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.Bool(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,93 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package get
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joyent/triton-go/v2/cmd/agent/compute"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/olekukonko/tablewriter"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.NoArgs,
+		Use:          "get",
+		Short:        "get the migration of an instance",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.GetMachineID() == "" && cfg.GetMachineName() == "" {
+				return errors.New("Either `id` or `name` must be specified")
+			}
+
+			if cfg.GetMachineID() != "" && cfg.GetMachineName() != "" {
+				return errors.New("Only 1 of `id` or `name` must be specified")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			c, err := cfg.NewTritonConfig()
+			if err != nil {
+				return err
+			}
+
+			a, err := compute.NewComputeClient(c)
+			if err != nil {
+				return err
+			}
+
+			instance, migration, err := a.GetMigration()
+			if err != nil {
+				return err
+			}
+
+			table := tablewriter.NewWriter(cons)
+			table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeaderLine(false)
+			table.SetAutoFormatHeaders(true)
+
+			table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT})
+			table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+			table.SetCenterSeparator("")
+			table.SetColumnSeparator("")
+			table.SetRowSeparator("")
+
+			table.SetHeader([]string{"------", "------"})
+
+			table.Append([]string{"id", migration.Machine})
+			table.Append([]string{"name", instance.Name})
+			table.Append([]string{"phase", migration.Phase})
+			table.Append([]string{"state", migration.State})
+			table.Append([]string{"automatic", fmt.Sprintf("%t", migration.Automatic)})
+			table.Append([]string{"created", cfg.FormatTime(migration.CreatedTime)})
+			if migration.Error != "" {
+				table.Append([]string{"error", migration.Error})
+			}
+			for _, progress := range migration.ProgressHistory {
+				table.Append([]string{"progress", a.FormatMigrationProgress(progress)})
+			}
+
+			table.Render()
+
+			return nil
+		},
+	},
+
+	Setup: func(parent *command.Command) error {
+		return nil
+	},
+}
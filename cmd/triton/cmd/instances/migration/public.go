@@ -0,0 +1,139 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package migration
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joyent/triton-go/v2/cmd/agent/compute"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/migration/get"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/migration/list"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/migration/watch"
+	tcc "github.com/joyent/triton-go/v2/compute"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Use:     "migration",
+		Aliases: []string{"migrations", "migrate"},
+		Short:   "migrate instances to other compute nodes",
+	},
+
+	Setup: func(parent *command.Command) error {
+
+		cmds := []*command.Command{
+			list.Cmd,
+			get.Cmd,
+			watch.Cmd,
+			actionCmd(tcc.MigrationBegin, "begin the migration of an instance by provisioning its target"),
+			actionCmd(tcc.MigrationSync, "sync the filesystem of an instance to its migration target"),
+			actionCmd(tcc.MigrationSwitch, "stop an instance, sync it a last time and switch over to its migration target"),
+			actionCmd(tcc.MigrationPause, "pause a running migration sync"),
+			actionCmd(tcc.MigrationAbort, "abort a migration, removing its target"),
+			actionCmd(tcc.MigrationAutomatic, "migrate an instance, running the begin, sync and switch phases in order"),
+			actionCmd(tcc.MigrationFinalize, "remove the original instance of a migration which switched over"),
+		}
+
+		for _, cmd := range cmds {
+			cmd.Setup(cmd)
+			parent.Cobra.AddCommand(cmd.Cobra)
+		}
+
+		{
+			const (
+				key         = config.KeyMigrationAffinity
+				longName    = "affinity"
+				description = "Affinity rule constraining the target compute node of the begin and automatic actions, e.g. instance!=db0. This flag can be used multiple times"
+			)
+
+			flags := parent.Cobra.PersistentFlags()
+			flags.StringSlice(longName, nil, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		{
+			const (
+				key          = config.KeyMigrationWatch
+				longName     = "watch"
+				shortName    = "w"
+				defaultValue = false
+				description  = "Follow the progress of the migration until the phase started ends"
+			)
+
+			flags := parent.Cobra.PersistentFlags()
+			flags.BoolP(longName, shortName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+			viper.SetDefault(key, defaultValue)
+		}
+
+		return nil
+	},
+}
+
+// actionCmd returns the command running action on the migration of an
+// instance.
+func actionCmd(action tcc.MigrationAction, short string) *command.Command {
+	return &command.Command{
+		Cobra: &cobra.Command{
+			Args:         cobra.NoArgs,
+			Use:          string(action),
+			Short:        short,
+			SilenceUsage: true,
+			PreRunE: func(cmd *cobra.Command, args []string) error {
+				if cfg.GetMachineID() == "" && cfg.GetMachineName() == "" {
+					return errors.New("Either `id` or `name` must be specified")
+				}
+
+				if cfg.GetMachineID() != "" && cfg.GetMachineName() != "" {
+					return errors.New("Only 1 of `id` or `name` must be specified")
+				}
+
+				return nil
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cons := conswriter.GetTerminal()
+
+				c, err := cfg.NewTritonConfig()
+				if err != nil {
+					return err
+				}
+
+				a, err := compute.NewComputeClient(c)
+				if err != nil {
+					return err
+				}
+
+				instance, migration, err := a.MigrateInstance(action)
+				if err != nil {
+					return err
+				}
+
+				cons.Write([]byte(fmt.Sprintf("Migration of instance %q: %s %s\n", instance.Name, migration.Phase, migration.State)))
+
+				if !cfg.GetMigrationWatch() {
+					return nil
+				}
+
+				return a.WatchMigration(func(progress *tcc.MigrationProgress) {
+					cons.Write([]byte(a.FormatMigrationProgress(progress) + "\n"))
+				})
+			},
+		},
+		Setup: func(parent *command.Command) error {
+			return nil
+		},
+	}
+}
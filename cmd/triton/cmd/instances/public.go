@@ -11,12 +11,15 @@ package instances
 import (
 	"github.com/joyent/triton-go/v2/cmd/internal/command"
 	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/audit"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/count"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/create"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/delete"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/disk"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/get"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/ip"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/list"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/migration"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/reboot"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/start"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances/stop"
@@ -44,6 +47,9 @@ var Cmd = &command.Command{
 			start.Cmd,
 			stop.Cmd,
 			ip.Cmd,
+			migration.Cmd,
+			audit.Cmd,
+			disk.Cmd,
 		}
 
 		for _, cmd := range cmds {
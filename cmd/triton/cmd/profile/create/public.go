@@ -0,0 +1,80 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package create
+
+import (
+	"errors"
+	"fmt"
+
+	triton "github.com/joyent/triton-go/v2"
+	cfg "github.com/joyent/triton-go/v2/cmd/config"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/internal/config"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Use:          "create NAME",
+		Aliases:      []string{"add"},
+		Short:        "create a Triton CLI profile",
+		Long:         "Create a Triton CLI profile from the --url, --account and --key-id flags. Settings which are not given are taken from the selected profile or the environment.",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := triton.GetProfile(args[0]); err == nil {
+				return fmt.Errorf("profile %q already exists, use `triton profile edit` to change it", args[0])
+			}
+
+			if cfg.GetTritonURL() == "" || cfg.GetTritonAccount() == "" || cfg.GetTritonKeyID() == "" {
+				return errors.New("`url`, `account` and `key-id` must be specified")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			profile := &triton.Profile{
+				Name:     args[0],
+				URL:      cfg.GetTritonURL(),
+				Account:  cfg.GetTritonAccount(),
+				KeyID:    cfg.GetTritonKeyID(),
+				Insecure: cfg.GetProfileInsecure(),
+				User:     cfg.GetTritonUser(),
+			}
+			if err := triton.SaveProfile(profile); err != nil {
+				return err
+			}
+
+			cons.Write([]byte(fmt.Sprintf("Created profile %q", profile.Name)))
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+
+		{
+			const (
+				key          = config.KeyProfileInsecure
+				longName     = "insecure"
+				defaultValue = false
+				description  = "Do not verify the TLS certificate of CloudAPI"
+			)
+
+			flags := parent.Cobra.Flags()
+			flags.Bool(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
+		return nil
+	},
+}
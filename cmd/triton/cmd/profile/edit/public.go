@@ -0,0 +1,73 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package edit
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	triton "github.com/joyent/triton-go/v2"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.ExactArgs(1),
+		Use:          "edit NAME",
+		Short:        "edit a Triton CLI profile in $EDITOR",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			_, err := triton.GetProfile(args[0])
+			return err
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			dir, err := triton.ConfigDir()
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(dir, "profiles.d", args[0]+".json")
+
+			editor := os.Getenv("VISUAL")
+			if editor == "" {
+				editor = os.Getenv("EDITOR")
+			}
+			if editor == "" {
+				editor = "vi"
+			}
+
+			// Editors are commonly configured with arguments, e.g. "code -w".
+			editorArgs := append(strings.Fields(editor), path)
+			edit := exec.Command(editorArgs[0], editorArgs[1:]...)
+			edit.Stdin = os.Stdin
+			edit.Stdout = os.Stdout
+			edit.Stderr = os.Stderr
+			if err := edit.Run(); err != nil {
+				return fmt.Errorf("unable to run %s: %v", editor, err)
+			}
+
+			if _, err := triton.GetProfile(args[0]); err != nil {
+				return err
+			}
+
+			cons.Write([]byte(fmt.Sprintf("Updated profile %q", args[0])))
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		return nil
+	},
+}
@@ -0,0 +1,63 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package get
+
+import (
+	"fmt"
+
+	triton "github.com/joyent/triton-go/v2"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.MaximumNArgs(1),
+		Use:          "get [NAME]",
+		Short:        "get a Triton CLI profile, the current one by default",
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			} else {
+				current, err := triton.CurrentProfileName()
+				if err != nil {
+					return err
+				}
+				name = current
+			}
+
+			profile, err := triton.GetProfile(name)
+			if err != nil {
+				return err
+			}
+
+			cons.Write([]byte(fmt.Sprintf("name: %s\n", profile.Name)))
+			cons.Write([]byte(fmt.Sprintf("url: %s\n", profile.URL)))
+			cons.Write([]byte(fmt.Sprintf("account: %s\n", profile.Account)))
+			cons.Write([]byte(fmt.Sprintf("keyId: %s\n", profile.KeyID)))
+			cons.Write([]byte(fmt.Sprintf("insecure: %t\n", profile.Insecure)))
+			if profile.User != "" {
+				cons.Write([]byte(fmt.Sprintf("user: %s\n", profile.User)))
+			}
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		return nil
+	},
+}
@@ -0,0 +1,71 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package list
+
+import (
+	triton "github.com/joyent/triton-go/v2"
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/olekukonko/tablewriter"
+	"github.com/sean-/conswriter"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Args:         cobra.NoArgs,
+		Use:          "list",
+		Short:        "list Triton CLI profiles",
+		Aliases:      []string{"ls"},
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cons := conswriter.GetTerminal()
+
+			profiles, err := triton.ListProfiles()
+			if err != nil {
+				return err
+			}
+
+			current, err := triton.CurrentProfileName()
+			if err != nil {
+				return err
+			}
+
+			table := tablewriter.NewWriter(cons)
+			table.SetHeaderAlignment(tablewriter.ALIGN_CENTER)
+			table.SetHeaderLine(false)
+			table.SetAutoFormatHeaders(true)
+
+			table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_CENTER, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT, tablewriter.ALIGN_LEFT})
+			table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+			table.SetCenterSeparator("")
+			table.SetColumnSeparator("")
+			table.SetRowSeparator("")
+
+			table.SetHeader([]string{"NAME", "CURR", "ACCOUNT", "USER", "URL"})
+
+			for _, profile := range profiles {
+				var curr string
+				if profile.Name == current {
+					curr = "*"
+				}
+				table.Append([]string{profile.Name, curr, profile.Account, profile.User, profile.URL})
+			}
+
+			table.Render()
+
+			return nil
+		},
+	},
+	Setup: func(parent *command.Command) error {
+		return nil
+	},
+}
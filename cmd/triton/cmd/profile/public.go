@@ -0,0 +1,47 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package profile
+
+import (
+	"github.com/joyent/triton-go/v2/cmd/internal/command"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/profile/create"
+	profileDelete "github.com/joyent/triton-go/v2/cmd/triton/cmd/profile/delete"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/profile/edit"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/profile/get"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/profile/list"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/profile/setcurrent"
+	"github.com/spf13/cobra"
+)
+
+var Cmd = &command.Command{
+	Cobra: &cobra.Command{
+		Use:     "profile",
+		Aliases: []string{"profiles"},
+		Short:   "List and manage Triton CLI profiles.",
+	},
+
+	Setup: func(parent *command.Command) error {
+
+		cmds := []*command.Command{
+			list.Cmd,
+			get.Cmd,
+			create.Cmd,
+			edit.Cmd,
+			profileDelete.Cmd,
+			setcurrent.Cmd,
+		}
+
+		for _, cmd := range cmds {
+			cmd.Setup(cmd)
+			parent.Cobra.AddCommand(cmd.Cobra)
+		}
+
+		return nil
+	},
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/instances"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/keys"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/packages"
+	"github.com/joyent/triton-go/v2/cmd/triton/cmd/profile"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/services"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/shell"
 	"github.com/joyent/triton-go/v2/cmd/triton/cmd/version"
@@ -41,6 +42,7 @@ var subCommands = []*command.Command{
 	packages.Cmd,
 	keys.Cmd,
 	accesskeys.Cmd,
+	profile.Cmd,
 }
 
 var rootCmd = &command.Command{
@@ -130,6 +132,19 @@ var rootCmd = &command.Command{
 			viper.SetDefault(key, defaultValue)
 		}
 
+		{
+			const (
+				key          = config.KeyProfile
+				longName     = "profile"
+				defaultValue = ""
+				description  = "Name of the profile (see 'triton profile list') to take the URL, account and key from. If not specified, the environment variable TRITON_PROFILE or the current profile will be used"
+			)
+
+			flags := parent.Cobra.PersistentFlags()
+			flags.String(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
 		{
 			const (
 				key          = config.KeyTritonAccount
@@ -144,6 +159,19 @@ var rootCmd = &command.Command{
 			viper.BindPFlag(key, flags.Lookup(longName))
 		}
 
+		{
+			const (
+				key          = config.KeyTritonUser
+				longName     = "user"
+				defaultValue = ""
+				description  = "Name of the RBAC sub-user of the account to authenticate as. If not specified, the environment variable TRITON_USER or SDC_USER will be used"
+			)
+
+			flags := parent.Cobra.PersistentFlags()
+			flags.String(longName, defaultValue, description)
+			viper.BindPFlag(key, flags.Lookup(longName))
+		}
+
 		{
 			const (
 				key          = config.KeyTritonURL
@@ -177,7 +205,7 @@ var rootCmd = &command.Command{
 				key          = config.KeyTritonSSHKeyMaterial
 				longName     = "key-material"
 				defaultValue = ""
-				description  = "This is the private key of an SSH key associated with the Triton account to be used. If this is not set, the private key corresponding to the fingerprint in key_id must be available via an SSH Agent. It can be provided via the SDC_KEY_MATERIAL or TRITON_KEY_MATERIAL environment variables."
+				description  = "This is the private key of an SSH key associated with the Triton account to be used. If this is not set, the private key corresponding to the fingerprint in key_id must be available via an SSH Agent. It can be provided via the SDC_KEY_MATERIAL or TRITON_KEY_MATERIAL environment variables. The passphrase of an encrypted key is read from the TRITON_KEY_PASSPHRASE environment variable, or prompted for on a terminal."
 			)
 
 			flags := parent.Cobra.PersistentFlags()
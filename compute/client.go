@@ -28,23 +28,19 @@ func newComputeClient(client *client.Client) *ComputeClient {
 // NewClient returns a new client for working with Compute endpoints and
 // resources within CloudAPI
 func NewClient(config *triton.ClientConfig) (*ComputeClient, error) {
-	// TODO: Utilize config interface within the function itself
-	client, err := client.New(
-		config.TritonURL,
-		config.MantaURL,
-		config.AccountName,
-		config.Signers...,
-	)
+	client, err := client.NewFromConfig(config)
 	if err != nil {
 		return nil, err
 	}
 	return newComputeClient(client), nil
 }
 
-// SetHeaders allows a consumer of the current client to set custom headers for
-// the next backend HTTP request sent to CloudAPI
+// SetHeader sets custom headers on every subsequent request sent to CloudAPI
+// through this client, replacing any set before. It is safe to call while the
+// client is in use. Use client.ContextWithHeader for the headers of a single
+// request.
 func (c *ComputeClient) SetHeader(header *http.Header) {
-	c.Client.RequestHeader = header
+	c.Client.SetHeader(header)
 }
 
 // Datacenters returns a Compute client used for accessing functions pertaining
@@ -53,6 +49,12 @@ func (c *ComputeClient) Datacenters() *DataCentersClient {
 	return &DataCentersClient{c.Client}
 }
 
+// Disks returns a Compute client used for accessing functions pertaining to
+// the Disks of bhyve instances in the Triton API.
+func (c *ComputeClient) Disks() *DisksClient {
+	return &DisksClient{c.Client}
+}
+
 // Images returns a Compute client used for accessing functions pertaining to
 // Images functionality in the Triton API.
 func (c *ComputeClient) Images() *ImagesClient {
@@ -65,6 +67,12 @@ func (c *ComputeClient) Instances() *InstancesClient {
 	return &InstancesClient{c.Client}
 }
 
+// Migrations returns a Compute client used for accessing functions pertaining
+// to instance Migrations functionality in the Triton API.
+func (c *ComputeClient) Migrations() *MigrationsClient {
+	return &MigrationsClient{c.Client}
+}
+
 // Packages returns a Compute client used for accessing functions pertaining to
 // Packages functionality in the Triton API.
 func (c *ComputeClient) Packages() *PackagesClient {
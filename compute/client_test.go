@@ -60,6 +60,30 @@ func TestSetHeader(t *testing.T) {
 			t.Error(err)
 		}
 	})
+
+	t.Run("every request", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+
+		header := &http.Header{}
+		header.Add(testHeaderName, testHeaderVal1)
+		computeClient.SetHeader(header)
+
+		var got []string
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "datacenters"), func(req *http.Request) (*http.Response, error) {
+			got = append(got, req.Header.Get(testHeaderName))
+			return listDataCentersSuccess(req)
+		})
+
+		for i := 0; i < 2; i++ {
+			if _, err := computeClient.Datacenters().List(context.Background(), &compute.ListDataCentersInput{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if len(got) != 2 || got[0] != testHeaderVal1 || got[1] != testHeaderVal1 {
+			t.Errorf("expected header on every request: got %q", got)
+		}
+	})
 }
 
 func overrideHeaderTest(t *testing.T) func(req *http.Request) (*http.Response, error) {
@@ -36,8 +36,9 @@ func (c *DataCentersClient) List(ctx context.Context, _ *ListDataCentersInput) (
 	fullPath := path.Join("/", c.client.AccountName, "datacenters")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.datacenters.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
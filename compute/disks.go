@@ -0,0 +1,385 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package compute
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+
+	"github.com/joyent/triton-go/v2/client"
+	"github.com/pkg/errors"
+)
+
+type DisksClient struct {
+	client *client.Client
+}
+
+const (
+	DiskStateCreating = "creating"
+	DiskStateRunning  = "running"
+	DiskStateResizing = "resizing"
+	DiskStateDeleting = "deleting"
+	DiskStateFailed   = "failed"
+)
+
+// Disk represents a disk of a bhyve instance.
+type Disk struct {
+	ID string `json:"id"`
+
+	// Size is the size of the disk in MiB.
+	Size int64 `json:"size"`
+
+	// Boot reports whether this is the boot disk of the instance.
+	Boot  bool   `json:"boot"`
+	State string `json:"state"`
+
+	// PCISlot is the PCI slot the disk is attached to, e.g. "0:4:1".
+	PCISlot string `json:"pci_slot"`
+}
+
+type ListDisksInput struct {
+	InstanceID string
+}
+
+// List returns the disks of an instance.
+func (c *DisksClient) List(ctx context.Context, input *ListDisksInput) ([]*Disk, error) {
+	if input.InstanceID == "" {
+		return nil, errors.New("unable to list disks: missing instance ID")
+	}
+
+	fullPath := path.Join("/", c.client.AccountName, "machines", input.InstanceID, "disks")
+	reqInputs := client.RequestInput{
+		Operation: "compute.disks.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+	}
+	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
+	if respReader != nil {
+		defer respReader.Close()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list disks")
+	}
+
+	var result []*Disk
+	decoder := json.NewDecoder(respReader)
+	if err = decoder.Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "unable to decode list disks response")
+	}
+
+	return result, nil
+}
+
+type GetDiskInput struct {
+	InstanceID string
+	ID         string
+}
+
+// Get returns a disk of an instance.
+func (c *DisksClient) Get(ctx context.Context, input *GetDiskInput) (*Disk, error) {
+	if input.InstanceID == "" {
+		return nil, errors.New("unable to get disk: missing instance ID")
+	}
+	if input.ID == "" {
+		return nil, errors.New("unable to get disk: missing disk ID")
+	}
+
+	fullPath := path.Join("/", c.client.AccountName, "machines", input.InstanceID, "disks", input.ID)
+	reqInputs := client.RequestInput{
+		Operation: "compute.disks.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+	}
+	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
+	if respReader != nil {
+		defer respReader.Close()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get disk")
+	}
+
+	var result *Disk
+	decoder := json.NewDecoder(respReader)
+	if err = decoder.Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "unable to decode get disk response")
+	}
+
+	return result, nil
+}
+
+type CreateDiskInput struct {
+	InstanceID string
+
+	// Size is the size of the disk in MiB. It is ignored if Remaining is
+	// set.
+	Size int64
+
+	// Remaining sizes the disk to take all the space left in the disk budget
+	// of the instance.
+	Remaining bool
+
+	// PCISlot optionally selects the PCI slot of the disk.
+	PCISlot string
+
+	// CheckBudget checks Size against the disk budget of the instance before
+	// creating the disk. See Budget.
+	CheckBudget bool
+}
+
+func (input *CreateDiskInput) toAPI() map[string]interface{} {
+	result := make(map[string]interface{}, 2)
+
+	if input.Remaining {
+		result["size"] = "remaining"
+	} else {
+		result["size"] = input.Size
+	}
+
+	if input.PCISlot != "" {
+		result["pci_slot"] = input.PCISlot
+	}
+
+	return result
+}
+
+// Create adds a disk to a stopped bhyve instance whose package has flexible
+// disk. The disk is returned in state "creating"; use WaitForState to wait
+// until it is running.
+func (c *DisksClient) Create(ctx context.Context, input *CreateDiskInput) (*Disk, error) {
+	if input.InstanceID == "" {
+		return nil, errors.New("unable to create disk: missing instance ID")
+	}
+	if !input.Remaining && input.Size <= 0 {
+		return nil, errors.New("unable to create disk: size must be positive")
+	}
+	if input.CheckBudget && !input.Remaining {
+		budget, err := c.Budget(ctx, &GetDiskBudgetInput{InstanceID: input.InstanceID})
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create disk")
+		}
+		if err := budget.Check("", input.Size); err != nil {
+			return nil, errors.Wrap(err, "unable to create disk")
+		}
+	}
+
+	fullPath := path.Join("/", c.client.AccountName, "machines", input.InstanceID, "disks")
+	reqInputs := client.RequestInput{
+		Operation: "compute.disks.create",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input.toAPI(),
+	}
+	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
+	if respReader != nil {
+		defer respReader.Close()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create disk")
+	}
+
+	var result *Disk
+	decoder := json.NewDecoder(respReader)
+	if err = decoder.Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "unable to decode create disk response")
+	}
+
+	return result, nil
+}
+
+type ResizeDiskInput struct {
+	InstanceID string
+	ID         string
+
+	// Size is the new size of the disk in MiB.
+	Size int64
+
+	// DangerousAllowShrink must be set to make a disk smaller. Shrinking a
+	// disk truncates it, so any data past the new size is lost.
+	DangerousAllowShrink bool
+
+	// CheckBudget checks Size against the disk budget of the instance, and
+	// that the disk only shrinks if DangerousAllowShrink is set, before
+	// resizing the disk. See Budget.
+	CheckBudget bool
+}
+
+// Resize changes the size of a disk of a stopped bhyve instance. The disk
+// is returned in state "resizing"; use WaitForState to wait until it is
+// running again.
+func (c *DisksClient) Resize(ctx context.Context, input *ResizeDiskInput) (*Disk, error) {
+	if input.InstanceID == "" {
+		return nil, errors.New("unable to resize disk: missing instance ID")
+	}
+	if input.ID == "" {
+		return nil, errors.New("unable to resize disk: missing disk ID")
+	}
+	if input.Size <= 0 {
+		return nil, errors.New("unable to resize disk: size must be positive")
+	}
+	if input.CheckBudget {
+		budget, err := c.Budget(ctx, &GetDiskBudgetInput{InstanceID: input.InstanceID})
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to resize disk")
+		}
+		if disk := budget.disk(input.ID); disk != nil && input.Size < disk.Size && !input.DangerousAllowShrink {
+			return nil, errors.Errorf("unable to resize disk: shrinking disk %s from %d MiB to %d MiB requires DangerousAllowShrink", input.ID, disk.Size, input.Size)
+		}
+		if err := budget.Check(input.ID, input.Size); err != nil {
+			return nil, errors.Wrap(err, "unable to resize disk")
+		}
+	}
+
+	body := map[string]interface{}{
+		"size": input.Size,
+	}
+	if input.DangerousAllowShrink {
+		body["dangerous_allow_shrink"] = true
+	}
+
+	fullPath := path.Join("/", c.client.AccountName, "machines", input.InstanceID, "disks", input.ID)
+	reqInputs := client.RequestInput{
+		Operation: "compute.disks.resize",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      body,
+	}
+	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
+	if respReader != nil {
+		defer respReader.Close()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to resize disk")
+	}
+
+	var result *Disk
+	decoder := json.NewDecoder(respReader)
+	if err = decoder.Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "unable to decode resize disk response")
+	}
+
+	return result, nil
+}
+
+type DeleteDiskInput struct {
+	InstanceID string
+	ID         string
+}
+
+// Delete removes a disk from a stopped bhyve instance. The boot disk cannot
+// be deleted.
+func (c *DisksClient) Delete(ctx context.Context, input *DeleteDiskInput) error {
+	if input.InstanceID == "" {
+		return errors.New("unable to delete disk: missing instance ID")
+	}
+	if input.ID == "" {
+		return errors.New("unable to delete disk: missing disk ID")
+	}
+
+	fullPath := path.Join("/", c.client.AccountName, "machines", input.InstanceID, "disks", input.ID)
+	reqInputs := client.RequestInput{
+		Operation: "compute.disks.delete",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
+	}
+	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
+	if respReader != nil {
+		defer respReader.Close()
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to delete disk")
+	}
+
+	return nil
+}
+
+// DiskBudget is the disk space of an instance whose package has flexible
+// disk. The sizes of all disks of the instance, in MiB, may add up to at
+// most Total.
+type DiskBudget struct {
+	Total int64
+	Used  int64
+	Disks []*Disk
+}
+
+// Remaining returns the MiB left for new or larger disks.
+func (b *DiskBudget) Remaining() int64 {
+	return b.Total - b.Used
+}
+
+// Check returns an error if resizing the disk identified by id to size MiB,
+// or adding a disk of size MiB if id is empty, would exceed the budget.
+func (b *DiskBudget) Check(id string, size int64) error {
+	used := b.Used + size
+	if id != "" {
+		disk := b.disk(id)
+		if disk == nil {
+			return errors.Errorf("disk %s not found", id)
+		}
+		used -= disk.Size
+	}
+
+	if used > b.Total {
+		return errors.Errorf("%d MiB of disks exceed the %d MiB flexible disk budget by %d MiB", used, b.Total, used-b.Total)
+	}
+
+	return nil
+}
+
+func (b *DiskBudget) disk(id string) *Disk {
+	for _, disk := range b.Disks {
+		if disk.ID == id {
+			return disk
+		}
+	}
+	return nil
+}
+
+type GetDiskBudgetInput struct {
+	InstanceID string
+}
+
+// Budget returns the disk budget of an instance, derived from the disk size
+// of its package and the sizes of its disks. It fails if the package of the
+// instance does not have flexible disk, as the disks of such instances
+// cannot be added or resized.
+func (c *DisksClient) Budget(ctx context.Context, input *GetDiskBudgetInput) (*DiskBudget, error) {
+	if input.InstanceID == "" {
+		return nil, errors.New("unable to get disk budget: missing instance ID")
+	}
+
+	instance, err := (&InstancesClient{c.client}).Get(ctx, &GetInstanceInput{ID: input.InstanceID})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get disk budget")
+	}
+
+	pkg, err := (&PackagesClient{c.client}).Get(ctx, &GetPackageInput{ID: instance.Package})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get disk budget")
+	}
+	if !pkg.FlexibleDisk {
+		return nil, errors.Errorf("unable to get disk budget: package %s does not have flexible disk", pkg.Name)
+	}
+
+	disks, err := c.List(ctx, &ListDisksInput{InstanceID: input.InstanceID})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get disk budget")
+	}
+
+	budget := &DiskBudget{
+		Total: pkg.Disk,
+		Disks: disks,
+	}
+	for _, disk := range disks {
+		budget.Used += disk.Size
+	}
+
+	return budget, nil
+}
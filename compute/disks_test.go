@@ -0,0 +1,282 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package compute_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/joyent/triton-go/v2/compute"
+	"github.com/joyent/triton-go/v2/testutils"
+)
+
+const fakeDiskID = "0c7f2c93-6d2a-4c4c-9f39-7a4dd3ec1e1e"
+
+func TestListDisks(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient) ([]*compute.Disk, error) {
+		defer testutils.DeactivateClient()
+
+		return cc.Disks().List(ctx, &compute.ListDisksInput{
+			InstanceID: fakeMachineID,
+		})
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID, "disks"), listDisksSuccess)
+
+		resp, err := do(context.Background(), computeClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp) != 2 || !resp[0].Boot || resp[1].Size != 20480 || resp[1].PCISlot != "0:4:1" {
+			t.Errorf("unexpected disks: %+v", resp)
+		}
+	})
+
+	t.Run("missing instance ID", func(t *testing.T) {
+		_, err := computeClient.Disks().List(context.Background(), &compute.ListDisksInput{})
+		if err == nil || !strings.Contains(err.Error(), "missing instance ID") {
+			t.Errorf("expected a missing instance ID error: found %v", err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID, "disks"), diskError)
+
+		_, err := do(context.Background(), computeClient)
+		if err == nil || !strings.Contains(err.Error(), "unable to list disks") {
+			t.Errorf("expected error to contain list disks: found %v", err)
+		}
+	})
+}
+
+func TestCreateDisk(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient, input *compute.CreateDiskInput) (*compute.Disk, map[string]interface{}, error) {
+		defer testutils.DeactivateClient()
+
+		var body map[string]interface{}
+		testutils.RegisterResponder("POST", path.Join("/", accountURL, "machines", fakeMachineID, "disks"), func(req *http.Request) (*http.Response, error) {
+			json.NewDecoder(req.Body).Decode(&body)
+			return diskResponse(`{"id": "` + fakeDiskID + `", "size": 10240, "state": "creating"}`), nil
+		})
+		registerDiskBudget()
+
+		input.InstanceID = fakeMachineID
+		disk, err := cc.Disks().Create(ctx, input)
+		return disk, body, err
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		disk, body, err := do(context.Background(), computeClient, &compute.CreateDiskInput{
+			Size:        10240,
+			CheckBudget: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if disk.State != compute.DiskStateCreating || body["size"] != float64(10240) {
+			t.Errorf("unexpected disk %+v created with %v", disk, body)
+		}
+	})
+
+	t.Run("remaining", func(t *testing.T) {
+		_, body, err := do(context.Background(), computeClient, &compute.CreateDiskInput{
+			Remaining: true,
+			PCISlot:   "0:4:2",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if body["size"] != "remaining" || body["pci_slot"] != "0:4:2" {
+			t.Errorf("unexpected request: %v", body)
+		}
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		_, body, err := do(context.Background(), computeClient, &compute.CreateDiskInput{
+			Size:        40960,
+			CheckBudget: true,
+		})
+		if err == nil || !strings.Contains(err.Error(), "exceed the 61440 MiB flexible disk budget by 10240 MiB") {
+			t.Errorf("expected budget error: found %v", err)
+		}
+		if body != nil {
+			t.Errorf("expected no disk to be created")
+		}
+	})
+}
+
+func TestResizeDisk(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient, input *compute.ResizeDiskInput) (map[string]interface{}, error) {
+		defer testutils.DeactivateClient()
+
+		var body map[string]interface{}
+		testutils.RegisterResponder("POST", path.Join("/", accountURL, "machines", fakeMachineID, "disks", fakeDiskID), func(req *http.Request) (*http.Response, error) {
+			json.NewDecoder(req.Body).Decode(&body)
+			return diskResponse(`{"id": "` + fakeDiskID + `", "size": 20480, "state": "resizing"}`), nil
+		})
+		registerDiskBudget()
+
+		input.InstanceID = fakeMachineID
+		input.ID = fakeDiskID
+		_, err := cc.Disks().Resize(ctx, input)
+		return body, err
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		body, err := do(context.Background(), computeClient, &compute.ResizeDiskInput{
+			Size:        40960,
+			CheckBudget: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if body["size"] != float64(40960) || body["dangerous_allow_shrink"] != nil {
+			t.Errorf("unexpected request: %v", body)
+		}
+	})
+
+	t.Run("shrink", func(t *testing.T) {
+		body, err := do(context.Background(), computeClient, &compute.ResizeDiskInput{
+			Size:                 10240,
+			DangerousAllowShrink: true,
+			CheckBudget:          true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if body["dangerous_allow_shrink"] != true {
+			t.Errorf("expected shrinking to be allowed: %v", body)
+		}
+	})
+
+	t.Run("shrink not allowed", func(t *testing.T) {
+		_, err := do(context.Background(), computeClient, &compute.ResizeDiskInput{
+			Size:        10240,
+			CheckBudget: true,
+		})
+		if err == nil || !strings.Contains(err.Error(), "requires DangerousAllowShrink") {
+			t.Errorf("expected shrink error: found %v", err)
+		}
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		_, err := do(context.Background(), computeClient, &compute.ResizeDiskInput{
+			Size:        61440,
+			CheckBudget: true,
+		})
+		if err == nil || !strings.Contains(err.Error(), "flexible disk budget") {
+			t.Errorf("expected budget error: found %v", err)
+		}
+	})
+}
+
+func TestDeleteDisk(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient) error {
+		defer testutils.DeactivateClient()
+
+		return cc.Disks().Delete(ctx, &compute.DeleteDiskInput{
+			InstanceID: fakeMachineID,
+			ID:         fakeDiskID,
+		})
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		testutils.RegisterResponder("DELETE", path.Join("/", accountURL, "machines", fakeMachineID, "disks", fakeDiskID), func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusNoContent, Header: http.Header{}}, nil
+		})
+
+		if err := do(context.Background(), computeClient); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("missing ID", func(t *testing.T) {
+		err := computeClient.Disks().Delete(context.Background(), &compute.DeleteDiskInput{
+			InstanceID: fakeMachineID,
+		})
+		if err == nil || !strings.Contains(err.Error(), "missing disk ID") {
+			t.Errorf("expected a missing disk ID error: found %v", err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		testutils.RegisterResponder("DELETE", path.Join("/", accountURL, "machines", fakeMachineID, "disks", fakeDiskID), diskError)
+
+		err := do(context.Background(), computeClient)
+		if err == nil || !strings.Contains(err.Error(), "unable to delete disk") {
+			t.Errorf("expected error to contain delete disk: found %v", err)
+		}
+	})
+}
+
+// registerDiskBudget registers an instance of a 60 GiB flexible disk package
+// with 30 GiB of disks.
+func registerDiskBudget() {
+	testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID), func(req *http.Request) (*http.Response, error) {
+		return diskResponse(`{"id": "` + fakeMachineID + `", "brand": "bhyve", "package": "flex-60G"}`), nil
+	})
+	testutils.RegisterResponder("GET", path.Join("/", accountURL, "packages", "flex-60G"), func(req *http.Request) (*http.Response, error) {
+		return diskResponse(`{"name": "flex-60G", "disk": 61440, "flexible_disk": true}`), nil
+	})
+	testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID, "disks"), listDisksSuccess)
+}
+
+func listDisksSuccess(req *http.Request) (*http.Response, error) {
+	return diskResponse(`[
+  {
+    "id": "4f5ca3e4-0b5c-4d3a-bc8a-9d9a3a4b7f21",
+    "pci_slot": "0:4:0",
+    "size": 10240,
+    "boot": true,
+    "state": "running"
+  },
+  {
+    "id": "` + fakeDiskID + `",
+    "pci_slot": "0:4:1",
+    "size": 20480,
+    "boot": false,
+    "state": "running"
+  }
+]`), nil
+}
+
+func diskResponse(body string) *http.Response {
+	header := http.Header{}
+	header.Add("Content-Type", "application/json")
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func diskError(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("unable to manage disks")
+}
@@ -11,6 +11,7 @@ package compute
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
@@ -59,9 +60,7 @@ type ListImagesInput struct {
 	Type    string
 }
 
-func (c *ImagesClient) List(ctx context.Context, input *ListImagesInput) ([]*Image, error) {
-	fullPath := path.Join("/", c.client.AccountName, "images")
-
+func buildImagesQuery(input *ListImagesInput) *url.Values {
 	query := &url.Values{}
 	if input.Name != "" {
 		query.Set("name", input.Name)
@@ -85,10 +84,17 @@ func (c *ImagesClient) List(ctx context.Context, input *ListImagesInput) ([]*Ima
 		query.Set("type", input.Type)
 	}
 
+	return query
+}
+
+func (c *ImagesClient) List(ctx context.Context, input *ListImagesInput) ([]*Image, error) {
+	fullPath := path.Join("/", c.client.AccountName, "images")
+
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
-		Query:  query,
+		Operation: "compute.images.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+		Query:     buildImagesQuery(input),
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -107,6 +113,122 @@ func (c *ImagesClient) List(ctx context.Context, input *ListImagesInput) ([]*Ima
 	return result, nil
 }
 
+// ImageIterator walks the images matching a ListImagesInput. Use Next to
+// advance it, Image to read the current image and Err to check for an error
+// once Next returns false.
+type ImageIterator struct {
+	ctx    context.Context
+	client *ImagesClient
+	query  *url.Values
+
+	body    io.ReadCloser
+	decoder *json.Decoder
+	current *Image
+	done    bool
+	err     error
+}
+
+// ListAll returns an iterator over the images matching input. CloudAPI
+// returns every matching image in a single response, which the iterator
+// decodes one image at a time rather than loading it into memory at once.
+// Close must be called if the iterator is abandoned before Next returns
+// false.
+func (c *ImagesClient) ListAll(ctx context.Context, input *ListImagesInput) *ImageIterator {
+	return &ImageIterator{
+		ctx:    ctx,
+		client: c,
+		query:  buildImagesQuery(input),
+	}
+}
+
+// Next advances the iterator to the next image. It returns false when there
+// are no more images or an error occurred.
+func (it *ImageIterator) Next() bool {
+	it.current = nil
+	if it.done {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		return it.stop(err)
+	}
+
+	if it.decoder == nil {
+		if err := it.open(); err != nil {
+			return it.stop(err)
+		}
+	}
+
+	if !it.decoder.More() {
+		return it.stop(nil)
+	}
+
+	image := &Image{}
+	if err := it.decoder.Decode(image); err != nil {
+		return it.stop(errors.Wrap(err, "unable to decode list images response"))
+	}
+	it.current = image
+
+	return true
+}
+
+func (it *ImageIterator) open() error {
+	fullPath := path.Join("/", it.client.client.AccountName, "images")
+
+	reqInputs := client.RequestInput{
+		Operation: "compute.images.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+		Query:     it.query,
+	}
+	respReader, err := it.client.client.ExecuteRequestURIParams(it.ctx, reqInputs)
+	if respReader != nil {
+		it.body = respReader
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to list images")
+	}
+
+	it.decoder = json.NewDecoder(respReader)
+	token, err := it.decoder.Token()
+	if err != nil {
+		return errors.Wrap(err, "unable to decode list images response")
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return errors.New("unable to decode list images response: expected an array")
+	}
+
+	return nil
+}
+
+func (it *ImageIterator) stop(err error) bool {
+	it.err = err
+	it.Close()
+	return false
+}
+
+// Image returns the image the iterator currently points at.
+func (it *ImageIterator) Image() *Image {
+	return it.current
+}
+
+// Err returns the error which stopped the iterator, if any.
+func (it *ImageIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator and releases the underlying response body.
+func (it *ImageIterator) Close() error {
+	it.done = true
+	if it.body == nil {
+		return nil
+	}
+
+	body := it.body
+	it.body = nil
+	return body.Close()
+}
+
 type GetImageInput struct {
 	ImageID string
 }
@@ -114,8 +236,9 @@ type GetImageInput struct {
 func (c *ImagesClient) Get(ctx context.Context, input *GetImageInput) (*Image, error) {
 	fullPath := path.Join("/", c.client.AccountName, "images", input.ImageID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.images.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -141,8 +264,9 @@ type DeleteImageInput struct {
 func (c *ImagesClient) Delete(ctx context.Context, input *DeleteImageInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "images", input.ImageID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "compute.images.delete",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -172,9 +296,10 @@ func (c *ImagesClient) Export(ctx context.Context, input *ExportImageInput) (*Ma
 	query.Set("action", "export")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  query,
+		Operation: "compute.images.export",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     query,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -207,9 +332,10 @@ type CreateImageFromMachineInput struct {
 func (c *ImagesClient) CreateFromMachine(ctx context.Context, input *CreateImageFromMachineInput) (*Image, error) {
 	fullPath := path.Join("/", c.client.AccountName, "images")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "compute.images.create_from_machine",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -245,10 +371,11 @@ func (c *ImagesClient) Update(ctx context.Context, input *UpdateImageInput) (*Im
 	query.Set("action", "update")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  query,
-		Body:   input,
+		Operation: "compute.images.update",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     query,
+		Body:      input,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
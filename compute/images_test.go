@@ -353,6 +353,75 @@ func TestListImages(t *testing.T) {
 	})
 }
 
+func TestListAllImages(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient) ([]*compute.Image, error) {
+		defer testutils.DeactivateClient()
+
+		iter := cc.Images().ListAll(ctx, &compute.ListImagesInput{})
+		defer iter.Close()
+
+		var images []*compute.Image
+		for iter.Next() {
+			images = append(images, iter.Image())
+		}
+		return images, iter.Err()
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "images"), listImagesSuccess)
+
+		resp, err := do(context.Background(), computeClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp) != 1 || resp[0].Name != "base" {
+			t.Errorf("expected to iterate over the base image: got %v", resp)
+		}
+	})
+
+	t.Run("eof", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "images"), listImagesEmpty)
+
+		_, err := do(context.Background(), computeClient)
+		if err == nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(err.Error(), "EOF") {
+			t.Errorf("expected error to contain EOF: found %s", err)
+		}
+	})
+
+	t.Run("bad_decode", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "images"), listImagesBadDecode)
+
+		_, err := do(context.Background(), computeClient)
+		if err == nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(err.Error(), "unable to decode list images response") {
+			t.Errorf("expected decode to fail: found %s", err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "images"), listImagesError)
+
+		_, err := do(context.Background(), computeClient)
+		if err == nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(err.Error(), "unable to list images") {
+			t.Errorf("expected error to equal testError: found %v", err)
+		}
+	})
+}
+
 func TestCreateImageFromMachine(t *testing.T) {
 	computeClient := MockComputeClient()
 
@@ -53,6 +53,28 @@ type InstanceVolume struct {
 	Mountpoint string `json:"mountpoint,omitempty"`
 }
 
+// InstanceDisk is a disk to provision with a bhyve instance whose package has
+// flexible disk. The first disk of an instance is its boot disk.
+type InstanceDisk struct {
+	// Size is the size of the disk in MiB. It may be left zero on the boot
+	// disk to size it after the image.
+	Size int64
+
+	// Remaining sizes the disk to take all the space left in the disk of the
+	// package. Only one disk may set it.
+	Remaining bool
+}
+
+func (d InstanceDisk) MarshalJSON() ([]byte, error) {
+	result := make(map[string]interface{}, 1)
+	if d.Remaining {
+		result["size"] = "remaining"
+	} else if d.Size > 0 {
+		result["size"] = d.Size
+	}
+	return json.Marshal(result)
+}
+
 type NetworkObject struct {
 	IPv4UUID string   `json:"ipv4_uuid"`
 	IPv4IPs  []string `json:"ipv4_ips,omitempty"`
@@ -117,9 +139,10 @@ func (c *InstancesClient) Count(ctx context.Context, input *ListInstancesInput)
 	fullPath := path.Join("/", c.client.AccountName, "machines")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodHead,
-		Path:   fullPath,
-		Query:  buildQueryFilter(input),
+		Operation: "compute.instances.count",
+		Method:    http.MethodHead,
+		Path:      fullPath,
+		Query:     buildQueryFilter(input),
 	}
 
 	response, err := c.client.ExecuteRequestRaw(ctx, reqInputs)
@@ -152,6 +175,7 @@ func (c *InstancesClient) Get(ctx context.Context, input *GetInstanceInput) (*In
 
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID)
 	reqInputs := client.RequestInput{
+		Operation:    "compute.instances.get",
 		Method:       http.MethodGet,
 		Path:         fullPath,
 		PreserveGone: true,
@@ -244,12 +268,17 @@ func buildQueryFilter(input *ListInstancesInput) *url.Values {
 }
 
 func (c *InstancesClient) List(ctx context.Context, input *ListInstancesInput) ([]*Instance, error) {
+	return c.list(ctx, buildQueryFilter(input))
+}
+
+func (c *InstancesClient) list(ctx context.Context, query *url.Values) ([]*Instance, error) {
 	fullPath := path.Join("/", c.client.AccountName, "machines")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
-		Query:  buildQueryFilter(input),
+		Operation: "compute.instances.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+		Query:     query,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -277,6 +306,110 @@ func (c *InstancesClient) List(ctx context.Context, input *ListInstancesInput) (
 	return machines, nil
 }
 
+// maxInstancesPageSize is the largest number of instances CloudAPI returns
+// for a single ListMachines request.
+const maxInstancesPageSize = 1000
+
+// InstanceIterator pages lazily through the instances matching a
+// ListInstancesInput. Use Next to advance it, Instance to read the current
+// instance and Err to check for an error once Next returns false. Close
+// stops it early.
+type InstanceIterator struct {
+	ctx      context.Context
+	client   *InstancesClient
+	query    *url.Values
+	offset   int
+	pageSize int
+
+	page    []*Instance
+	current *Instance
+	done    bool
+	err     error
+}
+
+// ListAll returns an iterator over every instance matching input. Pages of
+// input.Limit instances (1000 if unset) are requested on demand, starting at
+// input.Offset, until CloudAPI returns a short page, ctx is cancelled or a
+// request fails.
+//
+// Since pages are requested by offset, instances created or deleted while
+// iterating may be skipped or returned twice.
+func (c *InstancesClient) ListAll(ctx context.Context, input *ListInstancesInput) *InstanceIterator {
+	pageSize := maxInstancesPageSize
+	if input.Limit >= 1 && input.Limit <= maxInstancesPageSize {
+		pageSize = int(input.Limit)
+	}
+
+	return &InstanceIterator{
+		ctx:      ctx,
+		client:   c,
+		query:    buildQueryFilter(input),
+		offset:   int(input.Offset),
+		pageSize: pageSize,
+	}
+}
+
+// Next advances the iterator to the next instance, fetching the next page
+// when the current one is exhausted. It returns false when there are no more
+// instances or an error occurred.
+func (it *InstanceIterator) Next() bool {
+	for it.err == nil {
+		if len(it.page) > 0 {
+			it.current, it.page = it.page[0], it.page[1:]
+			return true
+		}
+
+		if it.done {
+			break
+		}
+
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			break
+		}
+
+		it.fetch()
+	}
+
+	it.current = nil
+	return false
+}
+
+func (it *InstanceIterator) fetch() {
+	it.query.Set("limit", strconv.Itoa(it.pageSize))
+	it.query.Set("offset", strconv.Itoa(it.offset))
+
+	page, err := it.client.list(it.ctx, it.query)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	it.page = page
+	it.offset += len(page)
+	it.done = len(page) < it.pageSize
+}
+
+// Instance returns the instance the iterator currently points at.
+func (it *InstanceIterator) Instance() *Instance {
+	return it.current
+}
+
+// Err returns the error which stopped the iterator, if any.
+func (it *InstanceIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator and discards the rest of the current page. Pages
+// are read in full, so it never fails, but it may be deferred like Close on
+// ImageIterator and DirectoryIterator.
+func (it *InstanceIterator) Close() error {
+	it.done = true
+	it.page = nil
+	it.current = nil
+	return nil
+}
+
 type CreateInstanceInput struct {
 	Name            string
 	NamePrefix      string
@@ -294,6 +427,18 @@ type CreateInstanceInput struct {
 	DelegateDataset bool
 	CNS             InstanceCNS
 	Volumes         []InstanceVolume
+
+	// Disks are the disks of a bhyve instance whose package has flexible
+	// disk. They are checked against the package before the instance is
+	// created.
+	Disks []InstanceDisk
+
+	DeletionProtection bool
+	Encrypted          bool
+
+	// AllowSharedImages allows the instance to be created from an image
+	// shared with, rather than owned by, the account.
+	AllowSharedImages bool
 }
 
 func buildInstanceName(namePrefix string) string {
@@ -303,12 +448,24 @@ func buildInstanceName(namePrefix string) string {
 }
 
 func (input *CreateInstanceInput) toAPI() (map[string]interface{}, error) {
-	const numExtraParams = 8
+	const numExtraParams = 12
 	result := make(map[string]interface{}, numExtraParams+len(input.Metadata)+len(input.Tags))
 
 	result["firewall_enabled"] = input.FirewallEnabled
 	result["delegate_dataset"] = input.DelegateDataset
 
+	if input.DeletionProtection {
+		result["deletion_protection"] = true
+	}
+
+	if input.Encrypted {
+		result["encrypted"] = true
+	}
+
+	if input.AllowSharedImages {
+		result["allow_shared_images"] = true
+	}
+
 	if input.Name != "" {
 		result["name"] = input.Name
 	} else if input.NamePrefix != "" {
@@ -354,6 +511,10 @@ func (input *CreateInstanceInput) toAPI() (map[string]interface{}, error) {
 		result["volumes"] = input.Volumes
 	}
 
+	if len(input.Disks) > 0 {
+		result["disks"] = input.Disks
+	}
+
 	// validate that affinity and locality are not included together
 	hasAffinity := len(input.Affinity) > 0
 	hasLocality := len(input.LocalityNear) > 0 || len(input.LocalityFar) > 0
@@ -396,6 +557,44 @@ func (input *CreateInstanceInput) toAPI() (map[string]interface{}, error) {
 	return result, nil
 }
 
+// checkPackage validates the options of input depending on the package of the
+// instance. Disks can only be set for bhyve packages with flexible disk, and
+// must fit in the disk of the package.
+func (input *CreateInstanceInput) checkPackage(pkg *Package) error {
+	if len(input.Disks) == 0 {
+		return nil
+	}
+
+	if !pkg.FlexibleDisk {
+		return fmt.Errorf("Cannot include Disks: package %s does not have flexible disk", pkg.Name)
+	}
+	if pkg.Brand != "" && pkg.Brand != "bhyve" {
+		return fmt.Errorf("Cannot include Disks: package %s is not a bhyve package", pkg.Name)
+	}
+
+	var size int64
+	remaining := 0
+	for i, disk := range input.Disks {
+		if disk.Remaining {
+			remaining++
+			continue
+		}
+		if disk.Size < 0 || (disk.Size == 0 && i > 0) {
+			return fmt.Errorf("Cannot include disk %d: size must be positive", i)
+		}
+		size += disk.Size
+	}
+
+	if remaining > 1 {
+		return fmt.Errorf("Cannot include more than one disk using the remaining space")
+	}
+	if size > pkg.Disk {
+		return fmt.Errorf("%d MiB of disks exceed the %d MiB flexible disk of package %s by %d MiB", size, pkg.Disk, pkg.Name, size-pkg.Disk)
+	}
+
+	return nil
+}
+
 func (c *InstancesClient) Create(ctx context.Context, input *CreateInstanceInput) (*Instance, error) {
 	fullPath := path.Join("/", c.client.AccountName, "machines")
 	body, err := input.toAPI()
@@ -403,10 +602,21 @@ func (c *InstancesClient) Create(ctx context.Context, input *CreateInstanceInput
 		return nil, pkgerrors.Wrap(err, "unable to prepare for machine creation")
 	}
 
+	if len(input.Disks) > 0 {
+		pkg, err := (&PackagesClient{c.client}).Get(ctx, &GetPackageInput{ID: input.Package})
+		if err != nil {
+			return nil, pkgerrors.Wrap(err, "unable to prepare for machine creation")
+		}
+		if err := input.checkPackage(pkg); err != nil {
+			return nil, pkgerrors.Wrap(err, "unable to prepare for machine creation")
+		}
+	}
+
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   body,
+		Operation: "compute.instances.create",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      body,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -432,8 +642,9 @@ type DeleteInstanceInput struct {
 func (c *InstancesClient) Delete(ctx context.Context, input *DeleteInstanceInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "compute.instances.delete",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	response, err := c.client.ExecuteRequestRaw(ctx, reqInputs)
 	if response == nil {
@@ -459,8 +670,9 @@ type DeleteTagsInput struct {
 func (c *InstancesClient) DeleteTags(ctx context.Context, input *DeleteTagsInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "tags")
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "compute.instances.delete_tags",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	response, err := c.client.ExecuteRequestRaw(ctx, reqInputs)
 	if err != nil {
@@ -487,8 +699,9 @@ type DeleteTagInput struct {
 func (c *InstancesClient) DeleteTag(ctx context.Context, input *DeleteTagInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "tags", input.Key)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "compute.instances.delete_tag",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	response, err := c.client.ExecuteRequestRaw(ctx, reqInputs)
 	if err != nil {
@@ -520,9 +733,10 @@ func (c *InstancesClient) Rename(ctx context.Context, input *RenameInstanceInput
 	params.Set("name", input.Name)
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  params,
+		Operation: "compute.instances.rename",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     params,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -555,9 +769,10 @@ func (input ReplaceTagsInput) toAPI() map[string]interface{} {
 func (c *InstancesClient) ReplaceTags(ctx context.Context, input *ReplaceTagsInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "tags")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPut,
-		Path:   fullPath,
-		Body:   input.toAPI(),
+		Operation: "compute.instances.replace_tags",
+		Method:    http.MethodPut,
+		Path:      fullPath,
+		Body:      input.toAPI(),
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -578,9 +793,10 @@ type AddTagsInput struct {
 func (c *InstancesClient) AddTags(ctx context.Context, input *AddTagsInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "tags")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input.Tags,
+		Operation: "compute.instances.add_tags",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input.Tags,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -601,8 +817,9 @@ type GetTagInput struct {
 func (c *InstancesClient) GetTag(ctx context.Context, input *GetTagInput) (string, error) {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "tags", input.Key)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.instances.get_tag",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
@@ -628,8 +845,9 @@ type ListTagsInput struct {
 func (c *InstancesClient) ListTags(ctx context.Context, input *ListTagsInput) (map[string]interface{}, error) {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "tags")
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.instances.list_tags",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -662,8 +880,9 @@ func (c *InstancesClient) GetMetadata(ctx context.Context, input *GetMetadataInp
 
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "metadata", input.Key)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.instances.get_metadata",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -696,9 +915,10 @@ func (c *InstancesClient) ListMetadata(ctx context.Context, input *ListMetadataI
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
-		Query:  query,
+		Operation: "compute.instances.list_metadata",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+		Query:     query,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -725,9 +945,10 @@ type UpdateMetadataInput struct {
 func (c *InstancesClient) UpdateMetadata(ctx context.Context, input *UpdateMetadataInput) (map[string]interface{}, error) {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "metadata")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input.Metadata,
+		Operation: "compute.instances.update_metadata",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input.Metadata,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -759,8 +980,9 @@ func (c *InstancesClient) DeleteMetadata(ctx context.Context, input *DeleteMetad
 
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "metadata", input.Key)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "compute.instances.delete_metadata",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
@@ -781,8 +1003,9 @@ type DeleteAllMetadataInput struct {
 func (c *InstancesClient) DeleteAllMetadata(ctx context.Context, input *DeleteAllMetadataInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "metadata")
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "compute.instances.delete_all_metadata",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
@@ -808,9 +1031,10 @@ func (c *InstancesClient) Resize(ctx context.Context, input *ResizeInstanceInput
 	params.Set("package", input.Package)
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  params,
+		Operation: "compute.instances.resize",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     params,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -834,9 +1058,10 @@ func (c *InstancesClient) EnableFirewall(ctx context.Context, input *EnableFirew
 	params.Set("action", "enable_firewall")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  params,
+		Operation: "compute.instances.enable_firewall",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     params,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -860,9 +1085,10 @@ func (c *InstancesClient) DisableFirewall(ctx context.Context, input *DisableFir
 	params.Set("action", "disable_firewall")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  params,
+		Operation: "compute.instances.disable_firewall",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     params,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -882,8 +1108,9 @@ type ListNICsInput struct {
 func (c *InstancesClient) ListNICs(ctx context.Context, input *ListNICsInput) ([]*NIC, error) {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.InstanceID, "nics")
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.instances.list_nics",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -911,8 +1138,9 @@ func (c *InstancesClient) GetNIC(ctx context.Context, input *GetNICInput) (*NIC,
 	mac := strings.Replace(input.MAC, ":", "", -1)
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.InstanceID, "nics", mac)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.instances.get_nic",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	response, err := c.client.ExecuteRequestRaw(ctx, reqInputs)
 	if err != nil {
@@ -973,9 +1201,10 @@ func (input AddNICInput) toAPI() map[string]interface{} {
 func (c *InstancesClient) AddNIC(ctx context.Context, input *AddNICInput) (*NIC, error) {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.InstanceID, "nics")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input.toAPI(),
+		Operation: "compute.instances.add_nic",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input.toAPI(),
 	}
 	response, err := c.client.ExecuteRequestRaw(ctx, reqInputs)
 	if err != nil {
@@ -1015,8 +1244,9 @@ func (c *InstancesClient) RemoveNIC(ctx context.Context, input *RemoveNICInput)
 	mac := strings.Replace(input.MAC, ":", "", -1)
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.InstanceID, "nics", mac)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "compute.instances.remove_nic",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	response, err := c.client.ExecuteRequestRaw(ctx, reqInputs)
 	if err != nil {
@@ -1050,9 +1280,10 @@ func (c *InstancesClient) Stop(ctx context.Context, input *StopInstanceInput) er
 	params.Set("action", "stop")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  params,
+		Operation: "compute.instances.stop",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     params,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -1076,9 +1307,10 @@ func (c *InstancesClient) Start(ctx context.Context, input *StartInstanceInput)
 	params.Set("action", "start")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  params,
+		Operation: "compute.instances.start",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     params,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -1102,9 +1334,10 @@ func (c *InstancesClient) Reboot(ctx context.Context, input *RebootInstanceInput
 	params.Set("action", "reboot")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  params,
+		Operation: "compute.instances.reboot",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     params,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -1128,9 +1361,10 @@ func (c *InstancesClient) EnableDeletionProtection(ctx context.Context, input *E
 	params.Set("action", "enable_deletion_protection")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  params,
+		Operation: "compute.instances.enable_deletion_protection",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     params,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -1154,9 +1388,10 @@ func (c *InstancesClient) DisableDeletionProtection(ctx context.Context, input *
 	params.Set("action", "disable_deletion_protection")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Query:  params,
+		Operation: "compute.instances.disable_deletion_protection",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Query:     params,
 	}
 	respReader, err := c.client.ExecuteRequestURIParams(ctx, reqInputs)
 	if respReader != nil {
@@ -1169,6 +1404,76 @@ func (c *InstancesClient) DisableDeletionProtection(ctx context.Context, input *
 	return nil
 }
 
+// AuditEntry is an action recorded in the audit trail of an instance.
+type AuditEntry struct {
+	// Action is the action taken, e.g. "start", "stop" or "resize".
+	Action string      `json:"action"`
+	Caller AuditCaller `json:"caller"`
+	Time   time.Time   `json:"time"`
+
+	// Success reports whether the action succeeded.
+	Success bool `json:"success"`
+
+	// Parameters holds the parameters the action was called with, when
+	// CloudAPI records them.
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// AuditCaller identifies the caller of an audited action.
+type AuditCaller struct {
+	// Type is how the caller authenticated, e.g. "signature" or "operator".
+	Type  string `json:"type"`
+	IP    string `json:"ip,omitempty"`
+	KeyID string `json:"keyId,omitempty"`
+}
+
+// _AuditEntry is the API representation of an AuditEntry, which reports its
+// success as "yes" or "no".
+type _AuditEntry struct {
+	AuditEntry
+	Success string `json:"success"`
+}
+
+type AuditInstanceInput struct {
+	ID string
+}
+
+// Audit returns the audit trail of an instance, most recent action first.
+func (c *InstancesClient) Audit(ctx context.Context, input *AuditInstanceInput) ([]*AuditEntry, error) {
+	if input.ID == "" {
+		return nil, pkgerrors.New("unable to get machine audit: machine ID can not be empty")
+	}
+
+	fullPath := path.Join("/", c.client.AccountName, "machines", input.ID, "audit")
+	reqInputs := client.RequestInput{
+		Operation: "compute.instances.audit",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+	}
+	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
+	if respReader != nil {
+		defer respReader.Close()
+	}
+	if err != nil {
+		return nil, pkgerrors.Wrap(err, "unable to get machine audit")
+	}
+
+	var results []*_AuditEntry
+	decoder := json.NewDecoder(respReader)
+	if err = decoder.Decode(&results); err != nil {
+		return nil, pkgerrors.Wrap(err, "unable to decode get machine audit response")
+	}
+
+	entries := make([]*AuditEntry, 0, len(results))
+	for _, result := range results {
+		entry := result.AuditEntry
+		entry.Success = result.Success == "yes"
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
 var reservedInstanceCNSTags = map[string]struct{}{
 	CNSTagDisable:    {},
 	CNSTagReversePTR: {},
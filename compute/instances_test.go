@@ -10,6 +10,7 @@ package compute_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -583,6 +584,102 @@ func TestListInstances(t *testing.T) {
 	})
 }
 
+func TestListAllInstances(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient) ([]*compute.Instance, error) {
+		defer testutils.DeactivateClient()
+
+		iter := cc.Instances().ListAll(ctx, &compute.ListInstancesInput{
+			Limit: 2,
+		})
+
+		var instances []*compute.Instance
+		for iter.Next() {
+			instances = append(instances, iter.Instance())
+		}
+		return instances, iter.Err()
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines?limit=2&offset=0"), listMachinesPage(2))
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines?limit=2&offset=2"), listMachinesPage(2))
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines?limit=2&offset=4"), listMachinesPage(1))
+
+		resp, err := do(context.Background(), computeClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp) != 5 {
+			t.Errorf("expected 5 instances across all pages: got %d", len(resp))
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines?limit=2&offset=0"), listMachinesPage(0))
+
+		resp, err := do(context.Background(), computeClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp) != 0 {
+			t.Errorf("expected no instances: got %d", len(resp))
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines?limit=2&offset=0"), listMachinesPage(2))
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines?limit=2&offset=2"), listMachinesError)
+
+		resp, err := do(context.Background(), computeClient)
+		if err == nil {
+			t.Fatal("expected error not to be nil")
+		}
+
+		if len(resp) != 2 {
+			t.Errorf("expected the first page to be returned: got %d instances", len(resp))
+		}
+
+		if !strings.Contains(err.Error(), "unable to list machines") {
+			t.Errorf("expected error to equal testError: found %v", err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := do(ctx, computeClient)
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled: found %v", err)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines?limit=2&offset=0"), listMachinesPage(2))
+
+		iter := computeClient.Instances().ListAll(context.Background(), &compute.ListInstancesInput{
+			Limit: 2,
+		})
+		defer iter.Close()
+
+		if !iter.Next() {
+			t.Fatalf("expected an instance: got error %v", iter.Err())
+		}
+		if err := iter.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// No further page is registered, so fetching one would fail.
+		if iter.Next() || iter.Instance() != nil || iter.Err() != nil {
+			t.Errorf("expected the closed iterator to stop: got %v, %v", iter.Instance(), iter.Err())
+		}
+	})
+}
+
 func TestDeleteInstance(t *testing.T) {
 	computeClient := MockComputeClient()
 
@@ -1561,6 +1658,90 @@ func TestCreateInstance(t *testing.T) {
 	})
 }
 
+func TestCreateBhyveInstance(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	const pkgID = "7b17343c-94af-6266-e0e8-893a3b9993d0"
+
+	do := func(ctx context.Context, cc *compute.ComputeClient, pkg string, disks []compute.InstanceDisk) (map[string]interface{}, error) {
+		defer testutils.DeactivateClient()
+
+		var body map[string]interface{}
+		testutils.RegisterResponder("POST", path.Join("/", accountURL, "machines"), func(req *http.Request) (*http.Response, error) {
+			json.NewDecoder(req.Body).Decode(&body)
+			return createMachineSuccess(req)
+		})
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "packages", pkgID), func(req *http.Request) (*http.Response, error) {
+			return diskResponse(pkg), nil
+		})
+
+		_, err := cc.Instances().Create(ctx, &compute.CreateInstanceInput{
+			Image:              "2b683a82-a066-11e3-97ab-2faa44701c5a",
+			Package:            pkgID,
+			Disks:              disks,
+			DeletionProtection: true,
+			Encrypted:          true,
+			AllowSharedImages:  true,
+		})
+		return body, err
+	}
+
+	flexible := `{"id": "` + pkgID + `", "name": "flex-60G", "brand": "bhyve", "disk": 61440, "flexible_disk": true}`
+
+	t.Run("successful", func(t *testing.T) {
+		body, err := do(context.Background(), computeClient, flexible, []compute.InstanceDisk{
+			{},
+			{Size: 20480},
+			{Remaining: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		disks, _ := json.Marshal(body["disks"])
+		if string(disks) != `[{},{"size":20480},{"size":"remaining"}]` {
+			t.Errorf("unexpected disks: %s", disks)
+		}
+		for _, key := range []string{"deletion_protection", "encrypted", "allow_shared_images"} {
+			if body[key] != true {
+				t.Errorf("expected %s to be set: %v", key, body)
+			}
+		}
+	})
+
+	t.Run("not flexible", func(t *testing.T) {
+		body, err := do(context.Background(), computeClient, `{"id": "`+pkgID+`", "name": "sample-bhyve", "brand": "bhyve", "disk": 61440}`, []compute.InstanceDisk{
+			{Size: 20480},
+		})
+		if err == nil || !strings.Contains(err.Error(), "does not have flexible disk") {
+			t.Errorf("expected flexible disk error: found %v", err)
+		}
+		if body != nil {
+			t.Errorf("expected no instance to be created")
+		}
+	})
+
+	t.Run("too many remaining", func(t *testing.T) {
+		_, err := do(context.Background(), computeClient, flexible, []compute.InstanceDisk{
+			{Remaining: true},
+			{Remaining: true},
+		})
+		if err == nil || !strings.Contains(err.Error(), "more than one disk") {
+			t.Errorf("expected remaining error: found %v", err)
+		}
+	})
+
+	t.Run("over budget", func(t *testing.T) {
+		_, err := do(context.Background(), computeClient, flexible, []compute.InstanceDisk{
+			{Size: 40960},
+			{Size: 40960},
+		})
+		if err == nil || !strings.Contains(err.Error(), "exceed the 61440 MiB flexible disk of package flex-60G by 20480 MiB") {
+			t.Errorf("expected budget error: found %v", err)
+		}
+	})
+}
+
 func TestCountInstances(t *testing.T) {
 	computeClient := MockComputeClient()
 
@@ -1803,6 +1984,24 @@ func listMachinesSuccess(req *http.Request) (*http.Response, error) {
 	}, nil
 }
 
+func listMachinesPage(count int) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Add("Content-Type", "application/json")
+
+		machines := make([]string, count)
+		for i := range machines {
+			machines[i] = fmt.Sprintf(`{"id": "%s-%d", "name": "test", "state": "running"}`, req.URL.Query().Get("offset"), i)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader("[" + strings.Join(machines, ",") + "]")),
+		}, nil
+	}
+}
+
 func listMachinesEmpty(req *http.Request) (*http.Response, error) {
 	header := http.Header{}
 	header.Add("Content-Type", "application/json")
@@ -2377,3 +2576,88 @@ func disableDeletionProtectionSuccess(req *http.Request) (*http.Response, error)
 func disableDeletionProtectionError(req *http.Request) (*http.Response, error) {
 	return nil, errors.New("unable to disable deletion protection")
 }
+
+func TestGetInstanceAudit(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient) ([]*compute.AuditEntry, error) {
+		defer testutils.DeactivateClient()
+
+		return cc.Instances().Audit(ctx, &compute.AuditInstanceInput{
+			ID: fakeMachineID,
+		})
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID, "audit"), getMachineAuditSuccess)
+
+		resp, err := do(context.Background(), computeClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp) != 2 {
+			t.Fatalf("expected 2 audit entries: got %d", len(resp))
+		}
+		if resp[0].Action != "resize" || resp[0].Success || resp[0].Parameters["package"] != "g4-highcpu-1G" {
+			t.Errorf("unexpected failed resize entry: %+v", resp[0])
+		}
+		if resp[1].Action != "start" || !resp[1].Success || resp[1].Caller.KeyID != "/testing/keys/e3:4d" {
+			t.Errorf("unexpected start entry: %+v", resp[1])
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID, "audit"), getMachineAuditError)
+
+		_, err := do(context.Background(), computeClient)
+		if err == nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(err.Error(), "unable to get machine audit") {
+			t.Errorf("expected error to contain get machine audit: found %s", err)
+		}
+	})
+}
+
+func getMachineAuditSuccess(req *http.Request) (*http.Response, error) {
+	header := http.Header{}
+	header.Add("Content-Type", "application/json")
+
+	body := strings.NewReader(`[
+  {
+    "action": "resize",
+    "parameters": {
+      "package": "g4-highcpu-1G"
+    },
+    "success": "no",
+    "caller": {
+      "type": "signature",
+      "ip": "10.0.0.1",
+      "keyId": "/testing/keys/e3:4d"
+    },
+    "time": "2020-06-02T10:00:00.000Z"
+  },
+  {
+    "action": "start",
+    "success": "yes",
+    "caller": {
+      "type": "signature",
+      "ip": "10.0.0.1",
+      "keyId": "/testing/keys/e3:4d"
+    },
+    "time": "2020-06-01T10:00:00.000Z"
+  }
+]`)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(body),
+	}, nil
+}
+
+func getMachineAuditError(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("unable to get machine audit")
+}
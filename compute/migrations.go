@@ -0,0 +1,338 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package compute
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/joyent/triton-go/v2/client"
+	"github.com/pkg/errors"
+)
+
+type MigrationsClient struct {
+	client *client.Client
+}
+
+// MigrationAction is an action which drives an instance migration.
+type MigrationAction string
+
+const (
+	// MigrationBegin provisions the target instance of a migration.
+	MigrationBegin MigrationAction = "begin"
+
+	// MigrationSync copies the filesystem of the instance to the target
+	// instance. It may be repeated to copy what changed since the last sync.
+	MigrationSync MigrationAction = "sync"
+
+	// MigrationSwitch stops the instance, makes a final sync and switches
+	// over to the target instance.
+	MigrationSwitch MigrationAction = "switch"
+
+	// MigrationPause stops a running sync.
+	MigrationPause MigrationAction = "pause"
+
+	// MigrationAbort removes the target instance, leaving the instance
+	// where it was.
+	MigrationAbort MigrationAction = "abort"
+
+	// MigrationAutomatic runs the begin, sync and switch phases in order.
+	MigrationAutomatic MigrationAction = "automatic"
+
+	// MigrationFinalize removes the original instance once a migration has
+	// switched over successfully.
+	MigrationFinalize MigrationAction = "finalize"
+)
+
+const (
+	MigrationStateRunning    = "running"
+	MigrationStatePaused     = "paused"
+	MigrationStateAborted    = "aborted"
+	MigrationStateSuccessful = "successful"
+	MigrationStateFailed     = "failed"
+)
+
+// Migration represents the migration of an instance to another compute
+// node.
+type Migration struct {
+	// Machine is the ID of the instance being migrated.
+	Machine   string `json:"machine"`
+	Automatic bool   `json:"automatic"`
+
+	// Phase is the phase the migration is in, e.g. "sync".
+	Phase string `json:"phase"`
+
+	// State is the state of the phase, e.g. "running" or "paused".
+	State string `json:"state"`
+
+	Error           string               `json:"error,omitempty"`
+	CreatedTime     time.Time            `json:"created_timestamp"`
+	FinishedTime    time.Time            `json:"finished_timestamp"`
+	DurationMs      int64                `json:"duration_ms,omitempty"`
+	ProgressHistory []*MigrationProgress `json:"progress_history,omitempty"`
+}
+
+// MigrationProgress is an event reporting the progress of a migration
+// phase, as found in the progress history of a migration and in the feed
+// of a migration watch.
+type MigrationProgress struct {
+	// Type is "progress" while a phase runs and "end" once it is over.
+	Type  string `json:"type"`
+	Phase string `json:"phase"`
+	State string `json:"state"`
+
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+
+	// CurrentProgress counts the work done out of TotalProgress, e.g. in
+	// bytes for a sync.
+	CurrentProgress int64 `json:"current_progress,omitempty"`
+	TotalProgress   int64 `json:"total_progress,omitempty"`
+
+	StartedTime  time.Time `json:"started_timestamp"`
+	FinishedTime time.Time `json:"finished_timestamp"`
+	DurationMs   int64     `json:"duration_ms,omitempty"`
+}
+
+// IsEnd reports whether the event ends its phase.
+func (p *MigrationProgress) IsEnd() bool {
+	return p.Type == "end"
+}
+
+type ListMigrationsInput struct{}
+
+// List returns the migrations of the account, running or finished.
+func (c *MigrationsClient) List(ctx context.Context, _ *ListMigrationsInput) ([]*Migration, error) {
+	fullPath := path.Join("/", c.client.AccountName, "migrations")
+	reqInputs := client.RequestInput{
+		Operation: "compute.migrations.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+	}
+	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
+	if respReader != nil {
+		defer respReader.Close()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list migrations")
+	}
+
+	var result []*Migration
+	decoder := json.NewDecoder(respReader)
+	if err = decoder.Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "unable to decode list migrations response")
+	}
+
+	return result, nil
+}
+
+type GetMigrationInput struct {
+	// InstanceID is the ID of the instance being migrated.
+	InstanceID string
+}
+
+// Get returns the migration of an instance.
+func (c *MigrationsClient) Get(ctx context.Context, input *GetMigrationInput) (*Migration, error) {
+	if input.InstanceID == "" {
+		return nil, errors.New("unable to get migration: missing instance ID")
+	}
+
+	fullPath := path.Join("/", c.client.AccountName, "migrations", input.InstanceID)
+	reqInputs := client.RequestInput{
+		Operation: "compute.migrations.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+	}
+	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
+	if respReader != nil {
+		defer respReader.Close()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get migration")
+	}
+
+	var result *Migration
+	decoder := json.NewDecoder(respReader)
+	if err = decoder.Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "unable to decode get migration response")
+	}
+
+	return result, nil
+}
+
+type MigrateInstanceInput struct {
+	InstanceID string
+	Action     MigrationAction
+
+	// Affinity holds affinity rules, e.g. "instance!=db0", constraining the
+	// compute node the instance is migrated to. It only applies to the
+	// begin and automatic actions.
+	Affinity []string
+}
+
+// Migrate runs an action on the migration of an instance and returns the
+// migration. Actions return once the phase has started; use Watch or Get to
+// follow its progress.
+func (c *MigrationsClient) Migrate(ctx context.Context, input *MigrateInstanceInput) (*Migration, error) {
+	if input.InstanceID == "" {
+		return nil, errors.New("unable to migrate machine: missing instance ID")
+	}
+	switch input.Action {
+	case MigrationBegin, MigrationSync, MigrationSwitch, MigrationPause, MigrationAbort, MigrationAutomatic, MigrationFinalize:
+	default:
+		return nil, errors.Errorf("unable to migrate machine: invalid action %q", input.Action)
+	}
+	if len(input.Affinity) > 0 && input.Action != MigrationBegin && input.Action != MigrationAutomatic {
+		return nil, errors.Errorf("unable to migrate machine: affinity is not valid for action %q", input.Action)
+	}
+
+	fullPath := path.Join("/", c.client.AccountName, "machines", input.InstanceID, "migrate")
+
+	body := map[string]interface{}{
+		"action": input.Action,
+	}
+	if len(input.Affinity) > 0 {
+		body["affinity"] = input.Affinity
+	}
+
+	reqInputs := client.RequestInput{
+		Operation: "compute.migrations.migrate",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      body,
+	}
+	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
+	if respReader != nil {
+		defer respReader.Close()
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to %s machine migration", input.Action)
+	}
+
+	var result *Migration
+	decoder := json.NewDecoder(respReader)
+	if err = decoder.Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "unable to decode migrate machine response")
+	}
+
+	return result, nil
+}
+
+type WatchMigrationInput struct {
+	InstanceID string
+}
+
+// MigrationWatcher streams the progress events of a migration as CloudAPI
+// sends them. Use Next to advance it, Progress to read the current event
+// and Err to check for an error once Next returns false.
+type MigrationWatcher struct {
+	ctx    context.Context
+	client *MigrationsClient
+	input  *WatchMigrationInput
+
+	body    io.ReadCloser
+	decoder *json.Decoder
+	current *MigrationProgress
+	done    bool
+	err     error
+}
+
+// Watch returns a watcher over the progress events of the migration of an
+// instance. The feed is requested on the first call to Next and ends when
+// the running phase of the migration does. Close must be called if the
+// watcher is abandoned before Next returns false.
+func (c *MigrationsClient) Watch(ctx context.Context, input *WatchMigrationInput) *MigrationWatcher {
+	return &MigrationWatcher{
+		ctx:    ctx,
+		client: c,
+		input:  input,
+	}
+}
+
+// Next blocks until the next progress event arrives. It returns false when
+// the feed ends or an error occurred.
+func (w *MigrationWatcher) Next() bool {
+	w.current = nil
+	if w.done {
+		return false
+	}
+
+	if w.decoder == nil {
+		if err := w.open(); err != nil {
+			return w.stop(err)
+		}
+	}
+
+	progress := &MigrationProgress{}
+	if err := w.decoder.Decode(progress); err != nil {
+		if err == io.EOF {
+			return w.stop(nil)
+		}
+		if ctxErr := w.ctx.Err(); ctxErr != nil {
+			return w.stop(ctxErr)
+		}
+		return w.stop(errors.Wrap(err, "unable to decode watch migration response"))
+	}
+
+	w.current = progress
+	return true
+}
+
+func (w *MigrationWatcher) open() error {
+	if w.input.InstanceID == "" {
+		return errors.New("unable to watch migration: missing instance ID")
+	}
+
+	fullPath := path.Join("/", w.client.client.AccountName, "migrations", w.input.InstanceID, "watch")
+	reqInputs := client.RequestInput{
+		Operation: "compute.migrations.watch",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+	}
+	respReader, err := w.client.client.ExecuteRequest(w.ctx, reqInputs)
+	if respReader != nil {
+		w.body = respReader
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to watch migration")
+	}
+
+	w.decoder = json.NewDecoder(respReader)
+	return nil
+}
+
+func (w *MigrationWatcher) stop(err error) bool {
+	w.err = err
+	w.Close()
+	return false
+}
+
+// Progress returns the progress event the watcher currently points at.
+func (w *MigrationWatcher) Progress() *MigrationProgress {
+	return w.current
+}
+
+// Err returns the error which stopped the watcher, if any.
+func (w *MigrationWatcher) Err() error {
+	return w.err
+}
+
+// Close stops the watcher and releases the underlying response body.
+func (w *MigrationWatcher) Close() error {
+	w.done = true
+	if w.body != nil {
+		w.body.Close()
+		w.body = nil
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package compute_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/joyent/triton-go/v2/compute"
+	"github.com/joyent/triton-go/v2/testutils"
+)
+
+func TestListMigrations(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient) ([]*compute.Migration, error) {
+		defer testutils.DeactivateClient()
+
+		return cc.Migrations().List(ctx, &compute.ListMigrationsInput{})
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "migrations"), listMigrationsSuccess)
+
+		resp, err := do(context.Background(), computeClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp) != 1 || resp[0].Machine != fakeMachineID || resp[0].Phase != "sync" {
+			t.Errorf("unexpected migrations: %+v", resp)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "migrations"), migrationError)
+
+		_, err := do(context.Background(), computeClient)
+		if err == nil || !strings.Contains(err.Error(), "unable to list migrations") {
+			t.Errorf("expected error to contain list migrations: found %v", err)
+		}
+	})
+}
+
+func TestGetMigration(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient) (*compute.Migration, error) {
+		defer testutils.DeactivateClient()
+
+		return cc.Migrations().Get(ctx, &compute.GetMigrationInput{
+			InstanceID: fakeMachineID,
+		})
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "migrations", fakeMachineID), getMigrationSuccess)
+
+		resp, err := do(context.Background(), computeClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.State != compute.MigrationStateRunning || len(resp.ProgressHistory) != 2 {
+			t.Fatalf("unexpected migration: %+v", resp)
+		}
+		if sync := resp.ProgressHistory[1]; sync.CurrentProgress != 512 || sync.TotalProgress != 1024 {
+			t.Errorf("unexpected sync progress: %+v", sync)
+		}
+		if !resp.ProgressHistory[0].IsEnd() {
+			t.Errorf("expected the begin phase to have ended")
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "migrations", fakeMachineID), migrationError)
+
+		_, err := do(context.Background(), computeClient)
+		if err == nil || !strings.Contains(err.Error(), "unable to get migration") {
+			t.Errorf("expected error to contain get migration: found %v", err)
+		}
+	})
+}
+
+func TestMigrateInstance(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient, input *compute.MigrateInstanceInput) (*compute.Migration, error) {
+		defer testutils.DeactivateClient()
+
+		return cc.Migrations().Migrate(ctx, input)
+	}
+
+	// migrate records the parameters of the migrate request.
+	migrate := func(params *map[string]interface{}) func(req *http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			if len(req.URL.Query()) != 0 {
+				t.Errorf("expected no query parameters: got %s", req.URL.RawQuery)
+			}
+			json.NewDecoder(req.Body).Decode(params)
+			return getMigrationSuccess(req)
+		}
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		var params map[string]interface{}
+		testutils.RegisterResponder("POST", path.Join("/", accountURL, "machines", fakeMachineID, "migrate"), migrate(&params))
+
+		resp, err := do(context.Background(), computeClient, &compute.MigrateInstanceInput{
+			InstanceID: fakeMachineID,
+			Action:     compute.MigrationBegin,
+			Affinity:   []string{"instance!=db0"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.Machine != fakeMachineID {
+			t.Errorf("unexpected migration: %+v", resp)
+		}
+		affinity, _ := params["affinity"].([]interface{})
+		if params["action"] != "begin" || len(affinity) != 1 || affinity[0] != "instance!=db0" {
+			t.Errorf("unexpected migrate parameters: %v", params)
+		}
+	})
+
+	t.Run("finalize", func(t *testing.T) {
+		var params map[string]interface{}
+		testutils.RegisterResponder("POST", path.Join("/", accountURL, "machines", fakeMachineID, "migrate"), migrate(&params))
+
+		_, err := do(context.Background(), computeClient, &compute.MigrateInstanceInput{
+			InstanceID: fakeMachineID,
+			Action:     compute.MigrationFinalize,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if params["action"] != "finalize" || params["affinity"] != nil {
+			t.Errorf("unexpected migrate parameters: %v", params)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := do(context.Background(), computeClient, &compute.MigrateInstanceInput{
+			InstanceID: fakeMachineID,
+			Action:     compute.MigrationSync,
+			Affinity:   []string{"instance!=db0"},
+		})
+		if err == nil || !strings.Contains(err.Error(), "affinity is not valid") {
+			t.Errorf("expected affinity to be rejected: found %v", err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		testutils.RegisterResponder("POST", path.Join("/", accountURL, "machines", fakeMachineID, "migrate"), migrationError)
+
+		_, err := do(context.Background(), computeClient, &compute.MigrateInstanceInput{
+			InstanceID: fakeMachineID,
+			Action:     compute.MigrationAbort,
+		})
+		if err == nil || !strings.Contains(err.Error(), "unable to abort machine migration") {
+			t.Errorf("expected error to contain abort machine migration: found %v", err)
+		}
+	})
+}
+
+func TestWatchMigration(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	t.Run("successful", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "migrations", fakeMachineID, "watch"), watchMigrationSuccess)
+
+		watcher := computeClient.Migrations().Watch(context.Background(), &compute.WatchMigrationInput{
+			InstanceID: fakeMachineID,
+		})
+		defer watcher.Close()
+
+		var events []string
+		for watcher.Next() {
+			progress := watcher.Progress()
+			events = append(events, fmt.Sprintf("%s %s %d/%d", progress.Type, progress.Phase, progress.CurrentProgress, progress.TotalProgress))
+		}
+		if err := watcher.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "progress sync 256/1024,progress sync 1024/1024,end sync 0/0"
+		if strings.Join(events, ",") != expected {
+			t.Errorf("expected events %s: got %v", expected, events)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "migrations", fakeMachineID, "watch"), migrationError)
+
+		watcher := computeClient.Migrations().Watch(context.Background(), &compute.WatchMigrationInput{
+			InstanceID: fakeMachineID,
+		})
+		if watcher.Next() {
+			t.Fatal("expected no progress")
+		}
+		if err := watcher.Err(); err == nil || !strings.Contains(err.Error(), "unable to watch migration") {
+			t.Errorf("expected error to contain watch migration: found %v", err)
+		}
+	})
+}
+
+func listMigrationsSuccess(req *http.Request) (*http.Response, error) {
+	header := http.Header{}
+	header.Add("Content-Type", "application/json")
+
+	body := strings.NewReader(`[
+  {
+    "machine": "75cfe125-a5ce-49e8-82ac-09aa31ffdf26",
+    "automatic": false,
+    "phase": "sync",
+    "state": "paused",
+    "created_timestamp": "2020-06-01T10:00:00.000Z"
+  }
+]`)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(body),
+	}, nil
+}
+
+func getMigrationSuccess(req *http.Request) (*http.Response, error) {
+	header := http.Header{}
+	header.Add("Content-Type", "application/json")
+
+	body := strings.NewReader(`{
+  "machine": "75cfe125-a5ce-49e8-82ac-09aa31ffdf26",
+  "automatic": false,
+  "phase": "sync",
+  "state": "running",
+  "created_timestamp": "2020-06-01T10:00:00.000Z",
+  "progress_history": [
+    {
+      "type": "end",
+      "phase": "begin",
+      "state": "success",
+      "message": "reserved the instance in the target compute node",
+      "started_timestamp": "2020-06-01T10:00:00.000Z",
+      "finished_timestamp": "2020-06-01T10:00:30.000Z",
+      "duration_ms": 30000
+    },
+    {
+      "type": "progress",
+      "phase": "sync",
+      "state": "running",
+      "current_progress": 512,
+      "total_progress": 1024,
+      "started_timestamp": "2020-06-01T10:01:00.000Z"
+    }
+  ]
+}`)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(body),
+	}, nil
+}
+
+func watchMigrationSuccess(req *http.Request) (*http.Response, error) {
+	header := http.Header{}
+	header.Add("Content-Type", "application/x-json-stream")
+
+	body := strings.NewReader(`{"type":"progress","phase":"sync","state":"running","current_progress":256,"total_progress":1024}
+{"type":"progress","phase":"sync","state":"running","current_progress":1024,"total_progress":1024}
+{"type":"end","phase":"sync","state":"success"}
+`)
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(body),
+	}, nil
+}
+
+func migrationError(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("unable to migrate machine")
+}
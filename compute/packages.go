@@ -121,9 +121,10 @@ func (c *PackagesClient) List(ctx context.Context, input *ListPackagesInput) ([]
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
-		Query:  query,
+		Operation: "compute.packages.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+		Query:     query,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -149,8 +150,9 @@ type GetPackageInput struct {
 func (c *PackagesClient) Get(ctx context.Context, input *GetPackageInput) (*Package, error) {
 	fullPath := path.Join("/", c.client.AccountName, "packages", input.ID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.packages.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
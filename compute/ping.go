@@ -32,8 +32,9 @@ type PingOutput struct {
 // as a list of API version numbers your instance of CloudAPI is presenting.
 func (c *ComputeClient) Ping(ctx context.Context) (*PingOutput, error) {
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   pingEndpoint,
+		Operation: "compute.ping",
+		Method:    http.MethodGet,
+		Path:      pingEndpoint,
 	}
 	response, err := c.Client.ExecuteRequestRaw(ctx, reqInputs)
 	if err != nil {
@@ -33,8 +33,9 @@ type ListServicesInput struct{}
 func (c *ServicesClient) List(ctx context.Context, _ *ListServicesInput) ([]*Service, error) {
 	fullPath := path.Join("/", c.client.AccountName, "services")
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.services.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
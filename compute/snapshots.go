@@ -37,8 +37,9 @@ type ListSnapshotsInput struct {
 func (c *SnapshotsClient) List(ctx context.Context, input *ListSnapshotsInput) ([]*Snapshot, error) {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.MachineID, "snapshots")
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.snapshots.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -65,8 +66,9 @@ type GetSnapshotInput struct {
 func (c *SnapshotsClient) Get(ctx context.Context, input *GetSnapshotInput) (*Snapshot, error) {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.MachineID, "snapshots", input.Name)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.snapshots.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -93,8 +95,9 @@ type DeleteSnapshotInput struct {
 func (c *SnapshotsClient) Delete(ctx context.Context, input *DeleteSnapshotInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.MachineID, "snapshots", input.Name)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "compute.snapshots.delete",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -115,8 +118,9 @@ type StartMachineFromSnapshotInput struct {
 func (c *SnapshotsClient) StartMachine(ctx context.Context, input *StartMachineFromSnapshotInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "machines", input.MachineID, "snapshots", input.Name)
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
+		Operation: "compute.snapshots.start_machine",
+		Method:    http.MethodPost,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -141,9 +145,10 @@ func (c *SnapshotsClient) Create(ctx context.Context, input *CreateSnapshotInput
 	data["name"] = input.Name
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   data,
+		Operation: "compute.snapshots.create",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      data,
 	}
 
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
@@ -61,9 +61,10 @@ func (c *VolumesClient) List(ctx context.Context, input *ListVolumesInput) ([]*V
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
-		Query:  query,
+		Operation: "compute.volumes.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+		Query:     query,
 	}
 	resp, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if resp != nil {
@@ -120,9 +121,10 @@ func (c *VolumesClient) Create(ctx context.Context, input *CreateVolumeInput) (*
 	fullPath := path.Join("/", c.client.AccountName, "volumes")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input.toAPI(),
+		Operation: "compute.volumes.create",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input.toAPI(),
 	}
 	resp, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
@@ -148,8 +150,9 @@ type DeleteVolumeInput struct {
 func (c *VolumesClient) Delete(ctx context.Context, input *DeleteVolumeInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "volumes", input.ID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "compute.volumes.delete",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	resp, err := c.client.ExecuteRequestRaw(ctx, reqInputs)
 	if err != nil {
@@ -175,8 +178,9 @@ type GetVolumeInput struct {
 func (c *VolumesClient) Get(ctx context.Context, input *GetVolumeInput) (*Volume, error) {
 	fullPath := path.Join("/", c.client.AccountName, "volumes", input.ID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "compute.volumes.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	resp, err := c.client.ExecuteRequestRaw(ctx, reqInputs)
 	if err != nil {
@@ -207,9 +211,10 @@ func (c *VolumesClient) Update(ctx context.Context, input *UpdateVolumeInput) er
 	fullPath := path.Join("/", c.client.AccountName, "volumes", input.ID)
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "compute.volumes.update",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	resp, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
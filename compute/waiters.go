@@ -0,0 +1,404 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/joyent/triton-go/v2/errors"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	defaultPollInterval    = 1 * time.Second
+	defaultMaxPollInterval = 10 * time.Second
+)
+
+// WaitConfig controls how often a waiter polls CloudAPI. The delay between
+// two polls starts at PollInterval and doubles after every poll until it
+// reaches MaxPollInterval. The zero value polls after 1s, backing off to at
+// most 10s.
+type WaitConfig struct {
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+}
+
+func (w WaitConfig) intervals() (time.Duration, time.Duration) {
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	maxInterval := w.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultMaxPollInterval
+	}
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+
+	return interval, maxInterval
+}
+
+// StateError is returned by a waiter when the resource it is waiting on
+// reaches a state from which the awaited state can no longer be reached, such
+// as "failed".
+type StateError struct {
+	Resource string
+	ID       string
+	State    string
+	Expected string
+}
+
+// Error implements interface Error on the StateError type.
+func (e *StateError) Error() string {
+	return fmt.Sprintf("%s %s reached state %q while waiting for state %q", e.Resource, e.ID, e.State, e.Expected)
+}
+
+// poll calls check until it reports that it is done, returns an error or ctx
+// is cancelled, sleeping between calls according to cfg.
+func poll(ctx context.Context, cfg WaitConfig, check func() (bool, error)) error {
+	interval, maxInterval := cfg.intervals()
+
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// isFailedState reports whether state is terminal for any resource other
+// than the one it is the expected state of.
+func isFailedState(state string, expected string) bool {
+	return state != expected && (state == "failed" || state == "deleted")
+}
+
+type WaitForInstanceStateInput struct {
+	ID    string
+	State string
+	WaitConfig
+}
+
+// WaitForState polls an instance until it reaches input.State and returns
+// the instance as last seen. It fails with a *StateError if the instance
+// fails or is deleted first.
+func (c *InstancesClient) WaitForState(ctx context.Context, input *WaitForInstanceStateInput) (*Instance, error) {
+	if input.State == "" {
+		return nil, pkgerrors.New("unable to wait for machine: missing state")
+	}
+
+	var instance *Instance
+	err := poll(ctx, input.WaitConfig, func() (bool, error) {
+		current, err := c.Get(ctx, &GetInstanceInput{ID: input.ID})
+		if current == nil || err != nil && !errors.IsSpecificStatusCode(err, http.StatusGone) {
+			return false, err
+		}
+		instance = current
+
+		if current.State == input.State {
+			return true, nil
+		}
+		if isFailedState(current.State, input.State) {
+			return false, &StateError{
+				Resource: "machine",
+				ID:       input.ID,
+				State:    current.State,
+				Expected: input.State,
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return instance, pkgerrors.Wrap(err, "unable to wait for machine state")
+	}
+
+	return instance, nil
+}
+
+type WaitForInstanceDeletionInput struct {
+	ID string
+	WaitConfig
+}
+
+// WaitForDeletion polls an instance until CloudAPI reports it as deleted,
+// either through an HTTP 410 Gone response or because it no longer exists.
+func (c *InstancesClient) WaitForDeletion(ctx context.Context, input *WaitForInstanceDeletionInput) error {
+	err := poll(ctx, input.WaitConfig, func() (bool, error) {
+		current, err := c.Get(ctx, &GetInstanceInput{ID: input.ID})
+		if errors.IsStatusNotFoundCode(err) {
+			return true, nil
+		}
+		if current == nil || err != nil && !errors.IsSpecificStatusCode(err, http.StatusGone) {
+			return false, err
+		}
+
+		switch current.State {
+		case "deleted":
+			return true, nil
+		case "failed":
+			return false, &StateError{
+				Resource: "machine",
+				ID:       input.ID,
+				State:    current.State,
+				Expected: "deleted",
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return pkgerrors.Wrap(err, "unable to wait for machine deletion")
+	}
+
+	return nil
+}
+
+type WaitForSnapshotStateInput struct {
+	MachineID string
+	Name      string
+
+	// State defaults to "created".
+	State string
+	WaitConfig
+}
+
+// WaitForState polls a snapshot until it reaches input.State, failing with
+// a *StateError if the snapshot fails first.
+func (c *SnapshotsClient) WaitForState(ctx context.Context, input *WaitForSnapshotStateInput) (*Snapshot, error) {
+	expected := input.State
+	if expected == "" {
+		expected = "created"
+	}
+
+	var snapshot *Snapshot
+	err := poll(ctx, input.WaitConfig, func() (bool, error) {
+		current, err := c.Get(ctx, &GetSnapshotInput{
+			MachineID: input.MachineID,
+			Name:      input.Name,
+		})
+		if err != nil {
+			return false, err
+		}
+		snapshot = current
+
+		if current.State == expected {
+			return true, nil
+		}
+		if isFailedState(current.State, expected) {
+			return false, &StateError{
+				Resource: "snapshot",
+				ID:       input.Name,
+				State:    current.State,
+				Expected: expected,
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return snapshot, pkgerrors.Wrap(err, "unable to wait for snapshot state")
+	}
+
+	return snapshot, nil
+}
+
+type WaitForImageStateInput struct {
+	ImageID string
+
+	// State defaults to "active".
+	State string
+	WaitConfig
+}
+
+// WaitForState polls an image until it reaches input.State, failing with a
+// *StateError if the image creation fails first.
+func (c *ImagesClient) WaitForState(ctx context.Context, input *WaitForImageStateInput) (*Image, error) {
+	expected := input.State
+	if expected == "" {
+		expected = "active"
+	}
+
+	var image *Image
+	err := poll(ctx, input.WaitConfig, func() (bool, error) {
+		current, err := c.Get(ctx, &GetImageInput{ImageID: input.ImageID})
+		if err != nil {
+			return false, err
+		}
+		image = current
+
+		if current.State == expected {
+			return true, nil
+		}
+		if isFailedState(current.State, expected) {
+			return false, &StateError{
+				Resource: "image",
+				ID:       input.ImageID,
+				State:    current.State,
+				Expected: expected,
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return image, pkgerrors.Wrap(err, "unable to wait for image state")
+	}
+
+	return image, nil
+}
+
+type WaitForVolumeStateInput struct {
+	ID string
+
+	// State defaults to "ready".
+	State string
+	WaitConfig
+}
+
+// WaitForState polls a volume until it reaches input.State, failing with a
+// *StateError if the volume fails first.
+func (c *VolumesClient) WaitForState(ctx context.Context, input *WaitForVolumeStateInput) (*Volume, error) {
+	expected := input.State
+	if expected == "" {
+		expected = "ready"
+	}
+
+	var volume *Volume
+	err := poll(ctx, input.WaitConfig, func() (bool, error) {
+		current, err := c.Get(ctx, &GetVolumeInput{ID: input.ID})
+		if err != nil {
+			return false, err
+		}
+		volume = current
+
+		if current.State == expected {
+			return true, nil
+		}
+		if isFailedState(current.State, expected) {
+			return false, &StateError{
+				Resource: "volume",
+				ID:       input.ID,
+				State:    current.State,
+				Expected: expected,
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return volume, pkgerrors.Wrap(err, "unable to wait for volume state")
+	}
+
+	return volume, nil
+}
+
+type WaitForDiskStateInput struct {
+	InstanceID string
+	ID         string
+
+	// State defaults to "running".
+	State string
+	WaitConfig
+}
+
+// WaitForState polls a disk until it reaches input.State, failing with a
+// *StateError if the disk fails first.
+func (c *DisksClient) WaitForState(ctx context.Context, input *WaitForDiskStateInput) (*Disk, error) {
+	expected := input.State
+	if expected == "" {
+		expected = DiskStateRunning
+	}
+
+	var disk *Disk
+	err := poll(ctx, input.WaitConfig, func() (bool, error) {
+		current, err := c.Get(ctx, &GetDiskInput{
+			InstanceID: input.InstanceID,
+			ID:         input.ID,
+		})
+		if err != nil {
+			return false, err
+		}
+		disk = current
+
+		if current.State == expected {
+			return true, nil
+		}
+		if isFailedState(current.State, expected) {
+			return false, &StateError{
+				Resource: "disk",
+				ID:       input.ID,
+				State:    current.State,
+				Expected: expected,
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return disk, pkgerrors.Wrap(err, "unable to wait for disk state")
+	}
+
+	return disk, nil
+}
+
+type WaitForDiskDeletionInput struct {
+	InstanceID string
+	ID         string
+	WaitConfig
+}
+
+// WaitForDeletion polls a disk until it no longer exists.
+func (c *DisksClient) WaitForDeletion(ctx context.Context, input *WaitForDiskDeletionInput) error {
+	err := poll(ctx, input.WaitConfig, func() (bool, error) {
+		current, err := c.Get(ctx, &GetDiskInput{
+			InstanceID: input.InstanceID,
+			ID:         input.ID,
+		})
+		if errors.IsStatusNotFoundCode(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if current.State == DiskStateFailed {
+			return false, &StateError{
+				Resource: "disk",
+				ID:       input.ID,
+				State:    current.State,
+				Expected: "deleted",
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return pkgerrors.Wrap(err, "unable to wait for disk deletion")
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package compute_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joyent/triton-go/v2/compute"
+	"github.com/joyent/triton-go/v2/testutils"
+	pkgerrors "github.com/pkg/errors"
+)
+
+var fastWait = compute.WaitConfig{
+	PollInterval:    time.Millisecond,
+	MaxPollInterval: 2 * time.Millisecond,
+}
+
+// stateSequence returns a responder which replies with the given states, one
+// per request, repeating the last one. A state prefixed with a status code
+// and a colon (e.g. "410:deleted") is returned with that status code.
+func stateSequence(body string, states ...string) func(req *http.Request) (*http.Response, error) {
+	calls := 0
+	return func(req *http.Request) (*http.Response, error) {
+		state := states[len(states)-1]
+		if calls < len(states) {
+			state = states[calls]
+		}
+		calls++
+
+		status := http.StatusOK
+		if i := strings.Index(state, ":"); i != -1 {
+			fmt.Sscanf(state[:i], "%d", &status)
+			state = state[i+1:]
+		}
+
+		header := http.Header{}
+		header.Add("Content-Type", "application/json")
+
+		return &http.Response{
+			StatusCode: status,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(fmt.Sprintf(body, state))),
+		}, nil
+	}
+}
+
+var (
+	waitMachineBody  = `{"id": "` + fakeMachineID + `", "name": "test", "state": %q}`
+	waitNotFoundBody = `{"code": "ResourceNotFound", "message": "%s"}`
+)
+
+func TestWaitForInstanceState(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient, state string) (*compute.Instance, error) {
+		defer testutils.DeactivateClient()
+
+		return cc.Instances().WaitForState(ctx, &compute.WaitForInstanceStateInput{
+			ID:         fakeMachineID,
+			State:      state,
+			WaitConfig: fastWait,
+		})
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID),
+			stateSequence(waitMachineBody, "provisioning", "provisioning", "running"))
+
+		instance, err := do(context.Background(), computeClient, "running")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if instance.State != "running" {
+			t.Errorf("expected instance to be running: got %q", instance.State)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID),
+			stateSequence(waitMachineBody, "provisioning", "failed"))
+
+		_, err := do(context.Background(), computeClient, "running")
+		stateErr, ok := pkgerrors.Cause(err).(*compute.StateError)
+		if !ok {
+			t.Fatalf("expected a StateError: got %v", err)
+		}
+
+		if stateErr.State != "failed" {
+			t.Errorf("expected failed state: got %q", stateErr.State)
+		}
+	})
+
+	t.Run("gone", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID),
+			stateSequence(waitMachineBody, "stopping", "410:deleted"))
+
+		_, err := do(context.Background(), computeClient, "stopped")
+		if _, ok := pkgerrors.Cause(err).(*compute.StateError); !ok {
+			t.Fatalf("expected a StateError: got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID),
+			stateSequence(waitMachineBody, "provisioning"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := do(ctx, computeClient, "running")
+		if pkgerrors.Cause(err) != context.DeadlineExceeded {
+			t.Errorf("expected deadline to be exceeded: got %v", err)
+		}
+	})
+}
+
+func TestWaitForInstanceDeletion(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	do := func(ctx context.Context, cc *compute.ComputeClient) error {
+		defer testutils.DeactivateClient()
+
+		return cc.Instances().WaitForDeletion(ctx, &compute.WaitForInstanceDeletionInput{
+			ID:         fakeMachineID,
+			WaitConfig: fastWait,
+		})
+	}
+
+	t.Run("gone", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID),
+			stateSequence(waitMachineBody, "running", "stopping", "410:deleted"))
+
+		if err := do(context.Background(), computeClient); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID),
+			stateSequence(waitNotFoundBody, "404:not found"))
+
+		if err := do(context.Background(), computeClient); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestWaitForSnapshotState(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID, "snapshots", "snap"),
+		stateSequence(`{"name": "snap", "state": %q}`, "queued", "created"))
+	defer testutils.DeactivateClient()
+
+	snapshot, err := computeClient.Snapshots().WaitForState(context.Background(), &compute.WaitForSnapshotStateInput{
+		MachineID:  fakeMachineID,
+		Name:       "snap",
+		WaitConfig: fastWait,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshot.State != "created" {
+		t.Errorf("expected snapshot to be created: got %q", snapshot.State)
+	}
+}
+
+func TestWaitForImageState(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	testutils.RegisterResponder("GET", path.Join("/", accountURL, "images", "image-id"),
+		stateSequence(`{"id": "image-id", "state": %q}`, "creating", "failed"))
+	defer testutils.DeactivateClient()
+
+	_, err := computeClient.Images().WaitForState(context.Background(), &compute.WaitForImageStateInput{
+		ImageID:    "image-id",
+		WaitConfig: fastWait,
+	})
+	if _, ok := pkgerrors.Cause(err).(*compute.StateError); !ok {
+		t.Fatalf("expected a StateError: got %v", err)
+	}
+}
+
+func TestWaitForVolumeState(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	testutils.RegisterResponder("GET", path.Join("/", accountURL, "volumes", "volume-id"),
+		stateSequence(`{"id": "volume-id", "state": %q}`, "creating", "ready"))
+	defer testutils.DeactivateClient()
+
+	volume, err := computeClient.Volumes().WaitForState(context.Background(), &compute.WaitForVolumeStateInput{
+		ID:         "volume-id",
+		WaitConfig: fastWait,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if volume.State != "ready" {
+		t.Errorf("expected volume to be ready: got %q", volume.State)
+	}
+}
+
+func TestWaitForDiskState(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID, "disks", "disk-id"),
+		stateSequence(`{"id": "disk-id", "size": 10240, "state": %q}`, "resizing", "resizing", "running"))
+	defer testutils.DeactivateClient()
+
+	disk, err := computeClient.Disks().WaitForState(context.Background(), &compute.WaitForDiskStateInput{
+		InstanceID: fakeMachineID,
+		ID:         "disk-id",
+		WaitConfig: fastWait,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if disk.State != compute.DiskStateRunning {
+		t.Errorf("expected disk to be running: got %q", disk.State)
+	}
+}
+
+func TestWaitForDiskDeletion(t *testing.T) {
+	computeClient := MockComputeClient()
+
+	testutils.RegisterResponder("GET", path.Join("/", accountURL, "machines", fakeMachineID, "disks", "disk-id"),
+		stateSequence(`{"id": "disk-id", "state": %q}`, "deleting", "404:ResourceNotFound"))
+	defer testutils.DeactivateClient()
+
+	err := computeClient.Disks().WaitForDeletion(context.Background(), &compute.WaitForDiskDeletionInput{
+		InstanceID: fakeMachineID,
+		ID:         "disk-id",
+		WaitConfig: fastWait,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -9,11 +9,11 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
 	"strings"
-
-	"github.com/pkg/errors"
+	"time"
 )
 
 // APIError represents an error code and message along with
@@ -22,15 +22,98 @@ import (
 // https://apidocs.joyent.com/cloudapi/#cloudapi-http-responses
 // Error codes used by the Manta API are listed at
 // https://apidocs.joyent.com/manta/api.html#errors
+//
+// APIError matches the Code sentinels below with errors.Is, and can be
+// extracted from wrapped errors with errors.As.
 type APIError struct {
 	StatusCode int
 	Code       string `json:"code"`
 	Message    string `json:"message"`
+
+	// RequestID and ServerName identify the request to the operator of the
+	// API, from the x-request-id and x-server-name response headers.
+	RequestID  string `json:"-"`
+	ServerName string `json:"-"`
+
+	// Method and Path are those of the failed request.
+	Method string `json:"-"`
+	Path   string `json:"-"`
+
+	// RetryAfter is how long the server asked clients to wait before trying
+	// again, or zero if it did not send a Retry-After header.
+	RetryAfter time.Duration `json:"-"`
 }
 
 // Error implements interface Error on the APIError type.
 func (e APIError) Error() string {
-	return strings.Trim(fmt.Sprintf("%+q", e.Code), `"`) + ": " + strings.Trim(fmt.Sprintf("%+q", e.Message), `"`)
+	msg := strings.Trim(fmt.Sprintf("%+q", e.Code), `"`) + ": " + strings.Trim(fmt.Sprintf("%+q", e.Message), `"`)
+	if e.RequestID != "" {
+		msg += " (request id: " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// Is makes APIError match the Code sentinel of its error code with errors.Is.
+func (e *APIError) Is(target error) bool {
+	code, ok := target.(Code)
+	return ok && string(code) == e.Code
+}
+
+// Temporary reports whether the error is caused by a condition of the server
+// which is expected to clear by itself, such as throttling or an outage.
+func (e *APIError) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	switch Code(e.Code) {
+	case ErrRequestThrottled, ErrServiceUnavailable:
+		return true
+	}
+
+	return false
+}
+
+// Retryable reports whether sending the same request again, after waiting
+// RetryAfter, may succeed. Requests which were throttled or refused as
+// unavailable were not acted upon and are always retryable. Other temporary
+// errors are only retryable for idempotent methods, since the server may
+// already have acted on the request.
+func (e *APIError) Retryable() bool {
+	if !e.Temporary() {
+		return false
+	}
+
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests,
+		e.StatusCode == http.StatusServiceUnavailable,
+		Code(e.Code) == ErrRequestThrottled,
+		Code(e.Code) == ErrServiceUnavailable:
+		return true
+	}
+
+	switch e.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+
+	return false
+}
+
+// Code is an error code returned by the Triton or Manta APIs. The Err*
+// constants are sentinels matching any APIError with their code:
+//
+//	if errors.Is(err, tritonerrors.ErrResourceNotFound) {
+//		...
+//	}
+type Code string
+
+// Error implements interface Error on the Code type.
+func (c Code) Error() string {
+	return string(c)
 }
 
 // ClientError represents an error code and message returned
@@ -46,6 +129,62 @@ func (e ClientError) Error() string {
 	return strings.Trim(fmt.Sprintf("%+q", e.Code), `"`) + ": " + strings.Trim(fmt.Sprintf("%+q", e.Message), `"`)
 }
 
+// Error codes of the Triton and Manta APIs.
+const (
+	ErrAuthScheme             Code = "AuthScheme"
+	ErrAuthorization          Code = "Authorization"
+	ErrBadRequest             Code = "BadRequest"
+	ErrChecksum               Code = "Checksum"
+	ErrConcurrentRequest      Code = "ConcurrentRequest"
+	ErrContentLength          Code = "ContentLength"
+	ErrContentMD5Mismatch     Code = "ContentMD5Mismatch"
+	ErrDirectoryDoesNotExist  Code = "DirectoryDoesNotExist"
+	ErrDirectoryExists        Code = "DirectoryExists"
+	ErrDirectoryNotEmpty      Code = "DirectoryNotEmpty"
+	ErrDirectoryOperation     Code = "DirectoryOperation"
+	ErrEmptyResponse          Code = "EmptyResponse"
+	ErrEntityExists           Code = "EntityExists"
+	ErrInUse                  Code = "InUseError"
+	ErrInternal               Code = "Internal"
+	ErrInvalidArgument        Code = "InvalidArgument"
+	ErrInvalidAuthToken       Code = "InvalidAuthToken"
+	ErrInvalidCredentials     Code = "InvalidCredentials"
+	ErrInvalidDurabilityLevel Code = "InvalidDurabilityLevel"
+	ErrInvalidHeader          Code = "InvalidHeader"
+	ErrInvalidJob             Code = "InvalidJob"
+	ErrInvalidKeyId           Code = "InvalidKeyId"
+	ErrInvalidLimit           Code = "InvalidLimit"
+	ErrInvalidLink            Code = "InvalidLink"
+	ErrInvalidSignature       Code = "InvalidSignature"
+	ErrInvalidUpdate          Code = "InvalidUpdate"
+	ErrInvalidVersion         Code = "InvalidVersion"
+	ErrJobNotFound            Code = "JobNotFound"
+	ErrJobState               Code = "JobState"
+	ErrKeyDoesNotExist        Code = "KeyDoesNotExist"
+	ErrLinkNotFound           Code = "LinkNotFound"
+	ErrLinkNotObject          Code = "LinkNotObject"
+	ErrLinkRequired           Code = "LinkRequired"
+	ErrMissingParameter       Code = "MissingParameter"
+	ErrNotAcceptable          Code = "NotAcceptable"
+	ErrNotAuthorized          Code = "NotAuthorized"
+	ErrNotEnoughSpace         Code = "NotEnoughSpace"
+	ErrParentNotDirectory     Code = "ParentNotDirectory"
+	ErrPreSignedRequest       Code = "PreSignedRequest"
+	ErrPreconditionFailed     Code = "PreconditionFailed"
+	ErrRequestEntityTooLarge  Code = "RequestEntityTooLarge"
+	ErrRequestMoved           Code = "RequestMoved"
+	ErrRequestThrottled       Code = "RequestThrottled"
+	ErrRequestTooLarge        Code = "RequestTooLarge"
+	ErrResourceFound          Code = "ResourceFound"
+	ErrResourceNotFound       Code = "ResourceNotFound"
+	ErrRootDirectory          Code = "RootDirectory"
+	ErrSSLRequired            Code = "SSLRequired"
+	ErrServiceUnavailable     Code = "ServiceUnavailable"
+	ErrUnknown                Code = "UnknownError"
+	ErrUploadTimeout          Code = "UploadTimeout"
+	ErrUserDoesNotExist       Code = "UserDoesNotExist"
+)
+
 func IsAuthSchemeError(err error) bool {
 	return IsSpecificError(err, "AuthScheme")
 }
@@ -274,24 +413,16 @@ func IsStatusNotFoundCode(err error) bool {
 	return IsSpecificStatusCode(err, http.StatusNotFound)
 }
 
+// IsSpecificError reports whether myError, or any error it wraps, is an
+// APIError with the given error code.
 func IsSpecificError(myError error, errorCode string) bool {
-	switch err := errors.Cause(myError).(type) {
-	case *APIError:
-		if err.Code == errorCode {
-			return true
-		}
-	}
-
-	return false
+	var err *APIError
+	return stderrors.As(myError, &err) && err.Code == errorCode
 }
 
+// IsSpecificStatusCode reports whether myError, or any error it wraps, is an
+// APIError with the given HTTP status code.
 func IsSpecificStatusCode(myError error, statusCode int) bool {
-	switch err := errors.Cause(myError).(type) {
-	case *APIError:
-		if err.StatusCode == statusCode {
-			return true
-		}
-	}
-
-	return false
+	var err *APIError
+	return stderrors.As(myError, &err) && err.StatusCode == statusCode
 }
@@ -9,6 +9,8 @@
 package errors
 
 import (
+	stderrors "errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -66,3 +68,67 @@ func TestCheckIsSpecificStatusCode(t *testing.T) {
 		}
 	})
 }
+
+func TestAPIErrorIs(t *testing.T) {
+	err := &APIError{
+		StatusCode: http.StatusNotFound,
+		Code:       "ResourceNotFound",
+		Message:    "Resource Not Found",
+		RequestID:  "b1c9e2b4-e8a5-4d7e-8e25-2a5f0c8bd1a1",
+	}
+
+	t.Run("errors.Is", func(t *testing.T) {
+		wrapped := fmt.Errorf("unable to get machine: %w", errors.Wrap(err, "unable to get"))
+		if !stderrors.Is(wrapped, ErrResourceNotFound) {
+			t.Fatalf("Expected %v to be ErrResourceNotFound", wrapped)
+		}
+		if stderrors.Is(wrapped, ErrInUse) {
+			t.Fatalf("Expected %v not to be ErrInUse", wrapped)
+		}
+		if !IsResourceNotFoundError(wrapped) || !IsStatusNotFoundCode(wrapped) {
+			t.Fatalf("Expected Is helpers to see through %%w wrapping")
+		}
+	})
+
+	t.Run("errors.As", func(t *testing.T) {
+		var apiErr *APIError
+		if !stderrors.As(errors.Wrap(err, "unable to get"), &apiErr) || apiErr != err {
+			t.Fatalf("Expected errors.As to find the APIError")
+		}
+	})
+
+	t.Run("message", func(t *testing.T) {
+		expected := "ResourceNotFound: Resource Not Found (request id: b1c9e2b4-e8a5-4d7e-8e25-2a5f0c8bd1a1)"
+		if err.Error() != expected {
+			t.Fatalf("Expected %q, got %q", expected, err.Error())
+		}
+	})
+}
+
+func TestAPIErrorRetryable(t *testing.T) {
+	tests := []struct {
+		status    int
+		code      string
+		method    string
+		temporary bool
+		retryable bool
+	}{
+		{http.StatusTooManyRequests, "", http.MethodPost, true, true},
+		{http.StatusServiceUnavailable, "ServiceUnavailable", http.MethodPost, true, true},
+		{http.StatusBadGateway, "", http.MethodGet, true, true},
+		{http.StatusGatewayTimeout, "", http.MethodPost, true, false},
+		{http.StatusForbidden, "RequestThrottled", http.MethodPost, true, true},
+		{http.StatusInternalServerError, "InternalError", http.MethodGet, false, false},
+		{http.StatusNotFound, "ResourceNotFound", http.MethodGet, false, false},
+	}
+
+	for _, test := range tests {
+		err := &APIError{StatusCode: test.status, Code: test.code, Method: test.method}
+		if err.Temporary() != test.temporary {
+			t.Errorf("Expected Temporary() of %d %s to be %t", test.status, test.code, test.temporary)
+		}
+		if err.Retryable() != test.retryable {
+			t.Errorf("Expected Retryable() of %s %d %s to be %t", test.method, test.status, test.code, test.retryable)
+		}
+	}
+}
@@ -187,7 +187,7 @@ func main() {
 		Volumes: []compute.InstanceVolume{
 			instanceVolume,
 		},
-		Tags: map[string]string{
+		Tags: map[string]interface{}{
 			"tag1": "value1",
 		},
 	}
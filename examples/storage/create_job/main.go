@@ -9,13 +9,12 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
-	"time"
 
 	"encoding/pem"
 
@@ -91,102 +90,57 @@ func main() {
 		log.Fatalf("NewClient: %v", err)
 	}
 
-	job, err := client.Jobs().Create(context.Background(), &storage.CreateJobInput{
-		Name: "WordCount",
-		Phases: []*storage.JobPhase{
-			{
-				Type: "map",
-				Exec: "wc",
-			},
-			{
-				Type: "reduce",
-				Exec: "awk '{ l += $1; w += $2; c += $3 } END { print l, w, c }'",
-			},
-		},
-	})
-	if err != nil {
-		log.Fatalf("CreateJob: %v", err)
-	}
-
-	fmt.Printf("Job ID: %s\n", job.JobID)
-
-	err = client.Jobs().AddInputs(context.Background(), &storage.AddJobInputsInput{
-		JobID: job.JobID,
-		ObjectPaths: []string{
-			fmt.Sprintf("/%s/stor/books/treasure_island.txt", accountName),
-			fmt.Sprintf("/%s/stor/books/moby_dick.txt", accountName),
-			fmt.Sprintf("/%s/stor/books/huck_finn.txt", accountName),
-			fmt.Sprintf("/%s/stor/books/dracula.txt", accountName),
-		},
-	})
+	input, err := storage.NewJobBuilder("WordCount").
+		Map("wc").
+		Reduce("awk '{ l += $1; w += $2; c += $3 } END { print l, w, c }'").
+		Build()
 	if err != nil {
-		log.Fatalf("AddJobInputs: %v", err)
+		log.Fatalf("NewJobBuilder: %v", err)
 	}
 
-	err = client.Jobs().AddInputs(context.Background(), &storage.AddJobInputsInput{
-		JobID: job.JobID,
-		ObjectPaths: []string{
-			fmt.Sprintf("/%s/stor/books/sherlock_holmes.txt", accountName),
-		},
-	})
-	if err != nil {
-		log.Fatalf("AddJobInputs: %v", err)
-	}
+	inputs := make(chan string)
+	go func() {
+		defer close(inputs)
+		for _, book := range []string{"treasure_island", "moby_dick", "huck_finn", "dracula", "sherlock_holmes"} {
+			inputs <- fmt.Sprintf("/%s/stor/books/%s.txt", accountName, book)
+		}
+	}()
 
-	gjo, err := client.Jobs().Get(context.Background(), &storage.GetJobInput{
-		JobID: job.JobID,
+	job, err := client.Jobs().Run(context.Background(), &storage.RunJobInput{
+		Job:    input,
+		Inputs: inputs,
 	})
 	if err != nil {
-		log.Fatalf("GetJob: %v", err)
+		log.Fatalf("RunJob: %v", err)
 	}
 
-	fmt.Printf("%+v\n", gjo.Job)
-	fmt.Printf("%+v\n", gjo.Job.Stats)
+	fmt.Printf("Job ID: %s\n", job.JobID)
 
-	err = client.Jobs().EndInput(context.Background(), &storage.EndJobInputInput{
+	done, err := client.Jobs().Wait(context.Background(), &storage.WaitForJobInput{
 		JobID: job.JobID,
 	})
 	if err != nil {
-		log.Fatalf("EndJobInput: %v", err)
+		log.Fatalf("WaitForJob: %v", err)
 	}
 
-	jobs, err := client.Jobs().List(context.Background(), &storage.ListJobsInput{})
-	if err != nil {
-		log.Fatalf("ListJobs: %v", err)
-	}
-
-	fmt.Printf("Number of jobs: %d\n", jobs.ResultSetSize)
-	for _, j := range jobs.Jobs {
-		fmt.Printf(" - %s\n", j.ID)
-	}
+	fmt.Printf("%+v\n", done)
+	fmt.Printf("%+v\n", done.Stats)
 
-	gjio, err := client.Jobs().GetInput(context.Background(), &storage.GetJobInputInput{
+	outputs := client.Jobs().Outputs(context.Background(), &storage.GetJobOutputInput{
 		JobID: job.JobID,
 	})
-	if err != nil {
-		log.Fatalf("GetJobInput: %v", err)
-	}
-	defer gjio.Items.Close()
+	defer outputs.Close()
 
-	fmt.Printf("Result set size: %d\n", gjio.ResultSetSize)
-	outputsScanner := bufio.NewScanner(gjio.Items)
-	for outputsScanner.Scan() {
-		fmt.Printf(" - %s\n", outputsScanner.Text())
+	for outputs.Next() {
+		object, err := outputs.Open()
+		if err != nil {
+			log.Fatalf("GetObject: %v", err)
+		}
+		fmt.Printf(" - %s\n", outputs.Path())
+		io.Copy(os.Stdout, object.ObjectReader)
+		object.ObjectReader.Close()
 	}
-
-	time.Sleep(10 * time.Second)
-
-	gjoo, err := client.Jobs().GetOutput(context.Background(), &storage.GetJobOutputInput{
-		JobID: job.JobID,
-	})
-	if err != nil {
+	if err := outputs.Err(); err != nil {
 		log.Fatalf("GetJobOutput: %v", err)
 	}
-	defer gjoo.Items.Close()
-
-	fmt.Printf("Result set size: %d\n", gjoo.ResultSetSize)
-	outputsScanner = bufio.NewScanner(gjoo.Items)
-	for outputsScanner.Scan() {
-		fmt.Printf(" - %s\n", outputsScanner.Text())
-	}
 }
@@ -28,23 +28,19 @@ func newIdentityClient(client *client.Client) *IdentityClient {
 // NewClient returns a new client for working with Identity endpoints and
 // resources within CloudAPI
 func NewClient(config *triton.ClientConfig) (*IdentityClient, error) {
-	// TODO: Utilize config interface within the function itself
-	client, err := client.New(
-		config.TritonURL,
-		config.MantaURL,
-		config.AccountName,
-		config.Signers...,
-	)
+	client, err := client.NewFromConfig(config)
 	if err != nil {
 		return nil, err
 	}
 	return newIdentityClient(client), nil
 }
 
-// SetHeaders allows a consumer of the current client to set custom headers for
-// the next backend HTTP request sent to CloudAPI
+// SetHeader sets custom headers on every subsequent request sent to CloudAPI
+// through this client, replacing any set before. It is safe to call while the
+// client is in use. Use client.ContextWithHeader for the headers of a single
+// request.
 func (c *IdentityClient) SetHeader(header *http.Header) {
-	c.Client.RequestHeader = header
+	c.Client.SetHeader(header)
 }
 
 // Roles returns a Roles client used for accessing functions pertaining to
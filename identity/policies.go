@@ -35,8 +35,9 @@ type ListPoliciesInput struct{}
 func (c *PoliciesClient) List(ctx context.Context, _ *ListPoliciesInput) ([]*Policy, error) {
 	fullPath := path.Join("/", c.client.AccountName, "policies")
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "identity.policies.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
@@ -62,8 +63,9 @@ type GetPolicyInput struct {
 func (c *PoliciesClient) Get(ctx context.Context, input *GetPolicyInput) (*Policy, error) {
 	fullPath := path.Join("/", c.client.AccountName, "policies", input.PolicyID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "identity.policies.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
@@ -89,8 +91,9 @@ type DeletePolicyInput struct {
 func (c *PoliciesClient) Delete(ctx context.Context, input *DeletePolicyInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "policies", input.PolicyID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "identity.policies.delete",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
@@ -115,9 +118,10 @@ type UpdatePolicyInput struct {
 func (c *PoliciesClient) Update(ctx context.Context, input *UpdatePolicyInput) (*Policy, error) {
 	fullPath := path.Join("/", c.client.AccountName, "policies", input.PolicyID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "identity.policies.update",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
@@ -145,9 +149,10 @@ type CreatePolicyInput struct {
 func (c *PoliciesClient) Create(ctx context.Context, input *CreatePolicyInput) (*Policy, error) {
 	fullPath := path.Join("/", c.client.AccountName, "policies")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "identity.policies.create",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if err != nil {
@@ -37,8 +37,9 @@ func (c *RolesClient) List(ctx context.Context, _ *ListRolesInput) ([]*Role, err
 	fullPath := path.Join("/", c.client.AccountName, "roles")
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "identity.roles.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -64,8 +65,9 @@ type GetRoleInput struct {
 func (c *RolesClient) Get(ctx context.Context, input *GetRoleInput) (*Role, error) {
 	fullPath := path.Join("/", c.client.AccountName, "roles", input.RoleID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "identity.roles.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -104,9 +106,10 @@ type CreateRoleInput struct {
 func (c *RolesClient) Create(ctx context.Context, input *CreateRoleInput) (*Role, error) {
 	fullPath := path.Join("/", c.client.AccountName, "roles")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "identity.roles.create",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -149,9 +152,10 @@ type UpdateRoleInput struct {
 func (c *RolesClient) Update(ctx context.Context, input *UpdateRoleInput) (*Role, error) {
 	fullPath := path.Join("/", c.client.AccountName, "roles", input.RoleID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "identity.roles.update",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -177,8 +181,9 @@ type DeleteRoleInput struct {
 func (c *RolesClient) Delete(ctx context.Context, input *DeleteRoleInput) error {
 	fullPath := path.Join("/", c.client.AccountName, "roles", input.RoleID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "identity.roles.delete",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -223,9 +228,10 @@ type SetRoleTagsInput struct {
 func (c *RolesClient) SetRoleTags(ctx context.Context, input *SetRoleTagsInput) (*RoleTags, error) {
 	fullPath := path.Join("/", c.client.AccountName, input.ResourceType, input.ResourceID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodPut,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "identity.roles.set_role_tags",
+		Method:    http.MethodPut,
+		Path:      fullPath,
+		Body:      input,
 	}
 
 	respReader, err := c.client.ExecuteRequest(ctx, reqInputs)
@@ -264,8 +270,9 @@ type GetRoleTagsInput struct {
 func (c *RolesClient) GetRoleTags(ctx context.Context, input *GetRoleTagsInput) (*RoleTags, error) {
 	fullPath := path.Join("/", c.client.AccountName, input.ResourceType, input.ResourceID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "identity.roles.get_role_tags",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 
 	response, err := c.client.ExecuteRequestRaw(ctx, reqInputs)
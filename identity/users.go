@@ -48,8 +48,9 @@ type ListUsersInput struct{}
 func (c *UsersClient) List(ctx context.Context, _ *ListUsersInput) ([]*User, error) {
 	fullPath := path.Join("/", c.Client.AccountName, "users")
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "identity.users.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.Client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -75,8 +76,9 @@ type GetUserInput struct {
 func (c *UsersClient) Get(ctx context.Context, input *GetUserInput) (*User, error) {
 	fullPath := path.Join("/", c.Client.AccountName, "users", input.UserID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "identity.users.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.Client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -102,8 +104,9 @@ type DeleteUserInput struct {
 func (c *UsersClient) Delete(ctx context.Context, input *DeleteUserInput) error {
 	fullPath := path.Join("/", c.Client.AccountName, "users", input.UserID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   fullPath,
+		Operation: "identity.users.delete",
+		Method:    http.MethodDelete,
+		Path:      fullPath,
 	}
 	respReader, err := c.Client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -134,9 +137,10 @@ type CreateUserInput struct {
 func (c *UsersClient) Create(ctx context.Context, input *CreateUserInput) (*User, error) {
 	fullPath := path.Join("/", c.Client.AccountName, "users")
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "identity.users.create",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.Client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -173,9 +177,10 @@ type UpdateUserInput struct {
 func (c *UsersClient) Update(ctx context.Context, input *UpdateUserInput) (*User, error) {
 	fullPath := path.Join("/", c.Client.AccountName, "users", input.UserID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "identity.users.update",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.Client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -218,9 +223,10 @@ func (c *UsersClient) ChangeUserPassword(ctx context.Context, input *ChangeUserP
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "identity.users.change_user_password",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respReader, err := c.Client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -232,11 +238,9 @@ func (c *UsersClient) ChangeUserPassword(ctx context.Context, input *ChangeUserP
 		// not very informative to the user.
 		apiError, ok := pkgerrors.Cause(err).(*errors.APIError)
 		if ok && apiError.Message == "passwordInHistory" {
-			err = &errors.APIError{
-				StatusCode: apiError.StatusCode,
-				Code:       apiError.Code,
-				Message:    "previous password cannot be reused",
-			}
+			rewritten := *apiError
+			rewritten.Message = "previous password cannot be reused"
+			err = &rewritten
 		}
 		return nil, pkgerrors.Wrap(err, "unable to change user password")
 	}
@@ -0,0 +1,139 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+// Package multidc operates on all data centers of a Triton account at once.
+// It builds a compute and network client for every data center returned by
+// CloudAPI, all sharing the signers of a single client.Client.
+package multidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	triton "github.com/joyent/triton-go/v2"
+	"github.com/joyent/triton-go/v2/client"
+	"github.com/joyent/triton-go/v2/compute"
+	"github.com/joyent/triton-go/v2/network"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// MultiDCClient fans out requests to every data center of the account Client
+// authenticates as. Client is also used to list the data centers.
+type MultiDCClient struct {
+	Client *client.Client
+
+	// DataCenterNames restricts the client to the named data centers. All
+	// data centers are used when it is empty.
+	DataCenterNames []string
+
+	mu          sync.Mutex
+	dataCenters []*DataCenterClient
+}
+
+// DataCenterClient holds the clients for one data center.
+type DataCenterClient struct {
+	Name    string
+	URL     string
+	Compute *compute.ComputeClient
+	Network *network.NetworkClient
+}
+
+func newMultiDCClient(client *client.Client) *MultiDCClient {
+	return &MultiDCClient{
+		Client: client,
+	}
+}
+
+// NewClient returns a MultiDCClient for the account of config. Its TritonURL
+// may point at any of the account's data centers.
+func NewClient(config *triton.ClientConfig) (*MultiDCClient, error) {
+	client, err := client.NewFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newMultiDCClient(client), nil
+}
+
+// SetHeader sets header on every subsequent request to all data centers,
+// replacing any set before. It is safe to call while the client is in use.
+func (c *MultiDCClient) SetHeader(header *http.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Client.SetHeader(header)
+	for _, dc := range c.dataCenters {
+		// Compute and Network share the client of the data center.
+		dc.Compute.SetHeader(header)
+	}
+}
+
+// DataCenters returns the clients for the data centers of the account, sorted
+// by name. The data centers are listed on first use and cached.
+func (c *MultiDCClient) DataCenters(ctx context.Context) ([]*DataCenterClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dataCenters != nil {
+		return c.dataCenters, nil
+	}
+
+	dcs, err := (&compute.ComputeClient{Client: c.Client}).Datacenters().List(ctx, &compute.ListDataCentersInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(c.DataCenterNames))
+	for _, name := range c.DataCenterNames {
+		wanted[name] = true
+	}
+
+	dataCenters := make([]*DataCenterClient, 0, len(dcs))
+	for _, dc := range dcs {
+		if len(wanted) > 0 && !wanted[dc.Name] {
+			continue
+		}
+		delete(wanted, dc.Name)
+
+		dcClient, err := c.Client.WithTritonURL(dc.URL)
+		if err != nil {
+			return nil, pkgerrors.Wrapf(err, "unable to create client for data center %q", dc.Name)
+		}
+		dataCenters = append(dataCenters, &DataCenterClient{
+			Name:    dc.Name,
+			URL:     dc.URL,
+			Compute: &compute.ComputeClient{Client: dcClient},
+			Network: &network.NetworkClient{Client: dcClient},
+		})
+	}
+	for _, name := range c.DataCenterNames {
+		if wanted[name] {
+			return nil, fmt.Errorf("data center %q not found", name)
+		}
+	}
+
+	c.dataCenters = dataCenters
+	return dataCenters, nil
+}
+
+// DataCenter returns the clients for the named data center.
+func (c *MultiDCClient) DataCenter(ctx context.Context, name string) (*DataCenterClient, error) {
+	dcs, err := c.DataCenters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dc := range dcs {
+		if dc.Name == name {
+			return dc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("data center %q not found", name)
+}
@@ -0,0 +1,162 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package multidc_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/joyent/triton-go/v2/authentication"
+	"github.com/joyent/triton-go/v2/client"
+	"github.com/joyent/triton-go/v2/compute"
+	"github.com/joyent/triton-go/v2/errors"
+	"github.com/joyent/triton-go/v2/multidc"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// mockDataCenters answers requests for three data centers. The machines of
+// us-west-1 cannot be listed.
+func mockDataCenters(requests *[]string) http.RoundTripper {
+	var mu sync.Mutex
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		*requests = append(*requests, req.URL.Host+req.URL.Path)
+		mu.Unlock()
+
+		switch req.URL.Host + req.URL.Path {
+		case "us-east-1.api.joyent.com/test.user/datacenters":
+			return jsonResponse(http.StatusOK, `{
+				"us-west-1": "https://us-west-1.api.joyent.com",
+				"us-east-1": "https://us-east-1.api.joyent.com",
+				"eu-ams-1": "https://eu-ams-1.api.joyent.com"
+			}`), nil
+		case "us-west-1.api.joyent.com/test.user/machines":
+			return jsonResponse(http.StatusServiceUnavailable, `{"code":"ServiceUnavailable","message":"down"}`), nil
+		}
+
+		if strings.HasSuffix(req.URL.Path, "/machines") {
+			dc := strings.SplitN(req.URL.Host, ".", 2)[0]
+			return jsonResponse(http.StatusOK, `[{"id":"`+dc+`-machine"}]`), nil
+		}
+
+		return jsonResponse(http.StatusNotFound, `{"code":"ResourceNotFound","message":"not found"}`), nil
+	})
+}
+
+func newMultiDCClient(rt http.RoundTripper) *multidc.MultiDCClient {
+	signer, _ := authentication.NewTestSigner()
+	tritonURL, _ := url.Parse("https://us-east-1.api.joyent.com")
+
+	return &multidc.MultiDCClient{
+		Client: &client.Client{
+			HTTPClient:  &http.Client{Transport: rt},
+			Authorizers: []authentication.Signer{signer},
+			TritonURL:   *tritonURL,
+			AccountName: "test.user",
+		},
+	}
+}
+
+func TestListInstances(t *testing.T) {
+	var requests []string
+	c := newMultiDCClient(mockDataCenters(&requests))
+
+	results, err := c.ListInstances(context.Background(), &compute.ListInstancesInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results: got %d", len(results))
+	}
+	for i, name := range []string{"eu-ams-1", "us-east-1", "us-west-1"} {
+		result := results[i]
+		if result.DataCenter != name {
+			t.Errorf("expected result %d to be for %s: got %s", i, name, result.DataCenter)
+		}
+		if name == "us-west-1" {
+			if !errors.IsServiceUnavailableError(result.Err) {
+				t.Errorf("expected ServiceUnavailable error for %s: got %v", name, result.Err)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("expected no error for %s: got %v", name, result.Err)
+		} else if len(result.Instances) != 1 || result.Instances[0].ID != name+"-machine" {
+			t.Errorf("expected the instances of %s: got %+v", name, result.Instances)
+		}
+	}
+
+	if _, err := c.ListInstances(context.Background(), &compute.ListInstancesInput{}); err != nil {
+		t.Fatal(err)
+	}
+	var listed int
+	for _, r := range requests {
+		if strings.HasSuffix(r, "/datacenters") {
+			listed++
+		}
+	}
+	if listed != 1 {
+		t.Errorf("expected data centers to be listed once: got %d", listed)
+	}
+}
+
+func TestDo(t *testing.T) {
+	c := newMultiDCClient(mockDataCenters(new([]string)))
+	c.DataCenterNames = []string{"us-east-1", "us-west-1"}
+
+	var mu sync.Mutex
+	var visited []string
+	err := c.Do(context.Background(), func(ctx context.Context, dc *multidc.DataCenterClient) error {
+		mu.Lock()
+		visited = append(visited, dc.Name)
+		mu.Unlock()
+
+		_, err := dc.Compute.Instances().List(ctx, &compute.ListInstancesInput{})
+		return err
+	})
+
+	if len(visited) != 2 {
+		t.Errorf("expected 2 data centers to be visited: got %v", visited)
+	}
+	dcErrs, ok := err.(multidc.DataCenterErrors)
+	if !ok || len(dcErrs) != 1 || dcErrs["us-west-1"] == nil {
+		t.Fatalf("expected an error for us-west-1 only: got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "us-west-1: ") {
+		t.Errorf("expected the error to name the data center: got %q", err)
+	}
+}
+
+func TestDataCenterNames(t *testing.T) {
+	c := newMultiDCClient(mockDataCenters(new([]string)))
+	c.DataCenterNames = []string{"us-east-1", "ap-south-1"}
+
+	if _, err := c.DataCenters(context.Background()); err == nil || !strings.Contains(err.Error(), "ap-south-1") {
+		t.Errorf("expected unknown data center to be reported: got %v", err)
+	}
+}
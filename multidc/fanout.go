@@ -0,0 +1,159 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package multidc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/joyent/triton-go/v2/compute"
+)
+
+// DataCenterErrors maps the names of data centers to the error an operation
+// failed with there.
+type DataCenterErrors map[string]error
+
+// Error implements interface Error on the DataCenterErrors type.
+func (e DataCenterErrors) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = fmt.Sprintf("%s: %s", name, e[name])
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Do calls fn concurrently for every data center and waits for all calls to
+// return. It returns a DataCenterErrors holding the errors of the failed
+// calls, or nil if all succeeded. An error listing the data centers is
+// returned as is.
+func (c *MultiDCClient) Do(ctx context.Context, fn func(ctx context.Context, dc *DataCenterClient) error) error {
+	dcs, err := c.DataCenters(ctx)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	errs := DataCenterErrors{}
+	each(dcs, func(_ int, dc *DataCenterClient) {
+		if err := fn(ctx, dc); err != nil {
+			mu.Lock()
+			errs[dc.Name] = err
+			mu.Unlock()
+		}
+	})
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// InstancesResult holds the instances of one data center, or the error
+// listing them failed with.
+type InstancesResult struct {
+	DataCenter string
+	Instances  []*compute.Instance
+	Err        error
+}
+
+// ListInstances lists the instances matching input in every data center
+// concurrently. It returns one result per data center, sorted by name.
+func (c *MultiDCClient) ListInstances(ctx context.Context, input *compute.ListInstancesInput) ([]*InstancesResult, error) {
+	dcs, err := c.DataCenters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*InstancesResult, len(dcs))
+	for i, dc := range dcs {
+		results[i] = &InstancesResult{DataCenter: dc.Name}
+	}
+	each(dcs, func(i int, dc *DataCenterClient) {
+		results[i].Instances, results[i].Err = dc.Compute.Instances().List(ctx, input)
+	})
+
+	return results, nil
+}
+
+// ImagesResult holds the images of one data center, or the error listing
+// them failed with.
+type ImagesResult struct {
+	DataCenter string
+	Images     []*compute.Image
+	Err        error
+}
+
+// ListImages lists the images matching input in every data center
+// concurrently. It returns one result per data center, sorted by name.
+func (c *MultiDCClient) ListImages(ctx context.Context, input *compute.ListImagesInput) ([]*ImagesResult, error) {
+	dcs, err := c.DataCenters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ImagesResult, len(dcs))
+	for i, dc := range dcs {
+		results[i] = &ImagesResult{DataCenter: dc.Name}
+	}
+	each(dcs, func(i int, dc *DataCenterClient) {
+		results[i].Images, results[i].Err = dc.Compute.Images().List(ctx, input)
+	})
+
+	return results, nil
+}
+
+// PackagesResult holds the packages of one data center, or the error listing
+// them failed with.
+type PackagesResult struct {
+	DataCenter string
+	Packages   []*compute.Package
+	Err        error
+}
+
+// ListPackages lists the packages matching input in every data center
+// concurrently. It returns one result per data center, sorted by name.
+func (c *MultiDCClient) ListPackages(ctx context.Context, input *compute.ListPackagesInput) ([]*PackagesResult, error) {
+	dcs, err := c.DataCenters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*PackagesResult, len(dcs))
+	for i, dc := range dcs {
+		results[i] = &PackagesResult{DataCenter: dc.Name}
+	}
+	each(dcs, func(i int, dc *DataCenterClient) {
+		results[i].Packages, results[i].Err = dc.Compute.Packages().List(ctx, input)
+	})
+
+	return results, nil
+}
+
+// each calls fn concurrently with every data center and its index in dcs.
+func each(dcs []*DataCenterClient, fn func(i int, dc *DataCenterClient)) {
+	var wg sync.WaitGroup
+	for i, dc := range dcs {
+		wg.Add(1)
+		go func(i int, dc *DataCenterClient) {
+			defer wg.Done()
+			fn(i, dc)
+		}(i, dc)
+	}
+	wg.Wait()
+}
@@ -28,23 +28,19 @@ func newNetworkClient(client *client.Client) *NetworkClient {
 // NewClient returns a new client for working with Network endpoints and
 // resources within CloudAPI
 func NewClient(config *triton.ClientConfig) (*NetworkClient, error) {
-	// TODO: Utilize config interface within the function itself
-	client, err := client.New(
-		config.TritonURL,
-		config.MantaURL,
-		config.AccountName,
-		config.Signers...,
-	)
+	client, err := client.NewFromConfig(config)
 	if err != nil {
 		return nil, err
 	}
 	return newNetworkClient(client), nil
 }
 
-// SetHeaders allows a consumer of the current client to set custom headers for
-// the next backend HTTP request sent to CloudAPI
+// SetHeader sets custom headers on every subsequent request sent to CloudAPI
+// through this client, replacing any set before. It is safe to call while the
+// client is in use. Use client.ContextWithHeader for the headers of a single
+// request.
 func (c *NetworkClient) SetHeader(header *http.Header) {
-	c.Client.RequestHeader = header
+	c.Client.SetHeader(header)
 }
 
 // Fabrics returns a FabricsClient used for accessing functions pertaining to
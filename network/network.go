@@ -38,8 +38,9 @@ type ListInput struct{}
 func (c *NetworkClient) List(ctx context.Context, _ *ListInput) ([]*Network, error) {
 	fullPath := path.Join("/", c.Client.AccountName, "networks")
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "network.networks.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.Client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
@@ -65,8 +66,9 @@ type GetInput struct {
 func (c *NetworkClient) Get(ctx context.Context, input *GetInput) (*Network, error) {
 	fullPath := path.Join("/", c.Client.AccountName, "networks", input.ID)
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "network.networks.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respReader, err := c.Client.ExecuteRequest(ctx, reqInputs)
 	if respReader != nil {
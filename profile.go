@@ -0,0 +1,280 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package triton
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// EnvProfileName is the name of the pseudo-profile built from the TRITON_* and
+// SDC_* environment variables. It is the current profile unless another one
+// was made current.
+const EnvProfileName = "env"
+
+const (
+	profilesDirName  = "profiles.d"
+	configFileName   = "config.json"
+	profileExtension = ".json"
+)
+
+// Profile is a named set of CloudAPI connection settings, stored as
+// <ConfigDir>/profiles.d/<name>.json in the same format as node-triton.
+type Profile struct {
+	Name     string `json:"-"`
+	URL      string `json:"url"`
+	Account  string `json:"account"`
+	KeyID    string `json:"keyId"`
+	Insecure bool   `json:"insecure,omitempty"`
+	User     string `json:"user,omitempty"`
+}
+
+// ClientConfig returns the configuration for connecting to CloudAPI with p.
+// Its Signers are left empty, so that clients look up the key identified by
+// KeyID through their default credential chain.
+func (p *Profile) ClientConfig() *ClientConfig {
+	return &ClientConfig{
+		TritonURL:             p.URL,
+		AccountName:           p.Account,
+		Username:              p.User,
+		KeyID:                 p.KeyID,
+		InsecureSkipTLSVerify: p.Insecure,
+	}
+}
+
+// ConfigDir returns the directory holding profiles and the CLI configuration,
+// TRITON_CONFIG_DIR or ~/.triton by default.
+func ConfigDir() (string, error) {
+	if dir := os.Getenv("TRITON_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "unable to find home directory")
+	}
+
+	return filepath.Join(home, ".triton"), nil
+}
+
+func profilePath(name string) (string, error) {
+	if name == "" || name == EnvProfileName || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid profile name %q", name)
+	}
+
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, profilesDirName, name+profileExtension), nil
+}
+
+// GetProfile reads the profile with the given name. The "env" profile is
+// built from the environment.
+func GetProfile(name string) (*Profile, error) {
+	if name == EnvProfileName {
+		return &Profile{
+			Name:     EnvProfileName,
+			URL:      GetEnv("URL"),
+			Account:  GetEnv("ACCOUNT"),
+			KeyID:    GetEnv("KEY_ID"),
+			Insecure: GetEnv("SKIP_TLS_VERIFY") != "",
+			User:     GetEnv("USER"),
+		}, nil
+	}
+
+	path, err := profilePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no such profile %q", name)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read profile %q", name)
+	}
+
+	profile := &Profile{}
+	if err := json.Unmarshal(data, profile); err != nil {
+		return nil, errors.Wrapf(err, "unable to decode profile %q", name)
+	}
+	profile.Name = name
+
+	return profile, nil
+}
+
+// ListProfiles returns the "env" profile followed by all stored profiles,
+// sorted by name.
+func ListProfiles() ([]*Profile, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, profilesDirName, "*"+profileExtension))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	env, _ := GetProfile(EnvProfileName)
+	profiles := []*Profile{env}
+	for _, path := range paths {
+		profile, err := GetProfile(strings.TrimSuffix(filepath.Base(path), profileExtension))
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+// SaveProfile creates or replaces the stored profile named p.Name.
+func SaveProfile(p *Profile) error {
+	path, err := profilePath(p.Name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(p, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "unable to encode profile")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrap(err, "unable to create profiles directory")
+	}
+	if err := ioutil.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return errors.Wrapf(err, "unable to write profile %q", p.Name)
+	}
+
+	return nil
+}
+
+// DeleteProfile removes the stored profile with the given name. If it was the
+// current profile, "env" becomes current again.
+func DeleteProfile(name string) error {
+	path, err := profilePath(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no such profile %q", name)
+		}
+		return errors.Wrapf(err, "unable to delete profile %q", name)
+	}
+
+	current, err := CurrentProfileName()
+	if err != nil || current != name {
+		return err
+	}
+
+	return SetCurrentProfile(EnvProfileName)
+}
+
+func readConfigFile() (string, map[string]interface{}, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(dir, configFileName)
+
+	config := map[string]interface{}{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return path, config, nil
+	}
+	if err != nil {
+		return "", nil, errors.Wrap(err, "unable to read config")
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", nil, errors.Wrapf(err, "unable to decode %s", path)
+	}
+
+	return path, config, nil
+}
+
+// CurrentProfileName returns the name of the current profile, "env" unless
+// another one was made current with SetCurrentProfile.
+func CurrentProfileName() (string, error) {
+	_, config, err := readConfigFile()
+	if err != nil {
+		return "", err
+	}
+
+	if name, ok := config["profile"].(string); ok && name != "" {
+		return name, nil
+	}
+
+	return EnvProfileName, nil
+}
+
+// SetCurrentProfile makes the profile with the given name current. Other
+// settings of the configuration file are preserved.
+func SetCurrentProfile(name string) error {
+	if _, err := GetProfile(name); err != nil {
+		return err
+	}
+
+	path, config, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	config["profile"] = name
+
+	data, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "unable to encode config")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrap(err, "unable to create config directory")
+	}
+	if err := ioutil.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return errors.Wrap(err, "unable to write config")
+	}
+
+	return nil
+}
+
+// LoadProfile returns the client configuration of the profile with the given
+// name, or of the current profile if name is empty. It lets Go programs share
+// the profiles of the triton CLI:
+//
+//	config, err := triton.LoadProfile("us-east-1")
+//	...
+//	c, err := compute.NewClient(config)
+func LoadProfile(name string) (*ClientConfig, error) {
+	if name == "" {
+		current, err := CurrentProfileName()
+		if err != nil {
+			return nil, err
+		}
+		name = current
+	}
+
+	profile, err := GetProfile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile.ClientConfig(), nil
+}
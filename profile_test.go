@@ -0,0 +1,137 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package triton_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	triton "github.com/joyent/triton-go/v2"
+)
+
+func withConfigDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "triton-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("TRITON_CONFIG_DIR", dir)
+
+	return dir, func() {
+		os.Unsetenv("TRITON_CONFIG_DIR")
+		os.RemoveAll(dir)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	dir, cleanup := withConfigDir(t)
+	defer cleanup()
+
+	east := &triton.Profile{
+		Name:     "us-east-1",
+		URL:      "https://us-east-1.api.joyent.com",
+		Account:  "test.user",
+		KeyID:    "9f:d6:65:fc:d6:60:dc:d0:4e:db:2d:75:f7:92:8c:31",
+		Insecure: true,
+		User:     "operator",
+	}
+	if err := triton.SaveProfile(east); err != nil {
+		t.Fatal(err)
+	}
+
+	// Profiles written by node-triton must be readable too.
+	nodeProfile := `{"url": "https://us-west-1.api.joyent.com", "account": "test.user", "keyId": "aa:bb"}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "profiles.d", "us-west-1.json"), []byte(nodeProfile), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("list", func(t *testing.T) {
+		profiles, err := triton.ListProfiles()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		for _, profile := range profiles {
+			names = append(names, profile.Name)
+		}
+		if len(names) != 3 || names[0] != "env" || names[1] != "us-east-1" || names[2] != "us-west-1" {
+			t.Errorf("expected env, us-east-1 and us-west-1: got %v", names)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		profile, err := triton.GetProfile("us-east-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *profile != *east {
+			t.Errorf("expected %+v: got %+v", east, profile)
+		}
+
+		if _, err := triton.GetProfile("missing"); err == nil {
+			t.Error("expected missing profile to fail")
+		}
+		if _, err := triton.GetProfile("../config"); err == nil {
+			t.Error("expected invalid profile name to fail")
+		}
+	})
+
+	t.Run("current", func(t *testing.T) {
+		os.Setenv("TRITON_URL", "https://env.example.com")
+		defer os.Unsetenv("TRITON_URL")
+
+		config, err := triton.LoadProfile("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.TritonURL != "https://env.example.com" {
+			t.Errorf("expected env profile to be current: got %q", config.TritonURL)
+		}
+
+		if err := triton.SetCurrentProfile("us-west-1"); err != nil {
+			t.Fatal(err)
+		}
+		config, err = triton.LoadProfile("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.TritonURL != "https://us-west-1.api.joyent.com" || config.KeyID != "aa:bb" {
+			t.Errorf("expected us-west-1 to be current: got %+v", config)
+		}
+
+		if err := triton.SetCurrentProfile("missing"); err == nil {
+			t.Error("expected making a missing profile current to fail")
+		}
+	})
+
+	t.Run("load", func(t *testing.T) {
+		config, err := triton.LoadProfile("us-east-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.TritonURL != east.URL || config.AccountName != east.Account ||
+			config.Username != east.User || config.KeyID != east.KeyID || !config.InsecureSkipTLSVerify {
+			t.Errorf("expected config of us-east-1: got %+v", config)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := triton.DeleteProfile("us-west-1"); err != nil {
+			t.Fatal(err)
+		}
+		if current, _ := triton.CurrentProfileName(); current != triton.EnvProfileName {
+			t.Errorf("expected env to become current: got %q", current)
+		}
+		if err := triton.DeleteProfile("us-west-1"); err == nil {
+			t.Error("expected deleting a missing profile to fail")
+		}
+	})
+}
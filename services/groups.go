@@ -40,8 +40,9 @@ type ListGroupsInput struct{}
 
 func (c *GroupsClient) List(ctx context.Context, _ *ListGroupsInput) ([]*ServiceGroup, error) {
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   groupsPath,
+		Operation: "services.groups.list",
+		Method:    http.MethodGet,
+		Path:      groupsPath,
 	}
 	respReader, err := c.client.ExecuteRequestTSG(ctx, reqInputs)
 	if respReader != nil {
@@ -93,8 +94,9 @@ func (c *GroupsClient) ListInstances(ctx context.Context, input *ListGroupInstan
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   path.Join(groupsPath, input.ID, "instances"),
+		Operation: "services.groups.list_instances",
+		Method:    http.MethodGet,
+		Path:      path.Join(groupsPath, input.ID, "instances"),
 	}
 	respReader, err := c.client.ExecuteRequestTSG(ctx, reqInputs)
 	if respReader != nil {
@@ -140,8 +142,9 @@ func (c *GroupsClient) Get(ctx context.Context, input *GetGroupInput) (*ServiceG
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   path.Join(groupsPath, input.ID),
+		Operation: "services.groups.get",
+		Method:    http.MethodGet,
+		Path:      path.Join(groupsPath, input.ID),
 	}
 	respReader, err := c.client.ExecuteRequestTSG(ctx, reqInputs)
 	if respReader != nil {
@@ -190,9 +193,10 @@ func (c *GroupsClient) Create(ctx context.Context, input *CreateGroupInput) (*Se
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   groupsPath,
-		Body:   body,
+		Operation: "services.groups.create",
+		Method:    http.MethodPost,
+		Path:      groupsPath,
+		Body:      body,
 	}
 	respReader, err := c.client.ExecuteRequestTSG(ctx, reqInputs)
 	if respReader != nil {
@@ -247,9 +251,10 @@ func (c *GroupsClient) Update(ctx context.Context, input *UpdateGroupInput) (*Se
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodPut,
-		Path:   path.Join(groupsPath, input.ID),
-		Body:   body,
+		Operation: "services.groups.update",
+		Method:    http.MethodPut,
+		Path:      path.Join(groupsPath, input.ID),
+		Body:      body,
 	}
 	respReader, err := c.client.ExecuteRequestTSG(ctx, reqInputs)
 	if respReader != nil {
@@ -286,8 +291,9 @@ func (c *GroupsClient) Delete(ctx context.Context, input *DeleteGroupInput) erro
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   path.Join(groupsPath, input.ID),
+		Operation: "services.groups.delete",
+		Method:    http.MethodDelete,
+		Path:      path.Join(groupsPath, input.ID),
 	}
 	respReader, err := c.client.ExecuteRequestTSG(ctx, reqInputs)
 	if respReader != nil {
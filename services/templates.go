@@ -43,8 +43,9 @@ type ListTemplatesInput struct{}
 
 func (c *TemplatesClient) List(ctx context.Context, _ *ListTemplatesInput) ([]*InstanceTemplate, error) {
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   templatesPath,
+		Operation: "services.templates.list",
+		Method:    http.MethodGet,
+		Path:      templatesPath,
 	}
 	respReader, err := c.client.ExecuteRequestTSG(ctx, reqInputs)
 	if respReader != nil {
@@ -81,8 +82,9 @@ func (c *TemplatesClient) Get(ctx context.Context, input *GetTemplateInput) (*In
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   path.Join(templatesPath, input.ID),
+		Operation: "services.templates.get",
+		Method:    http.MethodGet,
+		Path:      path.Join(templatesPath, input.ID),
 	}
 	respReader, err := c.client.ExecuteRequestTSG(ctx, reqInputs)
 	if respReader != nil {
@@ -150,9 +152,10 @@ func (input *CreateTemplateInput) toAPI() map[string]interface{} {
 
 func (c *TemplatesClient) Create(ctx context.Context, input *CreateTemplateInput) (*InstanceTemplate, error) {
 	reqInputs := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   templatesPath,
-		Body:   input.toAPI(),
+		Operation: "services.templates.create",
+		Method:    http.MethodPost,
+		Path:      templatesPath,
+		Body:      input.toAPI(),
 	}
 	respReader, err := c.client.ExecuteRequestTSG(ctx, reqInputs)
 	if respReader != nil {
@@ -189,8 +192,9 @@ func (c *TemplatesClient) Delete(ctx context.Context, input *DeleteTemplateInput
 	}
 
 	reqInputs := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   path.Join(templatesPath, input.ID),
+		Operation: "services.templates.delete",
+		Method:    http.MethodDelete,
+		Path:      path.Join(templatesPath, input.ID),
 	}
 	respReader, err := c.client.ExecuteRequestTSG(ctx, reqInputs)
 	if respReader != nil {
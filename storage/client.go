@@ -28,23 +28,19 @@ func newStorageClient(client *client.Client) *StorageClient {
 // NewClient returns a new client for working with Storage endpoints and
 // resources within CloudAPI
 func NewClient(config *triton.ClientConfig) (*StorageClient, error) {
-	// TODO: Utilize config interface within the function itself
-	client, err := client.New(
-		config.TritonURL,
-		config.MantaURL,
-		config.AccountName,
-		config.Signers...,
-	)
+	client, err := client.NewFromConfig(config)
 	if err != nil {
 		return nil, err
 	}
 	return newStorageClient(client), nil
 }
 
-// SetHeader allows a consumer of the current client to set a custom header for
-// the next backend HTTP request sent to CloudAPI.
+// SetHeader sets custom headers on every subsequent request sent to Manta
+// through this client, replacing any set before. It is safe to call while the
+// client is in use. Use client.ContextWithHeader for the headers of a single
+// request.
 func (c *StorageClient) SetHeader(header *http.Header) {
-	c.Client.RequestHeader = header
+	c.Client.SetHeader(header)
 }
 
 // Dir returns a DirectoryClient used for accessing functions pertaining to
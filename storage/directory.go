@@ -12,6 +12,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
@@ -61,9 +62,10 @@ func (s *DirectoryClient) List(ctx context.Context, input *ListDirectoryInput) (
 	}
 
 	reqInput := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   string(absPath),
-		Query:  query,
+		Operation: "storage.directory.list",
+		Method:    http.MethodGet,
+		Path:      string(absPath),
+		Query:     query,
 	}
 	respBody, respHeader, err := s.client.ExecuteRequestStorage(ctx, reqInput)
 	if err != nil {
@@ -98,6 +100,182 @@ func (s *DirectoryClient) List(ctx context.Context, input *ListDirectoryInput) (
 	return output, nil
 }
 
+// maxDirectoryPageSize is the largest number of entries Manta returns for a
+// single directory listing request.
+const maxDirectoryPageSize = 1024
+
+// DirectoryIterator streams the entries of a directory, following Manta's
+// marker-based paging. Use Next to advance it, Entry to read the current
+// entry and Err to check for an error once Next returns false.
+type DirectoryIterator struct {
+	ctx      context.Context
+	client   *DirectoryClient
+	path     _AbsCleanPath
+	marker   string
+	pageSize uint64
+
+	body          io.ReadCloser
+	scanner       *bufio.Scanner
+	pages         int
+	pageEntries   uint64
+	pageNew       uint64
+	resultSetSize uint64
+	current       *DirectoryEntry
+	done          bool
+	err           error
+}
+
+// ListAll returns an iterator over every entry of input.DirectoryName. Pages
+// of input.Limit entries (1024 if unset) are requested on demand, starting at
+// input.Marker, and each page is decoded as it is read from the network
+// rather than buffered. Close must be called if the iterator is abandoned
+// before Next returns false.
+//
+// Since every page after the first repeats the last entry of the previous
+// one, pages hold at least 2 entries.
+func (s *DirectoryClient) ListAll(ctx context.Context, input *ListDirectoryInput) *DirectoryIterator {
+	pageSize := uint64(maxDirectoryPageSize)
+	if input.Limit != 0 && input.Limit < maxDirectoryPageSize {
+		pageSize = input.Limit
+	}
+	if pageSize < 2 {
+		pageSize = 2
+	}
+
+	return &DirectoryIterator{
+		ctx:      ctx,
+		client:   s,
+		path:     absFileInput(s.client.AccountName, input.DirectoryName),
+		marker:   input.Marker,
+		pageSize: pageSize,
+	}
+}
+
+// Next advances the iterator to the next entry, requesting the next page
+// when the current one is exhausted. It returns false when there are no more
+// entries or an error occurred.
+func (it *DirectoryIterator) Next() bool {
+	it.current = nil
+
+	for !it.done {
+		if err := it.ctx.Err(); err != nil {
+			return it.stop(err)
+		}
+
+		if it.scanner == nil {
+			if err := it.open(); err != nil {
+				return it.stop(err)
+			}
+		}
+
+		if !it.scanner.Scan() {
+			if err := it.scanner.Err(); err != nil {
+				return it.stop(errors.Wrap(err, "unable to decode list directories response"))
+			}
+
+			// A short page, or one which only repeated the marker, is the
+			// last one.
+			it.closeBody()
+			if it.pageEntries < it.pageSize || it.pageNew == 0 {
+				return it.stop(nil)
+			}
+			continue
+		}
+
+		entry := &DirectoryEntry{}
+		if err := json.Unmarshal(it.scanner.Bytes(), entry); err != nil {
+			return it.stop(errors.Wrap(err, "unable to decode list directories response"))
+		}
+		it.pageEntries++
+
+		// Every page after the first starts with the last entry of the
+		// previous one.
+		if it.pages > 1 && it.pageEntries == 1 && entry.Name == it.marker {
+			continue
+		}
+		it.pageNew++
+
+		it.current = entry
+		it.marker = entry.Name
+		return true
+	}
+
+	return false
+}
+
+func (it *DirectoryIterator) open() error {
+	query := &url.Values{}
+	query.Set("limit", strconv.FormatUint(it.pageSize, 10))
+	if it.marker != "" {
+		query.Set("marker", it.marker)
+	}
+
+	reqInput := client.RequestInput{
+		Operation: "storage.directory.list",
+		Method:    http.MethodGet,
+		Path:      string(it.path),
+		Query:     query,
+	}
+	respBody, respHeader, err := it.client.client.ExecuteRequestStorage(it.ctx, reqInput)
+	if respBody != nil {
+		it.body = respBody
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to list directory")
+	}
+
+	if it.pages == 0 {
+		resultSetSize, err := strconv.ParseUint(respHeader.Get("Result-Set-Size"), 10, 64)
+		if err == nil {
+			it.resultSetSize = resultSetSize
+		}
+	}
+
+	it.scanner = bufio.NewScanner(respBody)
+	it.pages++
+	it.pageEntries = 0
+	it.pageNew = 0
+
+	return nil
+}
+
+func (it *DirectoryIterator) closeBody() {
+	if it.body != nil {
+		it.body.Close()
+		it.body = nil
+	}
+	it.scanner = nil
+}
+
+func (it *DirectoryIterator) stop(err error) bool {
+	it.err = err
+	it.Close()
+	return false
+}
+
+// Entry returns the directory entry the iterator currently points at.
+func (it *DirectoryIterator) Entry() *DirectoryEntry {
+	return it.current
+}
+
+// ResultSetSize returns the number of entries in the directory as reported
+// by Manta with the first page, or 0 before the first call to Next.
+func (it *DirectoryIterator) ResultSetSize() uint64 {
+	return it.resultSetSize
+}
+
+// Err returns the error which stopped the iterator, if any.
+func (it *DirectoryIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator and releases the underlying response body.
+func (it *DirectoryIterator) Close() error {
+	it.done = true
+	it.closeBody()
+	return nil
+}
+
 // PutDirectoryInput represents parameters to a Put operation.
 type PutDirectoryInput struct {
 	DirectoryName string
@@ -113,9 +291,10 @@ func (s *DirectoryClient) Put(ctx context.Context, input *PutDirectoryInput) err
 	headers.Set("Content-Type", "application/json; type=directory")
 
 	reqInput := client.RequestInput{
-		Method:  http.MethodPut,
-		Path:    string(absPath),
-		Headers: headers,
+		Operation: "storage.directory.put",
+		Method:    http.MethodPut,
+		Path:      string(absPath),
+		Headers:   headers,
 	}
 	respBody, _, err := s.client.ExecuteRequestStorage(ctx, reqInput)
 	if respBody != nil {
@@ -178,8 +357,9 @@ func deleteAll(c DirectoryClient, ctx context.Context, directoryPath _AbsCleanPa
 
 func deleteDirectory(c DirectoryClient, ctx context.Context, directoryPath _AbsCleanPath) error {
 	reqInput := client.RequestInput{
-		Method: http.MethodDelete,
-		Path:   string(directoryPath),
+		Operation: "storage.directory.delete",
+		Method:    http.MethodDelete,
+		Path:      string(directoryPath),
 	}
 	respBody, _, err := c.client.ExecuteRequestStorage(ctx, reqInput)
 	if respBody != nil {
@@ -133,6 +133,134 @@ func TestList(t *testing.T) {
 	})
 }
 
+func TestListAll(t *testing.T) {
+	storageClient := &storage.StorageClient{
+		Client: testutils.NewMockClient(testutils.MockClientInput{
+			AccountName: accountURL,
+		}),
+	}
+
+	do := func(ctx context.Context, sc *storage.StorageClient) ([]string, uint64, error) {
+		defer testutils.DeactivateClient()
+
+		iter := sc.Dir().ListAll(ctx, &storage.ListDirectoryInput{
+			DirectoryName: dirPath,
+			Limit:         2,
+		})
+		defer iter.Close()
+
+		var names []string
+		for iter.Next() {
+			names = append(names, iter.Entry().Name)
+		}
+		return names, iter.ResultSetSize(), iter.Err()
+	}
+
+	pageURL := func(marker string) string {
+		q := url.Values{}
+		q.Set("limit", "2")
+		if marker != "" {
+			q.Set("marker", marker)
+		}
+
+		u := url.URL{}
+		u.Path = path.Join("/", accountURL, dirPath)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		testutils.RegisterResponder("GET", pageURL(""), listDirPage("a", "b"))
+		testutils.RegisterResponder("GET", pageURL("b"), listDirPage("b", "c"))
+		testutils.RegisterResponder("GET", pageURL("c"), listDirPage("c"))
+
+		names, size, err := do(context.Background(), storageClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Join(names, ",") != "a,b,c" {
+			t.Errorf("expected each entry exactly once: got %v", names)
+		}
+
+		if size != 3 {
+			t.Errorf("expected result set size of 3: got %d", size)
+		}
+	})
+
+	t.Run("limit of 1", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		testutils.RegisterResponder("GET", pageURL(""), listDirPage("a", "b"))
+		testutils.RegisterResponder("GET", pageURL("b"), listDirPage("b", "c"))
+		testutils.RegisterResponder("GET", pageURL("c"), listDirPage("c"))
+
+		iter := storageClient.Dir().ListAll(context.Background(), &storage.ListDirectoryInput{
+			DirectoryName: dirPath,
+			Limit:         1,
+		})
+		defer iter.Close()
+
+		var names []string
+		for iter.Next() {
+			names = append(names, iter.Entry().Name)
+		}
+		if err := iter.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Join(names, ",") != "a,b,c" {
+			t.Errorf("expected every entry with pages of 2: got %v", names)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		testutils.RegisterResponder("GET", pageURL(""), listDirPage("a", "b"))
+		testutils.RegisterResponder("GET", pageURL("b"), listDirError)
+
+		names, _, err := do(context.Background(), storageClient)
+		if err == nil {
+			t.Fatal("expected non-nil error, but err was nil")
+		}
+
+		if len(names) != 2 {
+			t.Errorf("expected entries of the first page: got %v", names)
+		}
+
+		if !strings.Contains(err.Error(), "unable to list dir") {
+			t.Errorf("expected error to equal testError: found %v", err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err := do(ctx, storageClient)
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled: found %v", err)
+		}
+	})
+}
+
+func listDirPage(names ...string) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Add("Content-Type", "application/x-json-stream")
+		header.Add("Result-Set-Size", "3")
+
+		var body strings.Builder
+		for _, name := range names {
+			body.WriteString(`{"name":"` + name + `","type":"object","mtime":"2018-01-01T00:00:00.000Z"}` + "\n")
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(body.String())),
+		}, nil
+	}
+}
+
 func listDirSuccess(req *http.Request) (*http.Response, error) {
 	header := http.Header{}
 	header.Add("Content-Type", "application/x-json-stream")
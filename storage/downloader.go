@@ -0,0 +1,427 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package storage
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/joyent/triton-go/v2/client"
+	tt "github.com/joyent/triton-go/v2/errors"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultDownloadPartSize is the size of the byte ranges fetched by a
+	// Downloader unless configured otherwise.
+	DefaultDownloadPartSize = 16 << 20
+
+	defaultDownloadConcurrency     = 4
+	defaultDownloadMaxPartAttempts = 3
+)
+
+// ErrObjectChanged is returned by Download when the object was replaced while
+// it was being downloaded, or no longer has the ETag of the download being
+// resumed.
+var ErrObjectChanged = errors.New("object changed during download")
+
+// Downloader downloads large objects by fetching byte ranges of them
+// concurrently. The zero values of its fields select the defaults.
+type Downloader struct {
+	client *client.Client
+
+	// PartSize is the size of the byte ranges requested.
+	PartSize int64
+
+	// Concurrency is the number of ranges fetched at once. The Downloader
+	// buffers up to twice as many ranges in memory.
+	Concurrency int
+
+	// MaxPartAttempts is the number of attempts made to fetch a range before
+	// the download fails.
+	MaxPartAttempts int
+
+	// Progress is called whenever the data written without gaps from the
+	// start of the object grows. Calls are not concurrent.
+	Progress func(DownloadProgress)
+}
+
+// DownloadInput represents parameters to a Download operation.
+type DownloadInput struct {
+	ObjectPath string
+	Headers    map[string]string
+
+	// Writer receives the object. Ranges are written with concurrent WriteAt
+	// calls, so an *os.File can be used directly.
+	Writer io.WriterAt
+
+	// Offset resumes an interrupted download, whose first Offset bytes were
+	// already written to Writer. It is usually the Offset of a DownloadError
+	// or DownloadProgress.
+	Offset int64
+
+	// ETag is the ETag the object must have, e.g. the one of the download
+	// being resumed.
+	ETag string
+
+	// SkipChecksum disables checking the Content-MD5 of the object.
+	SkipChecksum bool
+}
+
+// DownloadOutput contains the outputs of a Download operation.
+type DownloadOutput struct {
+	ContentLength uint64
+	ContentType   string
+	ContentMD5    string
+	ETag          string
+	LastModified  time.Time
+	Metadata      map[string]string
+
+	// Verified reports whether the data written matched the Content-MD5 of
+	// the object. It cannot be checked when a download is resumed and Writer
+	// is not also an io.ReaderAt, or when Manta did not return a checksum.
+	Verified bool
+}
+
+// DownloadProgress reports the progress of a Download operation.
+type DownloadProgress struct {
+	ETag string
+
+	// Offset counts the bytes written without gaps from the start of the
+	// object. The download can be resumed from it.
+	Offset int64
+
+	// TotalBytes is the size of the object.
+	TotalBytes int64
+}
+
+// Downloader returns a Downloader with default settings, used for
+// downloading large objects in parallel.
+func (c *StorageClient) Downloader() *Downloader {
+	return &Downloader{client: c.Client}
+}
+
+// downloadPartJob is a byte range of the object waiting to be fetched.
+type downloadPartJob struct {
+	num    int
+	offset int64
+	buf    []byte
+}
+
+// download tracks the state of a single Download operation.
+type download struct {
+	*Downloader
+	objects ObjectsClient
+	input   *DownloadInput
+	etag    string
+	total   int64
+	buffers chan []byte
+
+	mu      sync.Mutex
+	hash    hash.Hash
+	next    int
+	offset  int64
+	pending map[int]downloadPartJob
+}
+
+// Download downloads input.ObjectPath into input.Writer. The object's size
+// and ETag are looked up first, and every range is requested with If-Match
+// so that an object replaced meanwhile fails the download with
+// ErrObjectChanged. Other failures return a *DownloadError carrying the
+// offset to resume the download from.
+func (d *Downloader) Download(ctx context.Context, input *DownloadInput) (*DownloadOutput, error) {
+	d = d.withDefaults()
+
+	headers := make(map[string]string, len(input.Headers)+1)
+	for key, value := range input.Headers {
+		headers[key] = value
+	}
+	if input.ETag != "" {
+		headers["If-Match"] = input.ETag
+	}
+	objects := ObjectsClient{d.client}
+	info, err := objects.GetInfo(ctx, &GetInfoInput{
+		ObjectPath: input.ObjectPath,
+		Headers:    headers,
+	})
+	if err != nil {
+		if isObjectChangedError(err) {
+			err = ErrObjectChanged
+		}
+		return nil, errors.Wrap(err, "unable to download object")
+	}
+	if input.ETag != "" && info.ETag != input.ETag {
+		return nil, errors.Wrap(ErrObjectChanged, "unable to download object")
+	}
+	if input.Offset < 0 || uint64(input.Offset) > info.ContentLength {
+		return nil, fmt.Errorf("unable to resume download at offset %d of object of %d bytes", input.Offset, info.ContentLength)
+	}
+
+	dl := &download{
+		Downloader: d,
+		objects:    objects,
+		input:      input,
+		etag:       info.ETag,
+		total:      int64(info.ContentLength),
+		offset:     input.Offset,
+		pending:    map[int]downloadPartJob{},
+	}
+	if !input.SkipChecksum && info.ContentMD5 != "" {
+		if err := dl.startChecksum(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := dl.downloadParts(ctx); err != nil {
+		if isObjectChangedError(err) {
+			return nil, errors.Wrap(ErrObjectChanged, "unable to download object")
+		}
+		return nil, &DownloadError{ETag: dl.etag, Offset: dl.offset, Err: err}
+	}
+
+	output := &DownloadOutput{
+		ContentLength: info.ContentLength,
+		ContentType:   info.ContentType,
+		ContentMD5:    info.ContentMD5,
+		ETag:          info.ETag,
+		LastModified:  info.LastModified,
+		Metadata:      info.Metadata,
+	}
+	if dl.hash != nil {
+		contentMD5 := base64.StdEncoding.EncodeToString(dl.hash.Sum(nil))
+		if contentMD5 != info.ContentMD5 {
+			return nil, fmt.Errorf("unable to download object: content md5 %s does not match %s", contentMD5, info.ContentMD5)
+		}
+		output.Verified = true
+	}
+
+	return output, nil
+}
+
+func (d *Downloader) withDefaults() *Downloader {
+	withDefaults := *d
+	if withDefaults.PartSize <= 0 {
+		withDefaults.PartSize = DefaultDownloadPartSize
+	}
+	if withDefaults.Concurrency <= 0 {
+		withDefaults.Concurrency = defaultDownloadConcurrency
+	}
+	if withDefaults.MaxPartAttempts <= 0 {
+		withDefaults.MaxPartAttempts = defaultDownloadMaxPartAttempts
+	}
+	return &withDefaults
+}
+
+// startChecksum hashes the data already written by the download being
+// resumed. The checksum is skipped if it cannot be read back.
+func (dl *download) startChecksum() error {
+	dl.hash = md5.New()
+	if dl.offset == 0 {
+		return nil
+	}
+
+	r, ok := dl.input.Writer.(io.ReaderAt)
+	if !ok {
+		dl.hash = nil
+		return nil
+	}
+	if _, err := io.Copy(dl.hash, io.NewSectionReader(r, 0, dl.offset)); err != nil {
+		return errors.Wrap(err, "unable to resume download")
+	}
+	return nil
+}
+
+// downloadParts fetches the rest of the object in ranges concurrently.
+func (dl *download) downloadParts(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	dl.buffers = make(chan []byte, 2*dl.Concurrency)
+	for i := 0; i < cap(dl.buffers); i++ {
+		dl.buffers <- nil
+	}
+	jobs := make(chan downloadPartJob)
+
+	var errOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < dl.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if err := dl.downloadPart(ctx, job); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	// Buffers are taken in the order of the ranges and only returned once
+	// all preceding ranges are written, which keeps the range being waited
+	// for in flight.
+	offset := dl.offset
+	for num := 0; offset < dl.total; num++ {
+		var buf []byte
+		select {
+		case buf = <-dl.buffers:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		size := dl.PartSize
+		if remaining := dl.total - offset; remaining < size {
+			size = remaining
+		}
+		if int64(cap(buf)) < size {
+			buf = make([]byte, size)
+		}
+
+		jobs <- downloadPartJob{num: num, offset: offset, buf: buf[:size]}
+		offset += size
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// downloadPart fetches a range and writes it, retrying failed attempts.
+func (dl *download) downloadPart(ctx context.Context, job downloadPartJob) error {
+	var err error
+	for attempt := 1; attempt <= dl.MaxPartAttempts; attempt++ {
+		if attempt > 1 {
+			if err := sleepPartBackoff(ctx, attempt); err != nil {
+				return err
+			}
+		}
+
+		err = dl.fetchPart(ctx, job)
+		if err == nil || ctx.Err() != nil || isObjectChangedError(err) || !isRetryablePartError(err) {
+			break
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to download bytes %d-%d", job.offset, job.offset+int64(len(job.buf))-1)
+	}
+
+	if _, err := dl.input.Writer.WriteAt(job.buf, job.offset); err != nil {
+		return errors.Wrap(err, "unable to write object")
+	}
+	dl.done(job)
+	return nil
+}
+
+// fetchPart reads the range of job into its buffer.
+func (dl *download) fetchPart(ctx context.Context, job downloadPartJob) error {
+	headers := make(map[string]string, len(dl.input.Headers)+2)
+	for key, value := range dl.input.Headers {
+		headers[key] = value
+	}
+	headers["Range"] = fmt.Sprintf("bytes=%d-%d", job.offset, job.offset+int64(len(job.buf))-1)
+	if dl.etag != "" {
+		headers["If-Match"] = dl.etag
+	}
+
+	output, err := dl.objects.Get(ctx, &GetObjectInput{
+		ObjectPath: dl.input.ObjectPath,
+		Headers:    headers,
+	})
+	if err != nil {
+		return err
+	}
+	defer output.ObjectReader.Close()
+
+	if output.ContentLength != uint64(len(job.buf)) {
+		return fmt.Errorf("expected %d bytes, got %d", len(job.buf), output.ContentLength)
+	}
+	if dl.etag != "" && output.ETag != "" && output.ETag != dl.etag {
+		return ErrObjectChanged
+	}
+	_, err = io.ReadFull(output.ObjectReader, job.buf)
+	return err
+}
+
+// done hashes the ranges written without gaps from the start of the object,
+// returns their buffers and reports the progress.
+func (dl *download) done(job downloadPartJob) {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	dl.pending[job.num] = job
+	for {
+		next, ok := dl.pending[dl.next]
+		if !ok {
+			return
+		}
+		delete(dl.pending, dl.next)
+		dl.next++
+
+		if dl.hash != nil {
+			dl.hash.Write(next.buf)
+		}
+		dl.offset += int64(len(next.buf))
+		dl.buffers <- next.buf
+
+		if dl.Progress != nil {
+			dl.Progress(DownloadProgress{
+				ETag:       dl.etag,
+				Offset:     dl.offset,
+				TotalBytes: dl.total,
+			})
+		}
+	}
+}
+
+func isObjectChangedError(err error) bool {
+	return errors.Is(err, ErrObjectChanged) ||
+		tt.IsSpecificStatusCode(err, http.StatusPreconditionFailed)
+}
+
+// DownloadError is returned by Download when a download failed part way, so
+// that it can be resumed with DownloadInput.Offset and DownloadInput.ETag.
+type DownloadError struct {
+	ETag   string
+	Offset int64
+	Err    error
+}
+
+// Error implements interface Error on the DownloadError type.
+func (e *DownloadError) Error() string {
+	return fmt.Sprintf("unable to download object (resume at offset %d): %s", e.Offset, e.Err)
+}
+
+// Unwrap makes DownloadError compatible with errors.Is and errors.As.
+func (e *DownloadError) Unwrap() error {
+	return e.Err
+}
+
+// Cause makes DownloadError compatible with errors.Cause.
+func (e *DownloadError) Cause() error {
+	return e.Err
+}
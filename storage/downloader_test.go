@@ -0,0 +1,264 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package storage_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/joyent/triton-go/v2/storage"
+	"github.com/joyent/triton-go/v2/testutils"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	downloadObjectPath = "/stor/artifact.tgz"
+	downloadData       = "0123456789"
+	downloadETag       = "b2f1d2a5-8f7e-4c43-a0b4-1b3b5e0a6c1f"
+)
+
+// writerAt is an in-memory io.WriterAt and io.ReaderAt.
+type writerAt struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *writerAt) WriteAt(p []byte, off int64) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if end := int(off) + len(p); end > len(w.buf) {
+		w.buf = append(w.buf, make([]byte, end-len(w.buf))...)
+	}
+	return copy(w.buf[off:], p), nil
+}
+
+func (w *writerAt) ReadAt(p []byte, off int64) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return copy(p, w.buf[off:]), nil
+}
+
+// mockDownload serves downloadData, recording the ranges requested.
+type mockDownload struct {
+	mu       sync.Mutex
+	data     string
+	etag     string
+	ranges   []string
+	attempts map[string]int
+}
+
+// register registers responders for the object. fail decides the status of
+// range requests.
+func (m *mockDownload) register(t *testing.T, fail func(rng string, attempt int) int) {
+	objectPath := path.Join("/", accountURL, downloadObjectPath)
+	sum := md5.Sum([]byte(downloadData))
+	contentMD5 := base64.StdEncoding.EncodeToString(sum[:])
+
+	testutils.RegisterResponder("HEAD", objectPath, func(req *http.Request) (*http.Response, error) {
+		if match := req.Header.Get("If-Match"); match != "" && match != m.etag {
+			return &http.Response{StatusCode: http.StatusPreconditionFailed, Header: http.Header{}, Body: http.NoBody}, nil
+		}
+		header := http.Header{}
+		header.Set("Content-Length", strconv.Itoa(len(m.data)))
+		header.Set("Content-MD5", contentMD5)
+		header.Set("Etag", downloadETag)
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: http.NoBody}, nil
+	})
+
+	testutils.RegisterResponder("GET", objectPath, func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("If-Match") != m.etag {
+			return jsonResponse(http.StatusPreconditionFailed, `{"code":"PreconditionFailed","message":"if-match does not match"}`), nil
+		}
+
+		rng := req.Header.Get("Range")
+		var start, end int
+		if _, err := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end); err != nil {
+			t.Errorf("unexpected range %q", rng)
+		}
+
+		m.mu.Lock()
+		m.ranges = append(m.ranges, rng)
+		m.attempts[rng]++
+		status := fail(rng, m.attempts[rng])
+		m.mu.Unlock()
+		if status != 0 {
+			return jsonResponse(status, `{"code":"InternalError","message":"failed"}`), nil
+		}
+
+		body := m.data[start : end+1]
+		header := http.Header{}
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+		header.Set("Etag", m.etag)
+		return &http.Response{
+			StatusCode: http.StatusPartialContent,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+}
+
+func newMockDownload() *mockDownload {
+	return &mockDownload{
+		data:     downloadData,
+		etag:     downloadETag,
+		attempts: map[string]int{},
+	}
+}
+
+func TestDownload(t *testing.T) {
+	newDownloader := func(progress func(storage.DownloadProgress)) *storage.Downloader {
+		downloader := MockStorageClient().Downloader()
+		downloader.PartSize = 4
+		downloader.Concurrency = 2
+		downloader.Progress = progress
+		return downloader
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockDownload()
+		m.register(t, func(rng string, attempt int) int { return 0 })
+
+		var progress []storage.DownloadProgress
+		w := &writerAt{}
+		output, err := newDownloader(func(p storage.DownloadProgress) {
+			progress = append(progress, p)
+		}).Download(context.Background(), &storage.DownloadInput{
+			ObjectPath: downloadObjectPath,
+			Writer:     w,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(w.buf) != downloadData {
+			t.Errorf("expected %q to be written: got %q", downloadData, w.buf)
+		}
+		if !output.Verified || output.ETag != downloadETag {
+			t.Errorf("expected a verified download: got %+v", output)
+		}
+		if len(m.ranges) != 3 {
+			t.Errorf("expected the object to be fetched in 3 ranges: got %v", m.ranges)
+		}
+		if len(progress) != 3 || progress[2].Offset != int64(len(downloadData)) {
+			t.Errorf("expected progress for every range: got %+v", progress)
+		}
+	})
+
+	t.Run("retries failed ranges", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockDownload()
+		m.register(t, func(rng string, attempt int) int {
+			if rng == "bytes=4-7" && attempt == 1 {
+				return http.StatusServiceUnavailable
+			}
+			return 0
+		})
+
+		w := &writerAt{}
+		if _, err := newDownloader(nil).Download(context.Background(), &storage.DownloadInput{
+			ObjectPath: downloadObjectPath,
+			Writer:     w,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if m.attempts["bytes=4-7"] != 2 || string(w.buf) != downloadData {
+			t.Errorf("expected range 4-7 to be fetched twice: got %v, %q", m.attempts, w.buf)
+		}
+	})
+
+	t.Run("reports offset on failure", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockDownload()
+		m.register(t, func(rng string, attempt int) int {
+			if rng == "bytes=8-9" {
+				return http.StatusForbidden
+			}
+			return 0
+		})
+
+		_, err := newDownloader(nil).Download(context.Background(), &storage.DownloadInput{
+			ObjectPath: downloadObjectPath,
+			Writer:     &writerAt{},
+		})
+		var downloadErr *storage.DownloadError
+		if !pkgerrors.As(err, &downloadErr) || downloadErr.ETag != downloadETag {
+			t.Fatalf("expected a DownloadError: got %v", err)
+		}
+		if downloadErr.Offset > 8 {
+			t.Errorf("expected offset before the failed range: got %d", downloadErr.Offset)
+		}
+	})
+
+	t.Run("resumes", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockDownload()
+		m.register(t, func(rng string, attempt int) int { return 0 })
+
+		w := &writerAt{buf: []byte(downloadData[:6])}
+		output, err := newDownloader(nil).Download(context.Background(), &storage.DownloadInput{
+			ObjectPath: downloadObjectPath,
+			Writer:     w,
+			Offset:     6,
+			ETag:       downloadETag,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Join(m.ranges, ",") != "bytes=6-9" {
+			t.Errorf("expected only the rest of the object to be fetched: got %v", m.ranges)
+		}
+		if !output.Verified || string(w.buf) != downloadData {
+			t.Errorf("expected a verified download: got %+v, %q", output, w.buf)
+		}
+	})
+
+	t.Run("detects changed object", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockDownload()
+		m.register(t, func(rng string, attempt int) int { return 0 })
+
+		_, err := newDownloader(nil).Download(context.Background(), &storage.DownloadInput{
+			ObjectPath: downloadObjectPath,
+			Writer:     &writerAt{},
+			Offset:     4,
+			ETag:       "a-previous-etag",
+		})
+		if !pkgerrors.Is(err, storage.ErrObjectChanged) {
+			t.Errorf("expected ErrObjectChanged: got %v", err)
+		}
+	})
+
+	t.Run("detects corrupted object", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockDownload()
+		m.register(t, func(rng string, attempt int) int { return 0 })
+		m.data = "0123456780"
+
+		_, err := newDownloader(nil).Download(context.Background(), &storage.DownloadInput{
+			ObjectPath: downloadObjectPath,
+			Writer:     &writerAt{},
+		})
+		if err == nil || !strings.Contains(err.Error(), "content md5") {
+			t.Errorf("expected a checksum error: got %v", err)
+		}
+	})
+}
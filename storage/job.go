@@ -110,9 +110,10 @@ func (s *JobClient) Create(ctx context.Context, input *CreateJobInput) (*CreateJ
 	fullPath := path.Join("/", s.client.AccountName, "jobs")
 
 	reqInput := client.RequestInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
-		Body:   input,
+		Operation: "storage.job.create",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Body:      input,
 	}
 	respBody, respHeaders, err := s.client.ExecuteRequestStorage(ctx, reqInput)
 	if respBody != nil {
@@ -148,10 +149,11 @@ func (s *JobClient) AddInputs(ctx context.Context, input *AddJobInputsInput) err
 	reader := strings.NewReader(strings.Join(input.ObjectPaths, "\n"))
 
 	reqInput := client.RequestNoEncodeInput{
-		Method:  http.MethodPost,
-		Path:    fullPath,
-		Headers: headers,
-		Body:    reader,
+		Operation: "storage.job.add_inputs",
+		Method:    http.MethodPost,
+		Path:      fullPath,
+		Headers:   headers,
+		Body:      reader,
 	}
 	respBody, _, err := s.client.ExecuteRequestNoEncode(ctx, reqInput)
 	if respBody != nil {
@@ -174,8 +176,9 @@ func (s *JobClient) EndInput(ctx context.Context, input *EndJobInputInput) error
 	fullPath := path.Join("/", s.client.AccountName, "jobs", input.JobID, "live", "in", "end")
 
 	reqInput := client.RequestNoEncodeInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
+		Operation: "storage.job.end_input",
+		Method:    http.MethodPost,
+		Path:      fullPath,
 	}
 	respBody, _, err := s.client.ExecuteRequestNoEncode(ctx, reqInput)
 	if respBody != nil {
@@ -205,8 +208,9 @@ func (s *JobClient) Cancel(ctx context.Context, input *CancelJobInput) error {
 	fullPath := path.Join("/", s.client.AccountName, "jobs", input.JobID, "live", "cancel")
 
 	reqInput := client.RequestNoEncodeInput{
-		Method: http.MethodPost,
-		Path:   fullPath,
+		Operation: "storage.job.cancel",
+		Method:    http.MethodPost,
+		Path:      fullPath,
 	}
 	respBody, _, err := s.client.ExecuteRequestNoEncode(ctx, reqInput)
 	if respBody != nil {
@@ -247,9 +251,10 @@ func (s *JobClient) List(ctx context.Context, input *ListJobsInput) (*ListJobsOu
 	}
 
 	reqInput := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
-		Query:  query,
+		Operation: "storage.job.list",
+		Method:    http.MethodGet,
+		Path:      fullPath,
+		Query:     query,
 	}
 	respBody, respHeader, err := s.client.ExecuteRequestStorage(ctx, reqInput)
 	if respBody != nil {
@@ -299,8 +304,9 @@ func (s *JobClient) Get(ctx context.Context, input *GetJobInput) (*GetJobOutput,
 	fullPath := path.Join("/", s.client.AccountName, "jobs", input.JobID, "live", "status")
 
 	reqInput := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "storage.job.get",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respBody, _, err := s.client.ExecuteRequestStorage(ctx, reqInput)
 	if respBody != nil {
@@ -340,14 +346,15 @@ func (s *JobClient) GetOutput(ctx context.Context, input *GetJobOutputInput) (*G
 	fullPath := path.Join("/", s.client.AccountName, "jobs", input.JobID, "live", "out")
 
 	reqInput := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "storage.job.get_output",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respBody, respHeader, err := s.client.ExecuteRequestStorage(ctx, reqInput)
-	if respBody != nil {
-		defer respBody.Close()
-	}
 	if err != nil {
+		if respBody != nil {
+			respBody.Close()
+		}
 		return nil, errors.Wrap(err, "unable to get job output")
 	}
 
@@ -382,14 +389,15 @@ func (s *JobClient) GetInput(ctx context.Context, input *GetJobInputInput) (*Get
 	fullPath := path.Join("/", s.client.AccountName, "jobs", input.JobID, "live", "in")
 
 	reqInput := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "storage.job.get_input",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respBody, respHeader, err := s.client.ExecuteRequestStorage(ctx, reqInput)
-	if respBody != nil {
-		defer respBody.Close()
-	}
 	if err != nil {
+		if respBody != nil {
+			respBody.Close()
+		}
 		return nil, errors.Wrap(err, "unable to get job input")
 	}
 
@@ -424,14 +432,15 @@ func (s *JobClient) GetFailures(ctx context.Context, input *GetJobFailuresInput)
 	fullPath := path.Join("/", s.client.AccountName, "jobs", input.JobID, "live", "fail")
 
 	reqInput := client.RequestInput{
-		Method: http.MethodGet,
-		Path:   fullPath,
+		Operation: "storage.job.get_failures",
+		Method:    http.MethodGet,
+		Path:      fullPath,
 	}
 	respBody, respHeader, err := s.client.ExecuteRequestStorage(ctx, reqInput)
-	if respBody != nil {
-		defer respBody.Close()
-	}
 	if err != nil {
+		if respBody != nil {
+			respBody.Close()
+		}
 		return nil, errors.Wrap(err, "unable to get job failures")
 	}
 
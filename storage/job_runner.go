@@ -0,0 +1,519 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package storage
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	tt "github.com/joyent/triton-go/v2/errors"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultJobInputBatchSize is the number of object paths submitted to a
+	// job with a single request unless configured otherwise.
+	DefaultJobInputBatchSize = 1000
+
+	maxJobReducerCount = 1024
+
+	defaultJobPollInterval    = 1 * time.Second
+	defaultJobMaxPollInterval = 30 * time.Second
+)
+
+var (
+	// jobMemorySizes are the amounts of DRAM in MB accepted as the Memory
+	// of a phase.
+	jobMemorySizes = []uint64{256, 512, 1024, 2048, 4096, 8192}
+
+	// jobDiskSizes are the amounts of disk space in GB accepted as the Disk
+	// of a phase.
+	jobDiskSizes = []uint64{2, 4, 8, 16, 32, 64, 128, 256, 512, 1024}
+)
+
+// Validate checks the phase against the values accepted by Manta. The zero
+// values of Type, ReducerCount, Memory and Disk select the Manta defaults
+// and are valid.
+func (p *JobPhase) Validate() error {
+	switch p.Type {
+	case "", "map", "reduce":
+	default:
+		return errors.Errorf("invalid phase type %q: must be map or reduce", p.Type)
+	}
+
+	if strings.TrimSpace(p.Exec) == "" {
+		return errors.New("missing exec")
+	}
+	if p.Memory != 0 && !containsSize(jobMemorySizes, p.Memory) {
+		return errors.Errorf("invalid memory %d MB: must be one of %v", p.Memory, jobMemorySizes)
+	}
+	if p.Disk != 0 && !containsSize(jobDiskSizes, p.Disk) {
+		return errors.Errorf("invalid disk %d GB: must be one of %v", p.Disk, jobDiskSizes)
+	}
+	if p.ReducerCount != 0 {
+		if p.Type != "reduce" {
+			return errors.New("reducer count is only valid for reduce phases")
+		}
+		if p.ReducerCount > maxJobReducerCount {
+			return errors.Errorf("invalid reducer count %d: must be at most %d", p.ReducerCount, maxJobReducerCount)
+		}
+	}
+
+	return nil
+}
+
+func containsSize(sizes []uint64, size uint64) bool {
+	for _, s := range sizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+// JobBuilder constructs the input of a CreateJob operation one phase at a
+// time, e.g.
+//
+//	input, err := storage.NewJobBuilder("rollup").
+//		Map("grep -c ERROR").Memory(1024).
+//		Reduce("awk '{ n += $1 } END { print n }'").
+//		Build()
+//
+// Memory, Disk, ReducerCount, Init and Assets configure the phase added
+// last. Mistakes are recorded and reported by Build.
+type JobBuilder struct {
+	name   string
+	phases []*JobPhase
+	err    error
+}
+
+// NewJobBuilder returns a JobBuilder for a job named name.
+func NewJobBuilder(name string) *JobBuilder {
+	return &JobBuilder{name: name}
+}
+
+// Map adds a map phase running exec.
+func (b *JobBuilder) Map(exec string) *JobBuilder {
+	b.phases = append(b.phases, &JobPhase{Type: "map", Exec: exec})
+	return b
+}
+
+// Reduce adds a reduce phase running exec.
+func (b *JobBuilder) Reduce(exec string) *JobBuilder {
+	b.phases = append(b.phases, &JobPhase{Type: "reduce", Exec: exec})
+	return b
+}
+
+// Init sets the shell statement run in each compute zone of the last phase
+// before any tasks.
+func (b *JobBuilder) Init(init string) *JobBuilder {
+	if phase := b.last("init"); phase != nil {
+		phase.Init = init
+	}
+	return b
+}
+
+// Assets adds objects to be placed in the compute zones of the last phase.
+func (b *JobBuilder) Assets(objectPaths ...string) *JobBuilder {
+	if phase := b.last("assets"); phase != nil {
+		phase.Assets = append(phase.Assets, objectPaths...)
+	}
+	return b
+}
+
+// Memory sets the DRAM in MB of the compute zones of the last phase.
+func (b *JobBuilder) Memory(mb uint64) *JobBuilder {
+	if phase := b.last("memory"); phase != nil {
+		phase.Memory = mb
+	}
+	return b
+}
+
+// Disk sets the disk space in GB of the compute zones of the last phase.
+func (b *JobBuilder) Disk(gb uint64) *JobBuilder {
+	if phase := b.last("disk"); phase != nil {
+		phase.Disk = gb
+	}
+	return b
+}
+
+// ReducerCount sets the number of reducers of the last phase, which must be
+// a reduce phase.
+func (b *JobBuilder) ReducerCount(count uint) *JobBuilder {
+	if phase := b.last("reducer count"); phase != nil {
+		phase.ReducerCount = count
+	}
+	return b
+}
+
+// last returns the phase added last, recording an error if there is none.
+func (b *JobBuilder) last(option string) *JobPhase {
+	if len(b.phases) == 0 {
+		if b.err == nil {
+			b.err = errors.Errorf("%s set before adding a phase", option)
+		}
+		return nil
+	}
+	return b.phases[len(b.phases)-1]
+}
+
+// Build validates the job and returns it as the input of a CreateJob
+// operation.
+func (b *JobBuilder) Build() (*CreateJobInput, error) {
+	if b.err != nil {
+		return nil, errors.Wrap(b.err, "unable to build job")
+	}
+	if err := validatePhases(b.phases); err != nil {
+		return nil, errors.Wrap(err, "unable to build job")
+	}
+
+	return &CreateJobInput{
+		Name:   b.name,
+		Phases: b.phases,
+	}, nil
+}
+
+func validatePhases(phases []*JobPhase) error {
+	if len(phases) == 0 {
+		return errors.New("missing phases")
+	}
+	for i, phase := range phases {
+		if err := phase.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid phase %d", i)
+		}
+	}
+	return nil
+}
+
+// RunJobInput represents parameters to a Run operation.
+type RunJobInput struct {
+	Job *CreateJobInput
+
+	// Inputs delivers the object paths to process. The input of the job is
+	// ended once the channel is closed.
+	Inputs <-chan string
+
+	// BatchSize is the largest number of object paths submitted with a
+	// single request, DefaultJobInputBatchSize if unset.
+	BatchSize int
+}
+
+// Run validates and creates a job, then submits the object paths received
+// from input.Inputs until the channel is closed. It does not wait for the
+// job to finish; use Wait for that. If submitting the inputs fails the job
+// is cancelled.
+func (s *JobClient) Run(ctx context.Context, input *RunJobInput) (*CreateJobOutput, error) {
+	if input.Job == nil {
+		return nil, errors.New("unable to run job: missing job")
+	}
+	if err := validatePhases(input.Job.Phases); err != nil {
+		return nil, errors.Wrap(err, "unable to run job")
+	}
+
+	job, err := s.Create(ctx, input.Job)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to run job")
+	}
+
+	err = s.SubmitInputs(ctx, &SubmitJobInputsInput{
+		JobID:     job.JobID,
+		Inputs:    input.Inputs,
+		BatchSize: input.BatchSize,
+	})
+	if err != nil {
+		// The context may be what failed, so cancel with a fresh one.
+		s.Cancel(context.Background(), &CancelJobInput{JobID: job.JobID})
+		return nil, errors.Wrapf(err, "unable to run job %s", job.JobID)
+	}
+
+	return job, nil
+}
+
+// SubmitJobInputsInput represents parameters to a SubmitInputs operation.
+type SubmitJobInputsInput struct {
+	JobID string
+
+	// Inputs delivers the object paths to add to the job. The input of the
+	// job is ended once the channel is closed.
+	Inputs <-chan string
+
+	// BatchSize is the largest number of object paths submitted with a
+	// single request, DefaultJobInputBatchSize if unset.
+	BatchSize int
+}
+
+// SubmitInputs streams the object paths received from input.Inputs to a job
+// and ends its input once the channel is closed. Paths already waiting on
+// the channel are submitted together, up to input.BatchSize at a time, so a
+// slow producer sees its inputs submitted promptly while a fast one is not
+// slowed down by a request per path.
+func (s *JobClient) SubmitInputs(ctx context.Context, input *SubmitJobInputsInput) error {
+	batchSize := input.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultJobInputBatchSize
+	}
+
+	for closed := false; !closed; {
+		var batch []string
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "unable to submit job inputs")
+		case objectPath, ok := <-input.Inputs:
+			if !ok {
+				closed = true
+				break
+			}
+			batch = appendJobInput(batch, objectPath)
+		}
+
+	drain:
+		for !closed && len(batch) < batchSize {
+			select {
+			case objectPath, ok := <-input.Inputs:
+				if !ok {
+					closed = true
+					break drain
+				}
+				batch = appendJobInput(batch, objectPath)
+			default:
+				break drain
+			}
+		}
+
+		if len(batch) > 0 {
+			err := s.AddInputs(ctx, &AddJobInputsInput{
+				JobID:       input.JobID,
+				ObjectPaths: batch,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.EndInput(ctx, &EndJobInputInput{JobID: input.JobID})
+}
+
+func appendJobInput(batch []string, objectPath string) []string {
+	if objectPath = strings.TrimSpace(objectPath); objectPath != "" {
+		batch = append(batch, objectPath)
+	}
+	return batch
+}
+
+// WaitForJobInput represents parameters to a Wait operation. The delay
+// between two polls starts at PollInterval (1s if unset) and doubles after
+// every poll until it reaches MaxPollInterval (30s if unset).
+type WaitForJobInput struct {
+	JobID           string
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+}
+
+// Wait polls a job until it is done and returns the job as last seen.
+// Cancelled jobs are done as well, so check Cancelled and Stats.Errors of
+// the job to learn how it went.
+func (s *JobClient) Wait(ctx context.Context, input *WaitForJobInput) (*Job, error) {
+	interval := input.PollInterval
+	if interval <= 0 {
+		interval = defaultJobPollInterval
+	}
+	maxInterval := input.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultJobMaxPollInterval
+	}
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+
+	for {
+		output, err := s.Get(ctx, &GetJobInput{JobID: input.JobID})
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to wait for job")
+		}
+		if output.Job.State == JobStateDone {
+			return output.Job, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Wrap(ctx.Err(), "unable to wait for job")
+		case <-timer.C:
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+// JobObjectIterator streams the object paths listed by a job, such as its
+// outputs or the inputs which failed. Use Next to advance it, Path to read
+// the current path, Open to read the current object and Err to check for an
+// error once Next returns false.
+type JobObjectIterator struct {
+	ctx    context.Context
+	client *JobClient
+	list   func() (io.ReadCloser, uint64, error)
+
+	// archived is the object listing the paths once the job is archived.
+	archived string
+
+	body          io.ReadCloser
+	scanner       *bufio.Scanner
+	resultSetSize uint64
+	current       string
+	done          bool
+	err           error
+}
+
+// Outputs returns an iterator over the output objects of a job. Outputs are
+// listed as they are produced while the job runs, and from the archived
+// out.txt of the job once Manta has archived it. Close must be called if
+// the iterator is abandoned before Next returns false.
+func (s *JobClient) Outputs(ctx context.Context, input *GetJobOutputInput) *JobObjectIterator {
+	return &JobObjectIterator{
+		ctx:    ctx,
+		client: s,
+		list: func() (io.ReadCloser, uint64, error) {
+			output, err := s.GetOutput(ctx, input)
+			if err != nil {
+				return nil, 0, err
+			}
+			return output.Items, output.ResultSetSize, nil
+		},
+		archived: path.Join("/", s.client.AccountName, "jobs", input.JobID, "out.txt"),
+	}
+}
+
+// Failures returns an iterator over the objects of a job whose tasks
+// failed, listed from the archived fail.txt of the job once Manta has
+// archived it. Close must be called if the iterator is abandoned before Next
+// returns false.
+func (s *JobClient) Failures(ctx context.Context, input *GetJobFailuresInput) *JobObjectIterator {
+	return &JobObjectIterator{
+		ctx:    ctx,
+		client: s,
+		list: func() (io.ReadCloser, uint64, error) {
+			output, err := s.GetFailures(ctx, input)
+			if err != nil {
+				return nil, 0, err
+			}
+			return output.Items, output.ResultSetSize, nil
+		},
+		archived: path.Join("/", s.client.AccountName, "jobs", input.JobID, "fail.txt"),
+	}
+}
+
+// Next advances the iterator to the next object path. It returns false when
+// there are no more paths or an error occurred.
+func (it *JobObjectIterator) Next() bool {
+	it.current = ""
+
+	for !it.done {
+		if err := it.ctx.Err(); err != nil {
+			return it.stop(err)
+		}
+
+		if it.scanner == nil {
+			if err := it.open(); err != nil {
+				return it.stop(err)
+			}
+		}
+
+		if !it.scanner.Scan() {
+			if err := it.scanner.Err(); err != nil {
+				return it.stop(errors.Wrap(err, "unable to read job objects"))
+			}
+			return it.stop(nil)
+		}
+
+		if objectPath := strings.TrimSpace(it.scanner.Text()); objectPath != "" {
+			it.current = objectPath
+			return true
+		}
+	}
+
+	return false
+}
+
+func (it *JobObjectIterator) open() error {
+	body, resultSetSize, err := it.list()
+	if tt.IsResourceNotFoundError(err) {
+		output, archivedErr := it.objects().Get(it.ctx, &GetObjectInput{ObjectPath: it.archived})
+		if archivedErr != nil {
+			return err
+		}
+		body, resultSetSize, err = output.ObjectReader, 0, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	it.body = body
+	it.resultSetSize = resultSetSize
+	it.scanner = bufio.NewScanner(body)
+
+	return nil
+}
+
+func (it *JobObjectIterator) objects() *ObjectsClient {
+	return &ObjectsClient{it.client.client}
+}
+
+func (it *JobObjectIterator) stop(err error) bool {
+	it.err = err
+	it.Close()
+	return false
+}
+
+// Path returns the object path the iterator currently points at.
+func (it *JobObjectIterator) Path() string {
+	return it.current
+}
+
+// Open retrieves the object the iterator currently points at. If error is
+// nil, it is your responsibility to close the io.ReadCloser named
+// ObjectReader in the output.
+func (it *JobObjectIterator) Open() (*GetObjectOutput, error) {
+	if it.current == "" {
+		return nil, errors.New("unable to open job object: iterator is not positioned on an object")
+	}
+	return it.objects().Get(it.ctx, &GetObjectInput{ObjectPath: it.current})
+}
+
+// ResultSetSize returns the number of objects as reported by Manta for a
+// live listing, or 0 before the first call to Next.
+func (it *JobObjectIterator) ResultSetSize() uint64 {
+	return it.resultSetSize
+}
+
+// Err returns the error which stopped the iterator, if any.
+func (it *JobObjectIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator and releases the underlying response body.
+func (it *JobObjectIterator) Close() error {
+	it.done = true
+	if it.body != nil {
+		it.body.Close()
+		it.body = nil
+	}
+	it.scanner = nil
+	return nil
+}
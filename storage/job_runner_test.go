@@ -0,0 +1,207 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package storage_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joyent/triton-go/v2/storage"
+	"github.com/joyent/triton-go/v2/testutils"
+)
+
+const testJobID = "d2b5b5e3-1f1e-4a36-8c2b-6b5f0bd7b7a1"
+
+var testJobPath = path.Join("/", accountURL, "jobs", testJobID)
+
+func textResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestJobBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *storage.JobBuilder
+		err     string
+	}{
+		{"valid", storage.NewJobBuilder("rollup").Map("grep -c ERROR").Memory(1024).Disk(16).Reduce("sort").ReducerCount(4), ""},
+		{"no phases", storage.NewJobBuilder("rollup"), "missing phases"},
+		{"option before phase", storage.NewJobBuilder("rollup").Memory(1024).Map("wc"), "memory set before adding a phase"},
+		{"missing exec", storage.NewJobBuilder("rollup").Map(" "), "missing exec"},
+		{"memory", storage.NewJobBuilder("rollup").Map("wc").Memory(1000), "invalid memory 1000 MB"},
+		{"disk", storage.NewJobBuilder("rollup").Map("wc").Disk(3), "invalid disk 3 GB"},
+		{"map reducer count", storage.NewJobBuilder("rollup").Map("wc").ReducerCount(2), "only valid for reduce phases"},
+		{"reducer count", storage.NewJobBuilder("rollup").Reduce("wc").ReducerCount(1025), "invalid reducer count 1025"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := tt.builder.Build()
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(input.Phases) != 2 || input.Phases[0].Memory != 1024 || input.Phases[1].ReducerCount != 4 {
+					t.Errorf("unexpected phases: %+v", input.Phases)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q: got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestRunJob(t *testing.T) {
+	defer testutils.DeactivateClient()
+
+	var mu sync.Mutex
+	var requests []string
+	record := func(req *http.Request) (*http.Response, error) {
+		var body []byte
+		if req.Body != nil {
+			body, _ = ioutil.ReadAll(req.Body)
+		}
+		mu.Lock()
+		requests = append(requests, strings.TrimPrefix(req.URL.Path, testJobPath+"/live/")+" "+string(body))
+		mu.Unlock()
+		return &http.Response{StatusCode: http.StatusNoContent, Header: http.Header{}, Body: http.NoBody}, nil
+	}
+
+	testutils.RegisterResponder("POST", path.Join("/", accountURL, "jobs"), func(req *http.Request) (*http.Response, error) {
+		header := http.Header{}
+		header.Set("Location", testJobPath)
+		return &http.Response{StatusCode: http.StatusCreated, Header: header, Body: http.NoBody}, nil
+	})
+	testutils.RegisterResponder("POST", testJobPath+"/live/in", record)
+	testutils.RegisterResponder("POST", testJobPath+"/live/in/end", record)
+
+	job, err := storage.NewJobBuilder("rollup").Map("wc").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := make(chan string, 3)
+	inputs <- "/testing/stor/logs/a.log"
+	inputs <- "/testing/stor/logs/b.log"
+	inputs <- "/testing/stor/logs/c.log"
+	close(inputs)
+
+	output, err := MockStorageClient().Jobs().Run(context.Background(), &storage.RunJobInput{
+		Job:       job,
+		Inputs:    inputs,
+		BatchSize: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.JobID != testJobID {
+		t.Errorf("expected job %s: got %s", testJobID, output.JobID)
+	}
+
+	expected := "in /testing/stor/logs/a.log\n/testing/stor/logs/b.log,in /testing/stor/logs/c.log,in/end "
+	if strings.Join(requests, ",") != expected {
+		t.Errorf("expected requests %q: got %q", expected, requests)
+	}
+}
+
+func TestWaitForJob(t *testing.T) {
+	defer testutils.DeactivateClient()
+
+	polls := 0
+	testutils.RegisterResponder("GET", testJobPath+"/live/status", func(req *http.Request) (*http.Response, error) {
+		polls++
+		state := storage.JobStateRunning
+		if polls == 3 {
+			state = storage.JobStateDone
+		}
+		return jsonResponse(http.StatusOK, `{"id":"`+testJobID+`","state":"`+state+`","stats":{"outputs":2}}`), nil
+	})
+
+	job, err := MockStorageClient().Jobs().Wait(context.Background(), &storage.WaitForJobInput{
+		JobID:        testJobID,
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 || job.State != storage.JobStateDone || job.Stats.Outputs != 2 {
+		t.Errorf("expected the job to be done after 3 polls: got %+v after %d", job, polls)
+	}
+}
+
+func TestJobObjects(t *testing.T) {
+	t.Run("outputs", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		testutils.RegisterResponder("GET", testJobPath+"/live/out", func(req *http.Request) (*http.Response, error) {
+			return textResponse(http.StatusOK, "/testing/jobs/out/0\n/testing/jobs/out/1\n"), nil
+		})
+		for i, content := range []string{"12", "30"} {
+			content := content
+			testutils.RegisterResponder("GET", "/testing/jobs/out/"+string(rune('0'+i)), func(req *http.Request) (*http.Response, error) {
+				return textResponse(http.StatusOK, content), nil
+			})
+		}
+
+		it := MockStorageClient().Jobs().Outputs(context.Background(), &storage.GetJobOutputInput{JobID: testJobID})
+		var contents []string
+		for it.Next() {
+			object, err := it.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := ioutil.ReadAll(object.ObjectReader)
+			object.ObjectReader.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			contents = append(contents, it.Path()+"="+string(data))
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "/testing/jobs/out/0=12,/testing/jobs/out/1=30"
+		if strings.Join(contents, ",") != expected {
+			t.Errorf("expected %s: got %v", expected, contents)
+		}
+	})
+
+	t.Run("archived failures", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		testutils.RegisterResponder("GET", testJobPath+"/live/fail", func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusNotFound, `{"code":"ResourceNotFound","message":"job was archived"}`), nil
+		})
+		testutils.RegisterResponder("GET", testJobPath+"/fail.txt", func(req *http.Request) (*http.Response, error) {
+			return textResponse(http.StatusOK, "/testing/stor/logs/b.log\n"), nil
+		})
+
+		it := MockStorageClient().Jobs().Failures(context.Background(), &storage.GetJobFailuresInput{JobID: testJobID})
+		var paths []string
+		for it.Next() {
+			paths = append(paths, it.Path())
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(paths, ",") != "/testing/stor/logs/b.log" {
+			t.Errorf("expected the archived failure: got %v", paths)
+		}
+	})
+}
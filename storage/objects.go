@@ -11,10 +11,12 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -106,9 +108,10 @@ func (s *ObjectsClient) GetInfo(ctx context.Context, input *GetInfoInput) (*GetI
 	}
 
 	reqInput := client.RequestInput{
-		Method:  http.MethodHead,
-		Path:    string(absPath),
-		Headers: headers,
+		Operation: "storage.objects.get_info",
+		Method:    http.MethodHead,
+		Path:      string(absPath),
+		Headers:   headers,
 	}
 	_, respHeaders, err := s.client.ExecuteRequestStorage(ctx, reqInput)
 	if err != nil {
@@ -188,9 +191,10 @@ func (s *ObjectsClient) Get(ctx context.Context, input *GetObjectInput) (*GetObj
 	}
 
 	reqInput := client.RequestInput{
-		Method:  http.MethodGet,
-		Path:    string(absPath),
-		Headers: headers,
+		Operation: "storage.objects.get",
+		Method:    http.MethodGet,
+		Path:      string(absPath),
+		Headers:   headers,
 	}
 	respBody, respHeaders, err := s.client.ExecuteRequestStorage(ctx, reqInput)
 	if err != nil {
@@ -241,9 +245,10 @@ func (s *ObjectsClient) Delete(ctx context.Context, input *DeleteObjectInput) er
 	}
 
 	reqInput := client.RequestInput{
-		Method:  http.MethodDelete,
-		Path:    string(absPath),
-		Headers: headers,
+		Operation: "storage.objects.delete",
+		Method:    http.MethodDelete,
+		Path:      string(absPath),
+		Headers:   headers,
 	}
 	respBody, _, err := s.client.ExecuteRequestStorage(ctx, reqInput)
 	if respBody != nil {
@@ -326,10 +331,11 @@ func (s *ObjectsClient) PutMetadata(ctx context.Context, input *PutObjectMetadat
 	}
 
 	reqInput := client.RequestInput{
-		Method:  http.MethodPut,
-		Path:    string(absPath),
-		Query:   query,
-		Headers: headers,
+		Operation: "storage.objects.put_metadata",
+		Method:    http.MethodPut,
+		Path:      string(absPath),
+		Query:     query,
+		Headers:   headers,
 	}
 	respBody, _, err := s.client.ExecuteRequestStorage(ctx, reqInput)
 	if respBody != nil {
@@ -441,10 +447,11 @@ func putObject(c ObjectsClient, ctx context.Context, input *PutObjectInput, absP
 	}
 
 	reqInput := client.RequestNoEncodeInput{
-		Method:  http.MethodPut,
-		Path:    string(absPath),
-		Headers: headers,
-		Body:    input.ObjectReader,
+		Operation: "storage.objects.put",
+		Method:    http.MethodPut,
+		Path:      string(absPath),
+		Headers:   headers,
+		Body:      input.ObjectReader,
 	}
 	respBody, _, err := c.client.ExecuteRequestNoEncode(ctx, reqInput)
 	if respBody != nil {
@@ -487,10 +494,11 @@ func createDirectory(c ObjectsClient, ctx context.Context, absPath _AbsCleanPath
 
 func abortMpu(c ObjectsClient, ctx context.Context, input *AbortMpuInput) error {
 	reqInput := client.RequestInput{
-		Method:  http.MethodPost,
-		Path:    input.PartsDirectoryPath + "/abort",
-		Headers: &http.Header{},
-		Body:    nil,
+		Operation: "storage.objects.abort_multipart_upload",
+		Method:    http.MethodPost,
+		Path:      input.PartsDirectoryPath + "/abort",
+		Headers:   &http.Header{},
+		Body:      nil,
 	}
 	respBody, _, err := c.client.ExecuteRequestStorage(ctx, reqInput)
 	if err != nil {
@@ -510,25 +518,18 @@ func commitMpu(c ObjectsClient, ctx context.Context, input *CommitMpuInput) erro
 		headers.Set(key, value)
 	}
 
-	// The mpu directory prefix length is derived from the final character
-	// in the mpu identifier which we'll call P. The mpu prefix itself is
-	// the first P characters of the mpu identifier. In order to derive the
-	// correct directory structure we need to parse this information from
-	// the mpu identifier
-	id := input.Id
-	idLength := len(id)
-	prefixLen, err := strconv.Atoi(id[idLength-1 : idLength])
+	partsPath, err := mpuPartsPath(c.client.AccountName, input.Id)
 	if err != nil {
 		return errors.Wrap(err, "unable to commit mpu due to invalid mpu prefix length")
 	}
-	prefix := id[:prefixLen]
-	partPath := "/" + c.client.AccountName + "/uploads/" + prefix + "/" + input.Id + "/commit"
+	partPath := partsPath + "/commit"
 
 	reqInput := client.RequestInput{
-		Method:  http.MethodPost,
-		Path:    partPath,
-		Headers: headers,
-		Body:    input.Body,
+		Operation: "storage.objects.commit_multipart_upload",
+		Method:    http.MethodPost,
+		Path:      partPath,
+		Headers:   headers,
+		Body:      input.Body,
 	}
 	respBody, _, err := c.client.ExecuteRequestStorage(ctx, reqInput)
 	if err != nil {
@@ -574,10 +575,11 @@ func createMpu(c ObjectsClient, ctx context.Context, input *CreateMpuInput) (*Cr
 
 	input.Body.ObjectPath = string(absPath)
 	reqInput := client.RequestInput{
-		Method:  http.MethodPost,
-		Path:    "/" + c.client.AccountName + "/uploads",
-		Headers: headers,
-		Body:    input.Body,
+		Operation: "storage.objects.create_multipart_upload",
+		Method:    http.MethodPost,
+		Path:      "/" + c.client.AccountName + "/uploads",
+		Headers:   headers,
+		Body:      input.Body,
 	}
 	respBody, _, err := c.client.ExecuteRequestStorage(ctx, reqInput)
 	if err != nil {
@@ -600,11 +602,15 @@ func getMpu(c ObjectsClient, ctx context.Context, input *GetMpuInput) (*GetMpuOu
 	headers := &http.Header{}
 
 	reqInput := client.RequestInput{
-		Method:  http.MethodGet,
-		Path:    input.PartsDirectoryPath + "/state",
-		Headers: headers,
+		Operation: "storage.objects.get_multipart_upload",
+		Method:    http.MethodGet,
+		Path:      input.PartsDirectoryPath + "/state",
+		Headers:   headers,
 	}
 	respBody, _, err := c.client.ExecuteRequestStorage(ctx, reqInput)
+	if respBody != nil {
+		defer respBody.Close()
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get mpu")
 	}
@@ -619,14 +625,10 @@ func getMpu(c ObjectsClient, ctx context.Context, input *GetMpuInput) (*GetMpuOu
 }
 
 func listMpuParts(c ObjectsClient, ctx context.Context, input *ListMpuPartsInput) (*ListMpuPartsOutput, error) {
-	id := input.Id
-	idLength := len(id)
-	prefixLen, err := strconv.Atoi(id[idLength-1 : idLength])
+	partPath, err := mpuPartsPath(c.client.AccountName, input.Id)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to upload part")
+		return nil, errors.Wrap(err, "unable to list mpu parts due to invalid mpu prefix length")
 	}
-	prefix := id[:prefixLen]
-	partPath := "/" + c.client.AccountName + "/uploads/" + prefix + "/" + input.Id + "/"
 	listDirInput := ListDirectoryInput{
 		DirectoryName: partPath,
 	}
@@ -635,19 +637,29 @@ func listMpuParts(c ObjectsClient, ctx context.Context, input *ListMpuPartsInput
 		client: c.client,
 	}
 
-	listDirOutput, err := dirClient.List(ctx, &listDirInput)
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to list mpu parts")
-	}
-
+	// Parts are named after their number, which sorts them as strings in
+	// directory listings.
 	var parts []ListMpuPart
-	for num, part := range listDirOutput.Entries {
+	entries := dirClient.ListAll(ctx, &listDirInput)
+	defer entries.Close()
+	for entries.Next() {
+		part := entries.Entry()
+		num, err := strconv.Atoi(part.Name)
+		if err != nil {
+			continue
+		}
 		parts = append(parts, ListMpuPart{
 			ETag:       part.ETag,
 			PartNumber: num,
 			Size:       int64(part.Size),
 		})
 	}
+	if err := entries.Err(); err != nil {
+		return nil, errors.Wrap(err, "unable to list mpu parts")
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
 
 	listMpuPartsOutput := &ListMpuPartsOutput{
 		Parts: parts,
@@ -666,26 +678,18 @@ func uploadPart(c ObjectsClient, ctx context.Context, input *UploadPartInput) (*
 		headers.Set("Content-MD5", input.ContentMD5)
 	}
 
-	// The mpu directory prefix length is derived from the final character
-	// in the mpu identifier which we'll call P. The mpu prefix itself is
-	// the first P characters of the mpu identifier. In order to derive the
-	// correct directory structure we need to parse this information from
-	// the mpu identifier
-	id := input.Id
-	idLength := len(id)
-	partNum := strconv.FormatUint(input.PartNum, 10)
-	prefixLen, err := strconv.Atoi(id[idLength-1 : idLength])
+	partsPath, err := mpuPartsPath(c.client.AccountName, input.Id)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to upload part due to invalid mpu prefix length")
 	}
-	prefix := id[:prefixLen]
-	partPath := "/" + c.client.AccountName + "/uploads/" + prefix + "/" + input.Id + "/" + partNum
+	partPath := partsPath + "/" + strconv.FormatUint(input.PartNum, 10)
 
 	reqInput := client.RequestNoEncodeInput{
-		Method:  http.MethodPut,
-		Path:    partPath,
-		Headers: headers,
-		Body:    input.ObjectReader,
+		Operation: "storage.objects.upload_part",
+		Method:    http.MethodPut,
+		Path:      partPath,
+		Headers:   headers,
+		Body:      input.ObjectReader,
 	}
 	respBody, respHeader, err := c.client.ExecuteRequestNoEncode(ctx, reqInput)
 	if respBody != nil {
@@ -701,6 +705,28 @@ func uploadPart(c ObjectsClient, ctx context.Context, input *UploadPartInput) (*
 	return uploadPartOutput, nil
 }
 
+// mpuPartsPath returns the path of the parts directory of the multipart
+// upload with the given id.
+//
+// The mpu directory prefix length is derived from the final character in the
+// mpu identifier which we'll call P. The mpu prefix itself is the first P
+// characters of the mpu identifier. In order to derive the correct directory
+// structure we need to parse this information from the mpu identifier.
+func mpuPartsPath(accountName, id string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("empty mpu identifier")
+	}
+	prefixLen, err := strconv.Atoi(id[len(id)-1:])
+	if err != nil {
+		return "", err
+	}
+	if prefixLen > len(id) {
+		return "", fmt.Errorf("mpu prefix length %d exceeds identifier %q", prefixLen, id)
+	}
+
+	return "/" + accountName + "/uploads/" + id[:prefixLen] + "/" + id, nil
+}
+
 func checkDirectoryTreeExists(c ObjectsClient, ctx context.Context, absPath _AbsCleanPath) (bool, error) {
 	exists, err := c.IsDir(ctx, string(absPath))
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/joyent/triton-go/v2/client"
 	"github.com/pkg/errors"
 )
 
@@ -59,14 +60,22 @@ func (output *SignURLOutput) SignedURL(scheme string) string {
 
 // SignURL creates a time-expiring URL that can be shared with others.
 // This is useful to generate HTML links, for example.
+//
+// The URL is signed with the client's active signer, i.e. the last of its
+// authorizers Manta accepted.
 func (s *StorageClient) SignURL(input *SignURLInput) (*SignURLOutput, error) {
+	signer := s.Client.ActiveSigner()
+	if signer == nil {
+		return nil, client.ErrMissingSigner
+	}
+
 	output := &SignURLOutput{
 		host:       s.Client.MantaURL.Host,
 		objectPath: fmt.Sprintf("/%s%s", s.Client.AccountName, input.ObjectPath),
 		Method:     input.Method,
-		Algorithm:  strings.ToUpper(s.Client.Authorizers[0].DefaultAlgorithm()),
+		Algorithm:  strings.ToUpper(signer.DefaultAlgorithm()),
 		Expires:    strconv.FormatInt(time.Now().Add(input.ValidityPeriod).Unix(), 10),
-		KeyID:      path.Join("/", s.Client.AccountName, "keys", s.Client.Authorizers[0].KeyFingerprint()),
+		KeyID:      path.Join("/", s.Client.AccountName, "keys", signer.KeyFingerprint()),
 	}
 
 	toSign := bytes.Buffer{}
@@ -80,7 +89,7 @@ func (s *StorageClient) SignURL(input *SignURLInput) (*SignURLOutput, error) {
 	query.Set("keyId", output.KeyID)
 	toSign.WriteString(query.Encode())
 
-	signature, _, err := s.Client.Authorizers[0].SignRaw(toSign.String())
+	signature, _, err := signer.SignRaw(toSign.String())
 	if err != nil {
 		return nil, errors.Wrapf(err, "error signing string")
 	}
@@ -38,9 +38,10 @@ func (s *SnapLinksClient) Put(ctx context.Context, input *PutSnapLinkInput) erro
 	headers.Set("Accept-Version", "application/json, */*")
 
 	reqInput := client.RequestInput{
-		Method:  http.MethodPut,
-		Path:    linkPath,
-		Headers: headers,
+		Operation: "storage.snap_links.put",
+		Method:    http.MethodPut,
+		Path:      linkPath,
+		Headers:   headers,
 	}
 	respBody, _, err := s.client.ExecuteRequestStorage(ctx, reqInput)
 	if respBody != nil {
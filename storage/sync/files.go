@@ -0,0 +1,364 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package sync
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	stdsync "sync"
+	"time"
+
+	tt "github.com/joyent/triton-go/v2/errors"
+	"github.com/joyent/triton-go/v2/storage"
+	"github.com/pkg/errors"
+)
+
+// file is a file or directory found at either end of a sync.
+type file struct {
+	dir   bool
+	size  int64
+	mtime time.Time
+
+	// localPath is the path of local files.
+	localPath string
+}
+
+// listLocal returns the files below root by their slash separated relative
+// paths. It returns nil if root does not exist.
+func listLocal(root string, exclude []string) (map[string]*file, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	files := map[string]*file{}
+	err := filepath.Walk(root, func(localPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if localPath == root {
+			if !info.IsDir() {
+				return errors.Errorf("%s is not a directory", root)
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, localPath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if excluded(rel, exclude) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+
+		files[rel] = &file{
+			dir:       info.IsDir(),
+			size:      info.Size(),
+			mtime:     info.ModTime(),
+			localPath: localPath,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list local files")
+	}
+
+	return files, nil
+}
+
+// listRemote returns the entries below root by their relative paths. It
+// returns nil if root does not exist and allowMissing is set.
+func (s *Syncer) listRemote(ctx context.Context, root string, exclude []string, allowMissing bool) (map[string]*file, error) {
+	var mu stdsync.Mutex
+	var missing bool
+	files := map[string]*file{}
+
+	prefix := strings.TrimSuffix(root, "/") + "/"
+	err := s.client.Dir().Walk(ctx, root, func(entryPath string, entry *storage.DirectoryEntry, err error) error {
+		if err != nil {
+			if entry == nil && allowMissing && tt.IsResourceNotFoundError(err) {
+				missing = true
+				return nil
+			}
+			return err
+		}
+
+		rel := strings.TrimPrefix(entryPath, prefix)
+		if excluded(rel, exclude) {
+			if entry.Type == "directory" {
+				return storage.SkipDir
+			}
+			return nil
+		}
+		if entry.Type != "directory" && entry.Type != "object" {
+			return nil
+		}
+
+		mu.Lock()
+		files[rel] = &file{
+			dir:   entry.Type == "directory",
+			size:  int64(entry.Size),
+			mtime: entry.ModifiedTime,
+		}
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list objects")
+	}
+	if missing {
+		return nil, nil
+	}
+
+	return files, nil
+}
+
+// fileMD5 returns the base64 encoded MD5 of the file at localPath, as found
+// in the Content-MD5 of an object.
+func fileMD5(localPath string) (string, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
+
+// execute makes the actions of plan. Deletions of files and transfers run
+// concurrently; directories are created and deleted one by one, in order.
+func (r *syncRun) execute(ctx context.Context, plan []Action) (*Output, error) {
+	concurrency := r.input.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	var batch []Action
+	for i, action := range plan {
+		if !action.Dir {
+			batch = append(batch, action)
+			if i < len(plan)-1 && !plan[i+1].Dir && plan[i+1].Type == action.Type {
+				continue
+			}
+			if err := r.executeConcurrently(ctx, batch, concurrency); err != nil {
+				return nil, err
+			}
+			batch = nil
+			continue
+		}
+
+		if err := r.executeAction(ctx, action); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Output{
+		Actions:   plan,
+		Unchanged: r.unchanged,
+	}, nil
+}
+
+// executeConcurrently makes actions using concurrency goroutines, stopping
+// at the first error.
+func (r *syncRun) executeConcurrently(ctx context.Context, actions []Action, concurrency int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan Action)
+	var errOnce stdsync.Once
+	var firstErr error
+
+	var wg stdsync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for action := range jobs {
+				if err := r.executeAction(ctx, action); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for _, action := range actions {
+		select {
+		case jobs <- action:
+			continue
+		case <-ctx.Done():
+		}
+		break
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// executeAction makes a single action, unless this is a dry run.
+func (r *syncRun) executeAction(ctx context.Context, action Action) error {
+	if !r.input.DryRun {
+		var err error
+		switch action.Type {
+		case ActionUpload:
+			err = r.upload(ctx, action.Path)
+		case ActionDownload:
+			err = r.download(ctx, action.Path)
+		case ActionMkdir:
+			err = r.mkdir(ctx, action.Path)
+		case ActionDelete:
+			err = r.delete(ctx, action)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unable to %s %s", action.Type, r.displayPath(action))
+		}
+	}
+
+	r.done(action)
+	return nil
+}
+
+// displayPath names the path of action at its destination.
+func (r *syncRun) displayPath(action Action) string {
+	if r.isDownload() {
+		return r.localPath(action.Path)
+	}
+	return r.remotePath(action.Path)
+}
+
+func (r *syncRun) isDownload() bool {
+	return r.transfer == ActionDownload
+}
+
+func (r *syncRun) localPath(rel string) string {
+	return filepath.Join(r.input.LocalPath, filepath.FromSlash(rel))
+}
+
+// upload uploads the file at rel, in parts if it is large.
+func (r *syncRun) upload(ctx context.Context, rel string) error {
+	f, err := os.Open(r.localPath(rel))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() > storage.DefaultUploadPartSize {
+		_, err := r.syncer.client.Uploader().Upload(ctx, &storage.UploadInput{
+			ObjectPath:    r.remotePath(rel),
+			ObjectReader:  f,
+			ContentLength: uint64(info.Size()),
+		})
+		return err
+	}
+
+	return r.syncer.client.Objects().Put(ctx, &storage.PutObjectInput{
+		ObjectPath:    r.remotePath(rel),
+		ObjectReader:  f,
+		ContentLength: uint64(info.Size()),
+	})
+}
+
+// download downloads the object at rel to a temporary file, which replaces
+// the local file once complete.
+func (r *syncRun) download(ctx context.Context, rel string) error {
+	localPath := r.localPath(rel)
+	f, err := os.Create(localPath + ".msync")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	output, err := r.syncer.client.Downloader().Download(ctx, &storage.DownloadInput{
+		ObjectPath: r.remotePath(rel),
+		Writer:     f,
+	})
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// The modification time of the listing is preferred to Last-Modified,
+	// which is only precise to the second, so that the next sync compares
+	// the file with the same time.
+	mtime := output.LastModified
+	if src := r.src[rel]; src != nil {
+		mtime = src.mtime
+	}
+	if err := os.Chtimes(f.Name(), mtime, mtime); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), localPath)
+}
+
+// mkdir creates the directory at rel, or the root of the destination if rel
+// is empty.
+func (r *syncRun) mkdir(ctx context.Context, rel string) error {
+	if r.isDownload() {
+		return os.MkdirAll(r.localPath(rel), 0755)
+	}
+	return mkdirAll(ctx, r.syncer.client, r.remotePath(rel))
+}
+
+// mkdirAll creates the Manta directory dir and its missing parents.
+func mkdirAll(ctx context.Context, client *storage.StorageClient, dir string) error {
+	err := client.Dir().Put(ctx, &storage.PutDirectoryInput{DirectoryName: dir})
+	if tt.IsDirectoryDoesNotExistError(err) && path.Dir(dir) != dir {
+		if err := mkdirAll(ctx, client, path.Dir(dir)); err != nil {
+			return err
+		}
+		err = client.Dir().Put(ctx, &storage.PutDirectoryInput{DirectoryName: dir})
+	}
+	return err
+}
+
+// delete removes the file or directory of action from the destination.
+func (r *syncRun) delete(ctx context.Context, action Action) error {
+	if r.isDownload() {
+		return os.Remove(r.localPath(action.Path))
+	}
+	if action.Dir {
+		return r.syncer.client.Dir().Delete(ctx, &storage.DeleteDirectoryInput{
+			DirectoryName: r.remotePath(action.Path),
+		})
+	}
+	return r.syncer.client.Objects().Delete(ctx, &storage.DeleteObjectInput{
+		ObjectPath: r.remotePath(action.Path),
+	})
+}
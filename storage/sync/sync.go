@@ -0,0 +1,308 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+// Package sync mirrors directory trees between the local file system and
+// Manta, in the manner of msync. Only files which differ in size,
+// modification time or, optionally, checksum are transferred.
+package sync
+
+import (
+	"context"
+	"path"
+	"sort"
+	stdsync "sync"
+	"time"
+
+	"github.com/joyent/triton-go/v2/storage"
+	"github.com/pkg/errors"
+)
+
+const defaultConcurrency = 4
+
+// ActionType is the kind of change made by a sync.
+type ActionType string
+
+const (
+	ActionUpload   ActionType = "upload"
+	ActionDownload ActionType = "download"
+	ActionMkdir    ActionType = "mkdir"
+	ActionDelete   ActionType = "delete"
+)
+
+// Action is a change made, or planned in a dry run, to the destination of a
+// sync.
+type Action struct {
+	Type ActionType
+
+	// Path is the slash separated path of the file or directory relative
+	// to the roots of the sync.
+	Path string
+
+	// Size is the number of bytes transferred.
+	Size int64
+
+	// Dir reports whether Path is a directory.
+	Dir bool
+}
+
+// Input represents parameters to an Upload or Download operation.
+type Input struct {
+	// LocalPath is the local directory.
+	LocalPath string
+
+	// RemotePath is the Manta directory, e.g. /stor/backups.
+	RemotePath string
+
+	// Delete removes the files and directories of the destination which do
+	// not exist at the source.
+	Delete bool
+
+	// DryRun plans the actions without making them.
+	DryRun bool
+
+	// Exclude holds path.Match patterns. Files and directories whose name or
+	// relative path matches one are neither transferred nor deleted.
+	Exclude []string
+
+	// Checksum compares the MD5 of files of the same size rather than their
+	// modification times.
+	Checksum bool
+
+	// Concurrency is the number of files transferred at once, 4 if unset.
+	Concurrency int
+
+	// OnAction is called after every action made or planned. Calls are not
+	// concurrent.
+	OnAction func(Action)
+}
+
+// Output contains the outputs of an Upload or Download operation.
+type Output struct {
+	// Actions lists the actions made, or planned in a dry run, in the order
+	// they were planned.
+	Actions []Action
+
+	// Unchanged counts the files found identical at both ends.
+	Unchanged int
+}
+
+// Syncer syncs directory trees using a storage client.
+type Syncer struct {
+	client *storage.StorageClient
+}
+
+// New returns a Syncer transferring files with client.
+func New(client *storage.StorageClient) *Syncer {
+	return &Syncer{client: client}
+}
+
+// Upload makes input.RemotePath a copy of input.LocalPath. Directories
+// missing in Manta are created, including RemotePath itself.
+func (s *Syncer) Upload(ctx context.Context, input *Input) (*Output, error) {
+	if err := checkPatterns(input.Exclude); err != nil {
+		return nil, err
+	}
+
+	local, err := listLocal(input.LocalPath, input.Exclude)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to sync")
+	}
+	if local == nil {
+		return nil, errors.Errorf("unable to sync: %s does not exist", input.LocalPath)
+	}
+	remote, err := s.listRemote(ctx, input.RemotePath, input.Exclude, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to sync")
+	}
+
+	run := &syncRun{syncer: s, input: input, transfer: ActionUpload}
+	plan, err := run.plan(ctx, local, remote)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to sync")
+	}
+	if remote == nil {
+		plan = append([]Action{{Type: ActionMkdir, Dir: true}}, plan...)
+	}
+
+	return run.execute(ctx, plan)
+}
+
+// Download makes input.LocalPath a copy of input.RemotePath. LocalPath is
+// created if missing, and the modification times of downloaded files are
+// set to those of their objects.
+func (s *Syncer) Download(ctx context.Context, input *Input) (*Output, error) {
+	if err := checkPatterns(input.Exclude); err != nil {
+		return nil, err
+	}
+
+	remote, err := s.listRemote(ctx, input.RemotePath, input.Exclude, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to sync")
+	}
+	local, err := listLocal(input.LocalPath, input.Exclude)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to sync")
+	}
+
+	run := &syncRun{syncer: s, input: input, transfer: ActionDownload}
+	plan, err := run.plan(ctx, remote, local)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to sync")
+	}
+	if local == nil {
+		plan = append([]Action{{Type: ActionMkdir, Dir: true}}, plan...)
+	}
+
+	return run.execute(ctx, plan)
+}
+
+// syncRun tracks the state of a single Upload or Download operation.
+type syncRun struct {
+	syncer   *Syncer
+	input    *Input
+	transfer ActionType
+
+	// src holds the files planned from, by relative path.
+	src map[string]*file
+
+	mu        stdsync.Mutex
+	unchanged int
+}
+
+// plan returns the actions making dst a copy of src. Directories are
+// created before their contents, and deleted after them.
+func (r *syncRun) plan(ctx context.Context, src, dst map[string]*file) ([]Action, error) {
+	var creates, deletes []Action
+	r.src = src
+
+	for _, rel := range sortedPaths(src) {
+		srcFile, dstFile := src[rel], dst[rel]
+		if dstFile != nil && srcFile.dir != dstFile.dir {
+			if !r.input.Delete {
+				return nil, errors.Errorf("%s is a file at one end and a directory at the other", rel)
+			}
+			deletes = append(deletes, Action{Type: ActionDelete, Path: rel, Dir: dstFile.dir})
+			dstFile = nil
+		}
+
+		switch {
+		case srcFile.dir && dstFile == nil:
+			creates = append(creates, Action{Type: ActionMkdir, Path: rel, Dir: true})
+		case srcFile.dir:
+		case dstFile == nil:
+			creates = append(creates, Action{Type: r.transfer, Path: rel, Size: srcFile.size})
+		default:
+			changed, err := r.changed(ctx, rel, srcFile, dstFile)
+			if err != nil {
+				return nil, err
+			}
+			if changed {
+				creates = append(creates, Action{Type: r.transfer, Path: rel, Size: srcFile.size})
+			} else {
+				r.unchanged++
+			}
+		}
+	}
+
+	if r.input.Delete {
+		for _, rel := range sortedPaths(dst) {
+			if src[rel] == nil {
+				deletes = append(deletes, Action{Type: ActionDelete, Path: rel, Dir: dst[rel].dir})
+			}
+		}
+	}
+
+	// Deleting in reverse order removes the contents of a directory before
+	// the directory, as both Manta and the local file system require.
+	sort.Slice(deletes, func(i, j int) bool { return deletes[i].Path > deletes[j].Path })
+
+	return append(deletes, creates...), nil
+}
+
+// changed reports whether the file at rel differs at both ends.
+func (r *syncRun) changed(ctx context.Context, rel string, src, dst *file) (bool, error) {
+	if src.size != dst.size {
+		return true, nil
+	}
+
+	if r.input.Checksum {
+		info, err := r.syncer.client.Objects().GetInfo(ctx, &storage.GetInfoInput{
+			ObjectPath: r.remotePath(rel),
+		})
+		if err != nil {
+			return false, err
+		}
+
+		// Objects uploaded in parts have no Content-MD5, so their
+		// modification times are compared instead.
+		if info.ContentMD5 != "" {
+			local := src
+			if r.isDownload() {
+				local = dst
+			}
+			localMD5, err := fileMD5(local.localPath)
+			if err != nil {
+				return false, err
+			}
+			return info.ContentMD5 != localMD5, nil
+		}
+	}
+
+	// Objects take the time of their upload and downloaded files the time
+	// of their object, so a source newer than its copy has changed. Times
+	// are compared to the second, as some file systems store no more.
+	return src.mtime.Truncate(time.Second).After(dst.mtime.Truncate(time.Second)), nil
+}
+
+// remotePath returns the Manta path of rel.
+func (r *syncRun) remotePath(rel string) string {
+	return path.Join(r.input.RemotePath, rel)
+}
+
+// done reports an action made or planned.
+func (r *syncRun) done(action Action) {
+	if r.input.OnAction == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.input.OnAction(action)
+}
+
+func sortedPaths(files map[string]*file) []string {
+	paths := make([]string, 0, len(files))
+	for rel := range files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func checkPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "invalid exclude pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// excluded reports whether the file or directory at rel matches one of
+// patterns.
+func excluded(rel string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
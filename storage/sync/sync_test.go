@@ -0,0 +1,307 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package sync_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joyent/triton-go/v2/storage"
+	storagesync "github.com/joyent/triton-go/v2/storage/sync"
+	"github.com/joyent/triton-go/v2/testutils"
+)
+
+const accountURL = "testing"
+
+func mockStorageClient() *storage.StorageClient {
+	return &storage.StorageClient{
+		Client: testutils.NewMockClient(testutils.MockClientInput{
+			AccountName: accountURL,
+		}),
+	}
+}
+
+func listing(entries ...storage.DirectoryEntry) testutils.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		var body strings.Builder
+		for _, entry := range entries {
+			line, _ := json.Marshal(entry)
+			body.Write(line)
+			body.WriteString("\n")
+		}
+
+		header := http.Header{}
+		header.Add("Content-Type", "application/x-json-stream")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(body.String())),
+		}, nil
+	}
+}
+
+// writeFile writes a local file of the sync test and sets its modification
+// time.
+func writeFile(t *testing.T, root, rel, data string, mtime time.Time) {
+	localPath := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(localPath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(localPath, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpload(t *testing.T) {
+	uploaded := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	root, err := ioutil.TempDir("", "triton-go-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	writeFile(t, root, "a.txt", "new", uploaded)
+	writeFile(t, root, "b.txt", "same", uploaded.Add(-time.Hour))
+	writeFile(t, root, "c.txt", "resized", uploaded.Add(-time.Hour))
+	writeFile(t, root, "sub/d.txt", "new", uploaded)
+	writeFile(t, root, "scratch.tmp", "excluded", uploaded)
+
+	// register mocks /stor/backup, recording the changes made to it.
+	register := func(requests *[]string) {
+		var mu sync.Mutex
+		record := func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			*requests = append(*requests, req.Method+" "+strings.TrimPrefix(req.URL.Path, "/testing/stor/backup/"))
+			mu.Unlock()
+			return &http.Response{StatusCode: http.StatusNoContent, Header: http.Header{}, Body: http.NoBody}, nil
+		}
+
+		remote := path.Join("/", accountURL, "stor/backup")
+		testutils.RegisterResponder("GET", remote+"?limit=1024", listing(
+			storage.DirectoryEntry{Name: "b.txt", Type: "object", Size: 4, ModifiedTime: uploaded},
+			storage.DirectoryEntry{Name: "c.txt", Type: "object", Size: 4, ModifiedTime: uploaded},
+			storage.DirectoryEntry{Name: "old", Type: "directory", ModifiedTime: uploaded},
+			storage.DirectoryEntry{Name: "old.txt", Type: "object", Size: 3, ModifiedTime: uploaded},
+			storage.DirectoryEntry{Name: "keep.tmp", Type: "object", Size: 3, ModifiedTime: uploaded},
+		))
+		testutils.RegisterResponder("GET", remote+"/old?limit=1024", listing(
+			storage.DirectoryEntry{Name: "e.txt", Type: "object", Size: 3, ModifiedTime: uploaded},
+		))
+		for _, rel := range []string{"a.txt", "c.txt", "sub", "sub/d.txt"} {
+			testutils.RegisterResponder("PUT", remote+"/"+rel, record)
+		}
+		for _, rel := range []string{"old", "old.txt", "old/e.txt"} {
+			testutils.RegisterResponder("DELETE", remote+"/"+rel, record)
+		}
+	}
+
+	expected := "delete old/e.txt,delete old.txt,delete old,upload a.txt,upload c.txt,mkdir sub,upload sub/d.txt"
+
+	for _, dryRun := range []bool{true, false} {
+		name := "successful"
+		if dryRun {
+			name = "dry run"
+		}
+		t.Run(name, func(t *testing.T) {
+			defer testutils.DeactivateClient()
+			var requests []string
+			register(&requests)
+
+			output, err := storagesync.New(mockStorageClient()).Upload(context.Background(), &storagesync.Input{
+				LocalPath:  root,
+				RemotePath: "/stor/backup",
+				Delete:     true,
+				DryRun:     dryRun,
+				Exclude:    []string{"*.tmp"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actions []string
+			for _, action := range output.Actions {
+				actions = append(actions, string(action.Type)+" "+action.Path)
+			}
+			if strings.Join(actions, ",") != expected {
+				t.Errorf("expected actions %s: got %v", expected, actions)
+			}
+			if output.Unchanged != 1 {
+				t.Errorf("expected b.txt to be unchanged: got %d", output.Unchanged)
+			}
+
+			sort.Strings(requests)
+			made := "DELETE old,DELETE old.txt,DELETE old/e.txt,PUT a.txt,PUT c.txt,PUT sub,PUT sub/d.txt"
+			if dryRun {
+				made = ""
+			}
+			if strings.Join(requests, ",") != made {
+				t.Errorf("expected requests %s: got %v", made, requests)
+			}
+		})
+	}
+}
+
+func TestDownload(t *testing.T) {
+	// The listing is precise to the millisecond, Last-Modified to the
+	// second.
+	modified := time.Date(2020, 6, 1, 0, 0, 0, 500*int(time.Millisecond), time.UTC)
+	objects := map[string]string{
+		"a.txt":     "new",
+		"b.txt":     "same",
+		"sub/c.txt": "nested",
+	}
+
+	root, err := ioutil.TempDir("", "triton-go-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	writeFile(t, root, "stale.txt", "stale", modified)
+	writeFile(t, root, "scratch.tmp", "excluded", modified)
+
+	// register mocks /stor/backup, recording the objects downloaded. No
+	// object has a Content-MD5, as if all were uploaded in parts.
+	register := func(downloads *[]string) {
+		var mu sync.Mutex
+		remote := path.Join("/", accountURL, "stor/backup")
+		testutils.RegisterResponder("GET", remote+"?limit=1024", listing(
+			storage.DirectoryEntry{Name: "a.txt", Type: "object", Size: 3, ModifiedTime: modified},
+			storage.DirectoryEntry{Name: "b.txt", Type: "object", Size: 4, ModifiedTime: modified},
+			storage.DirectoryEntry{Name: "sub", Type: "directory", ModifiedTime: modified},
+		))
+		testutils.RegisterResponder("GET", remote+"/sub?limit=1024", listing(
+			storage.DirectoryEntry{Name: "c.txt", Type: "object", Size: 6, ModifiedTime: modified},
+		))
+
+		for rel, data := range objects {
+			rel, data := rel, data
+			header := func() http.Header {
+				header := http.Header{}
+				header.Set("Content-Length", strconv.Itoa(len(data)))
+				header.Set("Etag", "etag-"+rel)
+				header.Set("Last-Modified", modified.Format(http.TimeFormat))
+				return header
+			}
+			testutils.RegisterResponder("HEAD", remote+"/"+rel, func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusOK, Header: header(), Body: http.NoBody}, nil
+			})
+			testutils.RegisterResponder("GET", remote+"/"+rel, func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				*downloads = append(*downloads, rel)
+				mu.Unlock()
+				return &http.Response{
+					StatusCode: http.StatusPartialContent,
+					Header:     header(),
+					Body:       ioutil.NopCloser(strings.NewReader(data)),
+				}, nil
+			})
+		}
+	}
+
+	sync := func(t *testing.T, input *storagesync.Input) (actions, downloads []string, unchanged int) {
+		defer testutils.DeactivateClient()
+		register(&downloads)
+
+		input.LocalPath = root
+		input.RemotePath = "/stor/backup"
+		input.Delete = true
+		input.Exclude = []string{"*.tmp"}
+		output, err := storagesync.New(mockStorageClient()).Download(context.Background(), input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, action := range output.Actions {
+			actions = append(actions, string(action.Type)+" "+action.Path)
+		}
+		sort.Strings(downloads)
+		return actions, downloads, output.Unchanged
+	}
+
+	expected := "delete stale.txt,download a.txt,download b.txt,mkdir sub,download sub/c.txt"
+
+	t.Run("dry run", func(t *testing.T) {
+		actions, downloads, _ := sync(t, &storagesync.Input{DryRun: true})
+		if strings.Join(actions, ",") != expected {
+			t.Errorf("expected actions %s: got %v", expected, actions)
+		}
+		if len(downloads) != 0 {
+			t.Errorf("expected no downloads: got %v", downloads)
+		}
+		if _, err := os.Stat(filepath.Join(root, "stale.txt")); err != nil {
+			t.Errorf("expected stale.txt to be kept: %v", err)
+		}
+	})
+
+	t.Run("successful", func(t *testing.T) {
+		actions, downloads, _ := sync(t, &storagesync.Input{})
+		if strings.Join(actions, ",") != expected {
+			t.Errorf("expected actions %s: got %v", expected, actions)
+		}
+		if strings.Join(downloads, ",") != "a.txt,b.txt,sub/c.txt" {
+			t.Errorf("unexpected downloads: %v", downloads)
+		}
+
+		for rel, data := range objects {
+			localPath := filepath.Join(root, filepath.FromSlash(rel))
+			b, err := ioutil.ReadFile(localPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != data {
+				t.Errorf("expected %s to contain %q: got %q", rel, data, b)
+			}
+			if info, _ := os.Stat(localPath); !info.ModTime().Equal(modified) {
+				t.Errorf("expected %s to be modified at %v: got %v", rel, modified, info.ModTime())
+			}
+		}
+		if _, err := os.Stat(filepath.Join(root, "stale.txt")); !os.IsNotExist(err) {
+			t.Errorf("expected stale.txt to be deleted: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "scratch.tmp")); err != nil {
+			t.Errorf("expected scratch.tmp to be excluded: %v", err)
+		}
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		actions, downloads, unchanged := sync(t, &storagesync.Input{})
+		if len(actions) != 0 || len(downloads) != 0 {
+			t.Errorf("expected nothing to be transferred: got %v", actions)
+		}
+		if unchanged != 3 {
+			t.Errorf("expected 3 unchanged files: got %d", unchanged)
+		}
+	})
+
+	t.Run("unchanged checksum", func(t *testing.T) {
+		actions, downloads, unchanged := sync(t, &storagesync.Input{Checksum: true})
+		if len(actions) != 0 || len(downloads) != 0 {
+			t.Errorf("expected nothing to be transferred: got %v", actions)
+		}
+		if unchanged != 3 {
+			t.Errorf("expected 3 unchanged files: got %d", unchanged)
+		}
+	})
+}
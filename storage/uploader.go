@@ -0,0 +1,483 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/joyent/triton-go/v2/client"
+	tt "github.com/joyent/triton-go/v2/errors"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultUploadPartSize is the part size used by an Uploader unless
+	// configured otherwise. It allows objects of up to 625GiB.
+	DefaultUploadPartSize = 64 << 20
+
+	// MinUploadPartSize is the smallest size Manta accepts for any part but
+	// the last one of a multipart upload.
+	MinUploadPartSize = 5 << 20
+
+	// MaxUploadParts is the largest number of parts of a multipart upload.
+	MaxUploadParts = 10000
+
+	defaultUploadConcurrency     = 4
+	defaultUploadMaxPartAttempts = 3
+	partRetryBackoff             = 250 * time.Millisecond
+)
+
+// Uploader uploads large objects with Manta's multipart upload API. It splits
+// a stream into parts, uploads several of them concurrently and commits the
+// upload once all parts are stored. The zero values of its fields select the
+// defaults.
+type Uploader struct {
+	client *client.Client
+
+	// PartSize is the size of every part but the last. It is raised when the
+	// size of the object is known and would need more than MaxUploadParts
+	// parts.
+	PartSize int64
+
+	// Concurrency is the number of parts uploaded at once. The Uploader
+	// buffers up to Concurrency+1 parts in memory.
+	Concurrency int
+
+	// MaxPartAttempts is the number of attempts made to upload a part before
+	// the upload fails.
+	MaxPartAttempts int
+
+	// Progress is called after every part stored. Calls are not concurrent.
+	Progress func(UploadProgress)
+}
+
+// UploadInput represents parameters to an Upload operation.
+type UploadInput struct {
+	ObjectPath string
+
+	// ObjectReader is read up to EOF. When it is an *os.File its size is
+	// used to choose the part size.
+	ObjectReader io.Reader
+
+	// ContentLength is the size of the object, if known.
+	ContentLength uint64
+
+	// Headers are stored with the object, e.g. Content-Type.
+	Headers         map[string]string
+	DurabilityLevel uint64
+	ForceInsert     bool //Force the creation of the directory tree
+
+	// UploadID resumes the interrupted multipart upload with this
+	// identifier. ObjectReader must provide the same data from the start
+	// again. Parts already stored with the size and Content-MD5 of the
+	// data read are skipped, any others are uploaded again.
+	UploadID string
+
+	// LeavePartsOnError keeps the parts of a failed upload, so that it can
+	// be resumed, rather than aborting it.
+	LeavePartsOnError bool
+}
+
+// UploadOutput contains the outputs of an Upload operation.
+type UploadOutput struct {
+	UploadID string
+	Parts    int
+	Size     int64
+}
+
+// UploadProgress reports the progress of an Upload operation.
+type UploadProgress struct {
+	// UploadID identifies the multipart upload, in order to resume it.
+	UploadID string
+
+	// PartNum is the part which was just stored or found already stored.
+	PartNum int
+
+	// PartsDone and BytesDone count the parts and bytes stored so far.
+	PartsDone int
+	BytesDone int64
+
+	// TotalBytes is the size of the object, or zero if it is unknown.
+	TotalBytes int64
+}
+
+// Uploader returns an Uploader with default settings, used for uploading
+// large objects in parts.
+func (c *StorageClient) Uploader() *Uploader {
+	return &Uploader{client: c.Client}
+}
+
+// uploadPartJob is a part read from the input, waiting to be uploaded.
+// stored is the part of the same number and size found when resuming.
+type uploadPartJob struct {
+	num    int
+	buf    []byte
+	stored *ListMpuPart
+}
+
+// upload tracks the state of a single Upload operation.
+type upload struct {
+	*Uploader
+	objects  ObjectsClient
+	id       string
+	total    int64
+	existing map[int]ListMpuPart
+
+	mu        sync.Mutex
+	etags     map[int]string
+	partsDone int
+	bytesDone int64
+}
+
+// Upload uploads input.ObjectReader to input.ObjectPath in parts. A failed
+// upload is aborted, unless input.LeavePartsOnError is set or ctx was
+// cancelled. If it is not aborted, the returned error is an *UploadError
+// carrying the identifier to resume the upload with.
+func (u *Uploader) Upload(ctx context.Context, input *UploadInput) (*UploadOutput, error) {
+	up := &upload{
+		Uploader: u.withDefaults(),
+		objects:  ObjectsClient{u.client},
+		total:    int64(input.ContentLength),
+		etags:    map[int]string{},
+	}
+	if up.total == 0 {
+		if f, ok := input.ObjectReader.(*os.File); ok {
+			if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+				up.total = info.Size()
+			}
+		}
+	}
+	if up.total > 0 {
+		if minPartSize := (up.total + MaxUploadParts - 1) / MaxUploadParts; up.PartSize < minPartSize {
+			up.PartSize = minPartSize
+		}
+	}
+
+	if err := up.start(ctx, input); err != nil {
+		return nil, err
+	}
+
+	parts, size, err := up.uploadParts(ctx, input.ObjectReader)
+	if err == nil {
+		err = up.commit(ctx, parts)
+	}
+	if err != nil {
+		if ctx.Err() == nil && !input.LeavePartsOnError {
+			partsPath, _ := mpuPartsPath(u.client.AccountName, up.id)
+			if abortErr := up.objects.AbortMultipartUpload(ctx, &AbortMpuInput{PartsDirectoryPath: partsPath}); abortErr == nil {
+				return nil, errors.Wrap(err, "unable to upload object")
+			}
+		}
+		return nil, &UploadError{UploadID: up.id, Err: err}
+	}
+
+	return &UploadOutput{
+		UploadID: up.id,
+		Parts:    parts,
+		Size:     size,
+	}, nil
+}
+
+func (u *Uploader) withDefaults() *Uploader {
+	withDefaults := *u
+	if withDefaults.PartSize <= 0 {
+		withDefaults.PartSize = DefaultUploadPartSize
+	}
+	if withDefaults.Concurrency <= 0 {
+		withDefaults.Concurrency = defaultUploadConcurrency
+	}
+	if withDefaults.MaxPartAttempts <= 0 {
+		withDefaults.MaxPartAttempts = defaultUploadMaxPartAttempts
+	}
+	return &withDefaults
+}
+
+// start creates the multipart upload, or looks up the parts already stored
+// for the one being resumed.
+func (up *upload) start(ctx context.Context, input *UploadInput) error {
+	objectPath := absFileInput(up.client.AccountName, input.ObjectPath)
+
+	if input.UploadID == "" {
+		created, err := up.objects.CreateMultipartUpload(ctx, &CreateMpuInput{
+			Body: CreateMpuBody{
+				ObjectPath: input.ObjectPath,
+				Headers:    input.Headers,
+			},
+			ContentLength:   input.ContentLength,
+			DurabilityLevel: input.DurabilityLevel,
+			ForceInsert:     input.ForceInsert,
+		})
+		if err != nil {
+			return errors.Wrap(err, "unable to upload object")
+		}
+		up.id = created.Id
+		return nil
+	}
+
+	up.id = input.UploadID
+	partsPath, err := mpuPartsPath(up.client.AccountName, up.id)
+	if err != nil {
+		return errors.Wrap(err, "unable to resume upload due to invalid mpu prefix length")
+	}
+	state, err := up.objects.GetMultipartUpload(ctx, &GetMpuInput{PartsDirectoryPath: partsPath})
+	if err != nil {
+		return errors.Wrap(err, "unable to resume upload")
+	}
+	if state.State != "created" {
+		return fmt.Errorf("unable to resume upload %s in state %q", up.id, state.State)
+	}
+	if state.TargetObject != "" && state.TargetObject != string(objectPath) {
+		return fmt.Errorf("unable to resume upload %s of %s to %s", up.id, state.TargetObject, objectPath)
+	}
+
+	listed, err := up.objects.ListMultipartUploadParts(ctx, &ListMpuPartsInput{Id: up.id})
+	if err != nil {
+		return errors.Wrap(err, "unable to resume upload")
+	}
+	up.existing = make(map[int]ListMpuPart, len(listed.Parts))
+	for _, part := range listed.Parts {
+		up.existing[part.PartNumber] = part
+	}
+
+	return nil
+}
+
+// uploadParts reads r in parts and uploads them concurrently. It returns the
+// number of parts and the size of the object.
+func (up *upload) uploadParts(ctx context.Context, r io.Reader) (int, int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	buffers := make(chan []byte, up.Concurrency+1)
+	for i := 0; i < cap(buffers); i++ {
+		buffers <- nil
+	}
+	jobs := make(chan uploadPartJob)
+
+	var errOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < up.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if err := up.uploadPart(ctx, job); err != nil {
+					fail(err)
+				}
+				buffers <- job.buf
+			}
+		}()
+	}
+
+	var num int
+	var size int64
+	for ; ; num++ {
+		var buf []byte
+		select {
+		case buf = <-buffers:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if buf == nil {
+			buf = make([]byte, up.PartSize)
+		}
+		buf = buf[:cap(buf)]
+
+		n, err := io.ReadFull(r, buf)
+		if err == io.EOF && num > 0 {
+			break
+		}
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			fail(errors.Wrap(err, "unable to read object"))
+			break
+		}
+		if num == MaxUploadParts {
+			fail(fmt.Errorf("object exceeds %d parts of %d bytes", MaxUploadParts, up.PartSize))
+			break
+		}
+		size += int64(n)
+
+		job := uploadPartJob{num: num, buf: buf[:n]}
+		if part, ok := up.existing[num]; ok && part.Size == int64(n) {
+			job.stored = &part
+		}
+		jobs <- job
+
+		if n < len(buf) {
+			num++
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return 0, 0, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	return num, size, nil
+}
+
+// uploadPart uploads a part, retrying failed attempts, unless the same
+// data is already stored.
+func (up *upload) uploadPart(ctx context.Context, job uploadPartJob) error {
+	sum := md5.Sum(job.buf)
+	contentMD5 := base64.StdEncoding.EncodeToString(sum[:])
+
+	if job.stored != nil && up.partStored(ctx, job.num, contentMD5) {
+		up.done(job.num, job.stored.ETag, len(job.buf))
+		return nil
+	}
+
+	var err error
+	for attempt := 1; attempt <= up.MaxPartAttempts; attempt++ {
+		if attempt > 1 {
+			if err := sleepPartBackoff(ctx, attempt); err != nil {
+				return err
+			}
+		}
+
+		var output *UploadPartOutput
+		output, err = up.objects.UploadPart(ctx, &UploadPartInput{
+			Id:           up.id,
+			PartNum:      uint64(job.num),
+			ContentMD5:   contentMD5,
+			ObjectReader: bytes.NewReader(job.buf),
+		})
+		if err == nil {
+			up.done(job.num, output.Part, len(job.buf))
+			return nil
+		}
+		if ctx.Err() != nil || !isRetryablePartError(err) {
+			break
+		}
+	}
+
+	return errors.Wrapf(err, "unable to upload part %d", job.num)
+}
+
+// partStored reports whether the part num of the upload has the
+// Content-MD5 contentMD5. Parts which cannot be checked are uploaded again.
+func (up *upload) partStored(ctx context.Context, num int, contentMD5 string) bool {
+	partsPath, err := mpuPartsPath(up.client.AccountName, up.id)
+	if err != nil {
+		return false
+	}
+	info, err := up.objects.GetInfo(ctx, &GetInfoInput{
+		ObjectPath: path.Join(partsPath, strconv.Itoa(num)),
+	})
+	return err == nil && info.ContentMD5 == contentMD5
+}
+
+func sleepPartBackoff(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(time.Duration(attempt-1) * partRetryBackoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRetryablePartError reports whether uploading a part again may succeed
+// after err. Connection failures and server errors are retried, as are
+// parts which were corrupted in transit.
+func isRetryablePartError(err error) bool {
+	var apiErr *tt.APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+
+	return apiErr.StatusCode >= http.StatusInternalServerError ||
+		apiErr.StatusCode == http.StatusRequestTimeout ||
+		apiErr.Temporary() ||
+		tt.IsContentMD5MismatchError(err)
+}
+
+// done records that a part is stored and reports the progress.
+func (up *upload) done(num int, etag string, size int) {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+
+	up.etags[num] = etag
+	up.partsDone++
+	up.bytesDone += int64(size)
+
+	if up.Progress != nil {
+		up.Progress(UploadProgress{
+			UploadID:   up.id,
+			PartNum:    num,
+			PartsDone:  up.partsDone,
+			BytesDone:  up.bytesDone,
+			TotalBytes: up.total,
+		})
+	}
+}
+
+// commit commits the first parts of the upload.
+func (up *upload) commit(ctx context.Context, parts int) error {
+	etags := make([]string, parts)
+	for i := range etags {
+		etags[i] = up.etags[i]
+	}
+
+	err := up.objects.CommitMultipartUpload(ctx, &CommitMpuInput{
+		Id:   up.id,
+		Body: CommitMpuBody{Parts: etags},
+	})
+	return errors.Wrap(err, "unable to upload object")
+}
+
+// UploadError is returned by Upload when a multipart upload failed but was
+// not aborted, so that it can be resumed with UploadInput.UploadID.
+type UploadError struct {
+	UploadID string
+	Err      error
+}
+
+// Error implements interface Error on the UploadError type.
+func (e *UploadError) Error() string {
+	return fmt.Sprintf("unable to upload object (resume with upload id %s): %s", e.UploadID, e.Err)
+}
+
+// Unwrap makes UploadError compatible with errors.Is and errors.As.
+func (e *UploadError) Unwrap() error {
+	return e.Err
+}
+
+// Cause makes UploadError compatible with errors.Cause.
+func (e *UploadError) Cause() error {
+	return e.Err
+}
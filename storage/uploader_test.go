@@ -0,0 +1,253 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package storage_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/joyent/triton-go/v2/storage"
+	"github.com/joyent/triton-go/v2/testutils"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	uploadID         = "d2fc3b06-8c2b-4d36-a06c-8f0b5d1e3a72"
+	uploadObjectPath = "/stor/dump.sql"
+	uploadData       = "0123456789"
+)
+
+var uploadPartsPath = path.Join("/", accountURL, "uploads", "d2", uploadID)
+
+// mockUpload records the requests of a multipart upload.
+type mockUpload struct {
+	mu        sync.Mutex
+	parts     map[int]string
+	attempts  map[int]int
+	committed []string
+	aborted   bool
+}
+
+func newMockUpload() *mockUpload {
+	return &mockUpload{parts: map[int]string{}, attempts: map[int]int{}}
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// register registers responders for the upload of a uploadData sized
+// object in parts of 4 bytes. fail decides the status of part uploads.
+func (m *mockUpload) register(t *testing.T, fail func(num, attempt int) int) {
+	testutils.RegisterResponder("POST", path.Join("/", accountURL, "uploads"), func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusCreated, `{"id":"`+uploadID+`","partsDirectory":"`+uploadPartsPath+`"}`), nil
+	})
+
+	for num := 0; num < 3; num++ {
+		num := num
+		testutils.RegisterResponder("PUT", uploadPartsPath+"/"+strconv.Itoa(num), func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			sum := md5.Sum(body)
+			if req.Header.Get("Content-MD5") != base64.StdEncoding.EncodeToString(sum[:]) {
+				t.Errorf("expected Content-MD5 of part %d to match its body", num)
+			}
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.attempts[num]++
+			if status := fail(num, m.attempts[num]); status != 0 {
+				return jsonResponse(status, `{"code":"InternalError","message":"failed"}`), nil
+			}
+			m.parts[num] = string(body)
+
+			resp := jsonResponse(http.StatusNoContent, "")
+			resp.Header.Set("Etag", "etag-"+strconv.Itoa(num))
+			return resp, nil
+		})
+	}
+
+	testutils.RegisterResponder("POST", uploadPartsPath+"/commit", func(req *http.Request) (*http.Response, error) {
+		var body storage.CommitMpuBody
+		json.NewDecoder(req.Body).Decode(&body)
+		m.mu.Lock()
+		m.committed = body.Parts
+		m.mu.Unlock()
+		return jsonResponse(http.StatusCreated, ""), nil
+	})
+
+	testutils.RegisterResponder("POST", uploadPartsPath+"/abort", func(req *http.Request) (*http.Response, error) {
+		m.mu.Lock()
+		m.aborted = true
+		m.mu.Unlock()
+		return jsonResponse(http.StatusNoContent, ""), nil
+	})
+}
+
+func TestUpload(t *testing.T) {
+	newUploader := func(progress func(storage.UploadProgress)) *storage.Uploader {
+		uploader := MockStorageClient().Uploader()
+		uploader.PartSize = 4
+		uploader.Concurrency = 2
+		uploader.Progress = progress
+		return uploader
+	}
+
+	t.Run("successful", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockUpload()
+		m.register(t, func(num, attempt int) int { return 0 })
+
+		var progress []storage.UploadProgress
+		output, err := newUploader(func(p storage.UploadProgress) {
+			progress = append(progress, p)
+		}).Upload(context.Background(), &storage.UploadInput{
+			ObjectPath:   uploadObjectPath,
+			ObjectReader: strings.NewReader(uploadData),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if output.UploadID != uploadID || output.Parts != 3 || output.Size != int64(len(uploadData)) {
+			t.Errorf("unexpected output: %+v", output)
+		}
+		if m.parts[0] != "0123" || m.parts[1] != "4567" || m.parts[2] != "89" {
+			t.Errorf("expected the object to be split in parts of 4 bytes: got %v", m.parts)
+		}
+		if strings.Join(m.committed, ",") != "etag-0,etag-1,etag-2" {
+			t.Errorf("expected the etags of all parts to be committed in order: got %v", m.committed)
+		}
+		if len(progress) != 3 || progress[2].PartsDone != 3 || progress[2].BytesDone != int64(len(uploadData)) {
+			t.Errorf("expected progress for every part: got %+v", progress)
+		}
+	})
+
+	t.Run("retries failed parts", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockUpload()
+		m.register(t, func(num, attempt int) int {
+			if num == 1 && attempt == 1 {
+				return http.StatusInternalServerError
+			}
+			return 0
+		})
+
+		if _, err := newUploader(nil).Upload(context.Background(), &storage.UploadInput{
+			ObjectPath:   uploadObjectPath,
+			ObjectReader: strings.NewReader(uploadData),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if m.attempts[1] != 2 || len(m.committed) != 3 {
+			t.Errorf("expected part 1 to be uploaded twice and committed: got %d attempts, %v", m.attempts[1], m.committed)
+		}
+	})
+
+	t.Run("aborts on failure", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockUpload()
+		m.register(t, func(num, attempt int) int {
+			if num == 2 {
+				return http.StatusForbidden
+			}
+			return 0
+		})
+
+		_, err := newUploader(nil).Upload(context.Background(), &storage.UploadInput{
+			ObjectPath:   uploadObjectPath,
+			ObjectReader: strings.NewReader(uploadData),
+		})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if !m.aborted || m.committed != nil {
+			t.Errorf("expected the upload to be aborted")
+		}
+		if m.attempts[2] != 1 {
+			t.Errorf("expected rejected part not to be retried: got %d attempts", m.attempts[2])
+		}
+	})
+
+	t.Run("leaves parts on failure", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockUpload()
+		m.register(t, func(num, attempt int) int {
+			if num == 2 {
+				return http.StatusForbidden
+			}
+			return 0
+		})
+
+		_, err := newUploader(nil).Upload(context.Background(), &storage.UploadInput{
+			ObjectPath:        uploadObjectPath,
+			ObjectReader:      strings.NewReader(uploadData),
+			LeavePartsOnError: true,
+		})
+		var uploadErr *storage.UploadError
+		if !pkgerrors.As(err, &uploadErr) || uploadErr.UploadID != uploadID {
+			t.Fatalf("expected an UploadError to resume %s: got %v", uploadID, err)
+		}
+		if m.aborted {
+			t.Error("expected the upload not to be aborted")
+		}
+	})
+
+	t.Run("resumes", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		m := newMockUpload()
+		m.register(t, func(num, attempt int) int { return 0 })
+		testutils.RegisterResponder("GET", uploadPartsPath+"/state", func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusOK, `{"id":"`+uploadID+`","state":"created","targetObject":"/testing/stor/dump.sql"}`), nil
+		})
+		testutils.RegisterResponder("GET", uploadPartsPath+"?limit=1024", func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusOK, `{"name":"0","type":"object","size":4,"etag":"stored-0"}
+{"name":"1","type":"object","size":4,"etag":"changed-1"}
+{"name":"2","type":"object","size":1,"etag":"truncated-2"}
+`), nil
+		})
+		for num, data := range []string{"0123", "4568"} {
+			sum := md5.Sum([]byte(data))
+			contentMD5 := base64.StdEncoding.EncodeToString(sum[:])
+			testutils.RegisterResponder("HEAD", uploadPartsPath+"/"+strconv.Itoa(num), func(req *http.Request) (*http.Response, error) {
+				resp := jsonResponse(http.StatusOK, "")
+				resp.Header.Set("Content-MD5", contentMD5)
+				return resp, nil
+			})
+		}
+
+		output, err := newUploader(nil).Upload(context.Background(), &storage.UploadInput{
+			ObjectPath:   uploadObjectPath,
+			ObjectReader: strings.NewReader(uploadData),
+			UploadID:     uploadID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if output.Parts != 3 || m.attempts[0] != 0 || m.attempts[1] != 1 || m.attempts[2] != 1 {
+			t.Errorf("expected only the changed and truncated parts to be uploaded: got %v", m.attempts)
+		}
+		if strings.Join(m.committed, ",") != "stored-0,etag-1,etag-2" {
+			t.Errorf("expected stored and uploaded parts to be committed: got %v", m.committed)
+		}
+	})
+}
@@ -0,0 +1,254 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package storage
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultWalkConcurrency = 8
+
+// SkipDir is returned by a WalkFunc to skip the directory it was called for,
+// or the remaining entries of the directory containing an object.
+var SkipDir = errors.New("skip this directory")
+
+// WalkFunc is called by Walk and Find for every entry found. entryPath is the
+// path of the entry below the root passed in, e.g. /stor/logs/2020/01.
+//
+// If a directory cannot be listed, the function is called with its path,
+// its entry (nil for the root) and the error. Returning nil continues the
+// walk without the directory, any other error stops it. Returning SkipDir
+// for a directory entry prevents the walk from descending into it, for an
+// object it skips the entries of its directory not yet visited, as in
+// filepath.Walk. SkipDir is never returned by Walk or Find.
+type WalkFunc func(entryPath string, entry *DirectoryEntry, err error) error
+
+// FindInput represents parameters to a Find operation. The filters select
+// the entries passed to the WalkFunc; they do not prevent the walk from
+// descending into directories.
+type FindInput struct {
+	// Root is the directory to walk. It is not passed to the WalkFunc.
+	Root string
+
+	// Concurrency is the number of directories listed at once, 8 if unset.
+	Concurrency int
+
+	// Limit is the number of entries requested per page of a directory
+	// listing, 1024 if unset.
+	Limit uint64
+
+	// MinDepth and MaxDepth limit the depth of the entries found. The
+	// entries of Root are at depth 1. A MaxDepth of 0 means no limit.
+	MinDepth int
+	MaxDepth int
+
+	// Name is a path.Match pattern the name of the entries must match.
+	Name string
+
+	// Type is the type of the entries, "object" or "directory".
+	Type string
+
+	// MinSize and MaxSize limit the size of the entries. When set, only
+	// objects are found. A MaxSize of 0 means no limit.
+	MinSize uint64
+	MaxSize uint64
+
+	// ModifiedAfter and ModifiedBefore limit the modification time of the
+	// entries.
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
+}
+
+// Walk calls fn for every entry below root, listing directories
+// concurrently and following the paging of large directories. The order in
+// which entries are visited is unspecified and fn is called from several
+// goroutines at once, so it must be safe for concurrent use.
+func (s *DirectoryClient) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	return s.Find(ctx, &FindInput{Root: root}, fn)
+}
+
+// Find walks input.Root like Walk, calling fn only for the entries matching
+// the filters of input, in the manner of mfind.
+func (s *DirectoryClient) Find(ctx context.Context, input *FindInput, fn WalkFunc) error {
+	if input.Name != "" {
+		if _, err := path.Match(input.Name, ""); err != nil {
+			return errors.Wrapf(err, "unable to find entries named %q", input.Name)
+		}
+	}
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultWalkConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := &walker{
+		client: s,
+		input:  input,
+		fn:     fn,
+		cancel: cancel,
+		queue:  []walkDir{{path: input.Root}},
+	}
+	w.cond = sync.NewCond(&w.mu)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work(ctx)
+		}()
+	}
+	wg.Wait()
+
+	if w.err != nil {
+		return w.err
+	}
+	return ctx.Err()
+}
+
+// walkDir is a directory waiting to be listed.
+type walkDir struct {
+	path  string
+	entry *DirectoryEntry
+	depth int
+}
+
+// walker shares the directories left to list between the workers of a
+// Find operation.
+type walker struct {
+	client *DirectoryClient
+	input  *FindInput
+	fn     WalkFunc
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []walkDir
+	active int
+	err    error
+}
+
+// work lists directories until none are left or the walk failed.
+func (w *walker) work(ctx context.Context) {
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && w.active > 0 && w.err == nil {
+			w.cond.Wait()
+		}
+		if len(w.queue) == 0 || w.err != nil {
+			w.cond.Broadcast()
+			w.mu.Unlock()
+			return
+		}
+
+		// Taking the most recently found directory first keeps the queue
+		// short in wide trees.
+		dir := w.queue[len(w.queue)-1]
+		w.queue = w.queue[:len(w.queue)-1]
+		w.active++
+		w.mu.Unlock()
+
+		err := w.list(ctx, dir)
+
+		w.mu.Lock()
+		w.active--
+		if err != nil && w.err == nil {
+			w.err = err
+			w.cancel()
+		}
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	}
+}
+
+// list calls fn for the entries of dir and queues its subdirectories.
+func (w *walker) list(ctx context.Context, dir walkDir) error {
+	iter := w.client.ListAll(ctx, &ListDirectoryInput{
+		DirectoryName: dir.path,
+		Limit:         w.input.Limit,
+	})
+	defer iter.Close()
+
+	depth := dir.depth + 1
+	for iter.Next() {
+		entry := iter.Entry()
+		entryPath := path.Join(dir.path, entry.Name)
+
+		if w.matches(entry, depth) {
+			if err := w.fn(entryPath, entry, nil); err != nil {
+				if err != SkipDir {
+					return err
+				}
+				if entry.Type != "directory" {
+					return nil
+				}
+				continue
+			}
+		}
+
+		if entry.Type == "directory" && (w.input.MaxDepth == 0 || depth < w.input.MaxDepth) {
+			w.mu.Lock()
+			w.queue = append(w.queue, walkDir{path: entryPath, entry: entry, depth: depth})
+			w.cond.Signal()
+			w.mu.Unlock()
+		}
+	}
+
+	if err := iter.Err(); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		if err := w.fn(dir.path, dir.entry, err); err != nil && err != SkipDir {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matches reports whether entry at depth passes the filters of the walk.
+func (w *walker) matches(entry *DirectoryEntry, depth int) bool {
+	input := w.input
+
+	if depth < input.MinDepth {
+		return false
+	}
+	if input.Type != "" && entry.Type != input.Type {
+		return false
+	}
+	if input.Name != "" {
+		if ok, _ := path.Match(input.Name, entry.Name); !ok {
+			return false
+		}
+	}
+	if input.MinSize > 0 || input.MaxSize > 0 {
+		if entry.Type != "object" || entry.Size < input.MinSize {
+			return false
+		}
+		if input.MaxSize > 0 && entry.Size > input.MaxSize {
+			return false
+		}
+	}
+	if !input.ModifiedAfter.IsZero() && !entry.ModifiedTime.After(input.ModifiedAfter) {
+		return false
+	}
+	if !input.ModifiedBefore.IsZero() && !entry.ModifiedTime.Before(input.ModifiedBefore) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,273 @@
+//
+// Copyright 2020 Joyent, Inc. All rights reserved.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joyent/triton-go/v2/client"
+	"github.com/joyent/triton-go/v2/errors"
+	"github.com/joyent/triton-go/v2/storage"
+	"github.com/joyent/triton-go/v2/testutils"
+)
+
+// walkTree maps directories below /stor/logs to their entries.
+var walkTree = map[string][]storage.DirectoryEntry{
+	"/stor/logs": {
+		{Name: "2019", Type: "directory"},
+		{Name: "2020", Type: "directory"},
+		{Name: "README", Type: "object", Size: 10},
+	},
+	"/stor/logs/2019": {
+		{Name: "a.log", Type: "object", Size: 100, ModifiedTime: time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "b.log", Type: "object", Size: 2000, ModifiedTime: time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "c.log", Type: "object", Size: 300, ModifiedTime: time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	"/stor/logs/2020": {
+		{Name: "01", Type: "directory"},
+		{Name: "d.txt", Type: "object", Size: 400, ModifiedTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+	},
+	"/stor/logs/2020/01": {
+		{Name: "e.log", Type: "object", Size: 500, ModifiedTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+	},
+}
+
+// registerWalkTree registers the pages of walkTree listed limit entries at
+// a time. Every page after the first repeats the last entry of the
+// previous one, like Manta does.
+func registerWalkTree(limit int) {
+	for dir, entries := range walkTree {
+		for start := 0; ; {
+			q := url.Values{}
+			q.Set("limit", strconv.Itoa(limit))
+			if start > 0 {
+				q.Set("marker", entries[start].Name)
+			}
+			u := url.URL{Path: path.Join("/", accountURL, dir), RawQuery: q.Encode()}
+
+			end := start + limit
+			if end > len(entries) {
+				end = len(entries)
+			}
+			testutils.RegisterResponder("GET", u.String(), walkPage(entries[start:end]))
+
+			if end-start < limit {
+				break
+			}
+			start = end - 1
+		}
+	}
+}
+
+func walkPage(entries []storage.DirectoryEntry) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		var body strings.Builder
+		for _, entry := range entries {
+			line, _ := json.Marshal(entry)
+			body.Write(line)
+			body.WriteString("\n")
+		}
+
+		header := http.Header{}
+		header.Add("Content-Type", "application/x-json-stream")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(body.String())),
+		}, nil
+	}
+}
+
+// collect returns a WalkFunc recording the paths of the entries visited.
+func collect(paths *[]string) storage.WalkFunc {
+	var mu sync.Mutex
+	return func(entryPath string, entry *storage.DirectoryEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		*paths = append(*paths, entryPath)
+		mu.Unlock()
+		return nil
+	}
+}
+
+func TestWalk(t *testing.T) {
+	t.Run("successful", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		registerWalkTree(1024)
+
+		var paths []string
+		if err := MockStorageClient().Dir().Walk(context.Background(), "/stor/logs", collect(&paths)); err != nil {
+			t.Fatal(err)
+		}
+
+		sort.Strings(paths)
+		expected := "/stor/logs/2019,/stor/logs/2019/a.log,/stor/logs/2019/b.log,/stor/logs/2019/c.log," +
+			"/stor/logs/2020,/stor/logs/2020/01,/stor/logs/2020/01/e.log,/stor/logs/2020/d.txt,/stor/logs/README"
+		if strings.Join(paths, ",") != expected {
+			t.Errorf("expected every entry once: got %v", paths)
+		}
+	})
+
+	t.Run("skips directories", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		registerWalkTree(1024)
+
+		var mu sync.Mutex
+		var paths []string
+		err := MockStorageClient().Dir().Walk(context.Background(), "/stor/logs", func(entryPath string, entry *storage.DirectoryEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.Name == "2020" {
+				return storage.SkipDir
+			}
+			mu.Lock()
+			paths = append(paths, entryPath)
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, p := range paths {
+			if strings.HasPrefix(p, "/stor/logs/2020") {
+				t.Errorf("expected /stor/logs/2020 to be skipped: got %v", paths)
+			}
+		}
+	})
+
+	t.Run("skips the rest of a directory", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		registerWalkTree(2)
+
+		var paths []string
+		visit := collect(&paths)
+		err := MockStorageClient().Dir().Find(context.Background(), &storage.FindInput{
+			Root:  "/stor/logs",
+			Limit: 2,
+			Type:  "object",
+		}, func(entryPath string, entry *storage.DirectoryEntry, err error) error {
+			if err := visit(entryPath, entry, err); err != nil {
+				return err
+			}
+			return storage.SkipDir
+		})
+		if err != nil {
+			t.Fatalf("expected SkipDir not to be returned: got %v", err)
+		}
+
+		sort.Strings(paths)
+		expected := "/stor/logs/2019/a.log,/stor/logs/2020/01/e.log,/stor/logs/2020/d.txt,/stor/logs/README"
+		if strings.Join(paths, ",") != expected {
+			t.Errorf("expected the first object of each directory: got %v", paths)
+		}
+	})
+
+	t.Run("requests while walking", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		registerWalkTree(1024)
+
+		var mu sync.Mutex
+		var deleted []string
+		for dir, entries := range walkTree {
+			for _, entry := range entries {
+				entryPath := path.Join(dir, entry.Name)
+				testutils.RegisterResponder("DELETE", path.Join("/", accountURL, entryPath), func(req *http.Request) (*http.Response, error) {
+					mu.Lock()
+					deleted = append(deleted, entryPath)
+					mu.Unlock()
+					return jsonResponse(http.StatusNoContent, ""), nil
+				})
+			}
+		}
+
+		// A single slot must not be held by the listings being walked.
+		sc := MockStorageClient()
+		sc.Client.MantaLimiter = client.NewLimiter(client.LimiterInput{MaxInFlight: 1})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err := sc.Dir().Walk(ctx, "/stor/logs", func(entryPath string, entry *storage.DirectoryEntry, err error) error {
+			if err != nil || entry.Type == "directory" {
+				return err
+			}
+			return sc.Objects().Delete(ctx, &storage.DeleteObjectInput{ObjectPath: entryPath})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deleted) != 6 {
+			t.Errorf("expected every object to be deleted: got %v", deleted)
+		}
+	})
+
+	t.Run("reports listing errors", func(t *testing.T) {
+		defer testutils.DeactivateClient()
+		testutils.RegisterResponder("GET", path.Join("/", accountURL, "/stor/missing")+"?limit=1024", func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusNotFound, `{"code":"ResourceNotFound","message":"/stor/missing was not found"}`), nil
+		})
+
+		err := MockStorageClient().Dir().Walk(context.Background(), "/stor/missing", collect(new([]string)))
+		if !errors.IsResourceNotFoundError(err) {
+			t.Errorf("expected ResourceNotFound error: got %v", err)
+		}
+	})
+}
+
+func TestFind(t *testing.T) {
+	find := func(t *testing.T, input *storage.FindInput) []string {
+		defer testutils.DeactivateClient()
+		registerWalkTree(2)
+
+		input.Root = "/stor/logs"
+		input.Limit = 2
+		var paths []string
+		if err := MockStorageClient().Dir().Find(context.Background(), input, collect(&paths)); err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(paths)
+		return paths
+	}
+
+	tests := []struct {
+		name     string
+		input    *storage.FindInput
+		expected string
+	}{
+		{"name", &storage.FindInput{Name: "*.log"}, "/stor/logs/2019/a.log,/stor/logs/2019/b.log,/stor/logs/2019/c.log,/stor/logs/2020/01/e.log"},
+		{"type", &storage.FindInput{Type: "directory"}, "/stor/logs/2019,/stor/logs/2020,/stor/logs/2020/01"},
+		{"depth", &storage.FindInput{MinDepth: 2, MaxDepth: 2, Type: "directory"}, "/stor/logs/2020/01"},
+		{"size", &storage.FindInput{MinSize: 300, MaxSize: 1000}, "/stor/logs/2019/c.log,/stor/logs/2020/01/e.log,/stor/logs/2020/d.txt"},
+		{"mtime", &storage.FindInput{
+			ModifiedAfter:  time.Date(2019, 5, 15, 0, 0, 0, 0, time.UTC),
+			ModifiedBefore: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, "/stor/logs/2019/b.log,/stor/logs/2019/c.log"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if paths := find(t, tt.input); strings.Join(paths, ",") != tt.expected {
+				t.Errorf("expected %s: got %v", tt.expected, paths)
+			}
+		})
+	}
+}
@@ -46,6 +46,7 @@ func AccTest(t *testing.T, c TestCase) {
 	tritonAccount := triton.GetEnv("ACCOUNT")
 	tritonKeyID := triton.GetEnv("KEY_ID")
 	tritonKeyMaterial := triton.GetEnv("KEY_MATERIAL")
+	tritonKeyPassphrase := triton.GetEnv("KEY_PASSPHRASE")
 	userName := triton.GetEnv("USER")
 	mantaURL := triton.GetEnv("MANTA_URL")
 
@@ -78,6 +79,7 @@ func AccTest(t *testing.T, c TestCase) {
 			PrivateKeyMaterial: []byte(tritonKeyMaterial),
 			AccountName:        tritonAccount,
 			Username:           userName,
+			Passphrase:         []byte(tritonKeyPassphrase),
 		}
 		signer, err = authentication.NewPrivateKeySigner(input)
 		if err != nil {
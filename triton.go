@@ -26,6 +26,13 @@ type ClientConfig struct {
 	AccountName string
 	Username    string
 	Signers     []authentication.Signer
+
+	// KeyID identifies the key to look up through the default credential
+	// chain when Signers is empty. It defaults to TRITON_KEY_ID.
+	KeyID string
+
+	// InsecureSkipTLSVerify turns off TLS certificate verification.
+	InsecureSkipTLSVerify bool
 }
 
 var envPrefixes = []string{"TRITON", "SDC"}
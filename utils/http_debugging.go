@@ -13,13 +13,56 @@ import (
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
 	"os"
 )
 
 // This file is used for tracing HTTP requests (trace).
 //
 // All HTTP requests and responses through this transport will be printed to
-// stderr.
+// stderr, with credentials redacted.
+
+// redactedHeaders are the headers whose values never appear in traces.
+var redactedHeaders = []string{
+	"Authorization",
+	"X-Auth-Token",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// redactedQueryParams are the query parameters of pre-signed URLs whose
+// values never appear in traces.
+var redactedQueryParams = []string{
+	"signature",
+}
+
+// RedactHeader returns a copy of header with the values of headers carrying
+// credentials, such as Authorization, replaced.
+func RedactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range redactedHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, "[REDACTED]")
+		}
+	}
+
+	return redacted
+}
+
+// RedactURL returns a copy of u with the signature of pre-signed URLs
+// replaced.
+func RedactURL(u *url.URL) *url.URL {
+	redacted := *u
+	query := redacted.Query()
+	for _, name := range redactedQueryParams {
+		if query.Get(name) != "" {
+			query.Set(name, "REDACTED")
+			redacted.RawQuery = query.Encode()
+		}
+	}
+
+	return &redacted
+}
 
 // TraceRoundTripper to wrap a HTTP Transport.
 func TraceRoundTripper(in http.RoundTripper) http.RoundTripper {
@@ -44,7 +87,12 @@ func (d *traceRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 }
 
 func (d *traceRoundTripper) dumpRequest(r *http.Request) {
-	dump, err := httputil.DumpRequestOut(r, true)
+	redacted := r.Clone(r.Context())
+	redacted.Header = RedactHeader(r.Header)
+	redacted.URL = RedactURL(r.URL)
+	dump, err := httputil.DumpRequestOut(redacted, true)
+	// DumpRequestOut consumed the body of the copy in place of r's.
+	r.Body = redacted.Body
 	if err != nil {
 		fmt.Fprintf(d.logger, "\n\tERROR dumping: %v\n", err)
 		return
@@ -53,7 +101,10 @@ func (d *traceRoundTripper) dumpRequest(r *http.Request) {
 }
 
 func (d *traceRoundTripper) dumpResponse(r *http.Response) {
+	header := r.Header
+	r.Header = RedactHeader(header)
 	dump, err := httputil.DumpResponse(r, true)
+	r.Header = header
 	if err != nil {
 		fmt.Fprintf(d.logger, "\n\tERROR dumping: %v\n", err)
 		return